@@ -0,0 +1,105 @@
+package controller
+
+import (
+	"event-bus-demo/application/dto"
+	"event-bus-demo/application/error"
+	"event-bus-demo/domain/service"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"net/http"
+)
+
+type AdminController interface {
+	GetEventBus(ctx *gin.Context)
+	PauseTopic(ctx *gin.Context)
+	ResumeTopic(ctx *gin.Context)
+	DrainTopic(ctx *gin.Context)
+	UpdateWorkers(ctx *gin.Context)
+	PublishEvent(ctx *gin.Context)
+}
+
+type adminController struct {
+	eventBusAdminService service.EventBusAdminService
+	controllerAdvice     error.ControllerAdvice
+}
+
+func NewAdminController(eventBusAdminService service.EventBusAdminService, controllerAdvice error.ControllerAdvice) AdminController {
+	return &adminController{
+		eventBusAdminService: eventBusAdminService,
+		controllerAdvice:     controllerAdvice,
+	}
+}
+
+func (controller *adminController) GetEventBus(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, controller.eventBusAdminService.GetEventBus(ctx.Request.Context()))
+}
+
+func (controller *adminController) PauseTopic(ctx *gin.Context) {
+	if err := controller.eventBusAdminService.PauseTopic(ctx.Request.Context(), ctx.Param("topic")); err != nil {
+		httpError := controller.controllerAdvice.TranslateError(err)
+		ctx.JSON(httpError.GetCode(), gin.H{
+			"message": httpError.GetMessage(),
+		})
+	} else {
+		ctx.JSON(http.StatusNoContent, gin.H{})
+	}
+}
+
+func (controller *adminController) ResumeTopic(ctx *gin.Context) {
+	if err := controller.eventBusAdminService.ResumeTopic(ctx.Request.Context(), ctx.Param("topic")); err != nil {
+		httpError := controller.controllerAdvice.TranslateError(err)
+		ctx.JSON(httpError.GetCode(), gin.H{
+			"message": httpError.GetMessage(),
+		})
+	} else {
+		ctx.JSON(http.StatusNoContent, gin.H{})
+	}
+}
+
+func (controller *adminController) DrainTopic(ctx *gin.Context) {
+	var request dto.DrainTopicRequest
+	if err := ctx.ShouldBindQuery(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"message": err.Error(),
+		})
+	} else if err := controller.eventBusAdminService.DrainTopic(ctx.Request.Context(), ctx.Param("topic"), request); err != nil {
+		httpError := controller.controllerAdvice.TranslateError(err)
+		ctx.JSON(httpError.GetCode(), gin.H{
+			"message": httpError.GetMessage(),
+		})
+	} else {
+		ctx.JSON(http.StatusNoContent, gin.H{})
+	}
+}
+
+func (controller *adminController) UpdateWorkers(ctx *gin.Context) {
+	var request dto.UpdateEventBusWorkersRequest
+	if err := ctx.ShouldBindWith(&request, binding.JSON); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"message": err.Error(),
+		})
+	} else if err := controller.eventBusAdminService.SetMaxWorkers(ctx.Request.Context(), request); err != nil {
+		httpError := controller.controllerAdvice.TranslateError(err)
+		ctx.JSON(httpError.GetCode(), gin.H{
+			"message": httpError.GetMessage(),
+		})
+	} else {
+		ctx.JSON(http.StatusNoContent, gin.H{})
+	}
+}
+
+func (controller *adminController) PublishEvent(ctx *gin.Context) {
+	var request dto.PublishEventRequest
+	if err := ctx.ShouldBindWith(&request, binding.JSON); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"message": err.Error(),
+		})
+	} else if err := controller.eventBusAdminService.PublishEvent(ctx.Request.Context(), request); err != nil {
+		httpError := controller.controllerAdvice.TranslateError(err)
+		ctx.JSON(httpError.GetCode(), gin.H{
+			"message": httpError.GetMessage(),
+		})
+	} else {
+		ctx.JSON(http.StatusAccepted, gin.H{})
+	}
+}
@@ -37,7 +37,7 @@ func NewCategoryController(eventBus event_sourcing.EventBus, categoryReadService
 }
 
 func (controller *categoryController) GetCategories(ctx *gin.Context) {
-	categories, err := controller.categoryReadService.GetCategories()
+	categories, err := controller.categoryReadService.GetCategories(ctx.Request.Context())
 	if err != nil {
 		log.Println(err)
 		httpError := controller.controllerAdvice.TranslateError(err)
@@ -54,12 +54,13 @@ func (controller *categoryController) GetCategoryById(ctx *gin.Context) {
 		ctx.JSON(http.StatusBadRequest, gin.H{
 			"message": "ID parameter must be a valid UUID value",
 		})
-	} else if category, err := controller.categoryReadService.GetCategoryById(model.GetCategoryByIDEvent{ID: ID}); err != nil {
+	} else if category, err := controller.categoryReadService.GetCategoryById(ctx.Request.Context(), model.GetCategoryByIDEvent{ID: ID}); err != nil {
 		httpError := controller.controllerAdvice.TranslateError(err)
 		ctx.JSON(httpError.GetCode(), gin.H{
 			"message": httpError.GetMessage(),
 		})
 	} else {
+		ctx.Header(eTagHeader, newETag(category.Version))
 		ctx.JSON(http.StatusOK, category)
 	}
 }
@@ -76,7 +77,7 @@ func (controller *categoryController) SaveCategory(ctx *gin.Context) {
 			"message": err.Error(),
 		})
 	} else {
-		controller.eventBus.Publish(request.ToEvent(ID))
+		controller.eventBus.Publish(ctx.Request.Context(), request.ToEvent(ID))
 		response := dto.CreateCategoryResponse{
 			ID: ID,
 		}
@@ -94,13 +95,26 @@ func (controller *categoryController) UpdateCategory(ctx *gin.Context) {
 		ctx.JSON(http.StatusBadRequest, gin.H{
 			"message": err.Error(),
 		})
-	} else if _, err := controller.categoryReadService.GetCategoryById(model.GetCategoryByIDEvent{ID: ID}); err != nil {
+	} else if !hasIfMatch(ctx) {
+		ctx.JSON(http.StatusPreconditionRequired, gin.H{
+			"message": "If-Match header is required, holding an ETag returned for the category",
+		})
+	} else if expectedVersion, ok := parseIfMatch(ctx); !ok {
+		ctx.JSON(http.StatusPreconditionFailed, gin.H{
+			"message": "If-Match header must hold an ETag returned for the category",
+		})
+	} else if _, err := controller.categoryReadService.GetCategoryById(ctx.Request.Context(), model.GetCategoryByIDEvent{ID: ID}); err != nil {
+		httpError := controller.controllerAdvice.TranslateError(err)
+		ctx.JSON(httpError.GetCode(), gin.H{
+			"message": httpError.GetMessage(),
+		})
+	} else if err := controller.categoryReadService.CheckCategoryVersion(ctx.Request.Context(), ID, expectedVersion); err != nil {
 		httpError := controller.controllerAdvice.TranslateError(err)
 		ctx.JSON(httpError.GetCode(), gin.H{
 			"message": httpError.GetMessage(),
 		})
 	} else {
-		controller.eventBus.Publish(request.ToEvent(ID))
+		controller.eventBus.Publish(ctx.Request.Context(), request.ToEvent(ID, expectedVersion))
 		ctx.JSON(http.StatusNoContent, gin.H{})
 	}
 }
@@ -110,11 +124,30 @@ func (controller *categoryController) DeleteCategory(ctx *gin.Context) {
 		ctx.JSON(http.StatusBadRequest, gin.H{
 			"message": "ID parameter must be a valid UUID value",
 		})
+	} else if !hasIfMatch(ctx) {
+		ctx.JSON(http.StatusPreconditionRequired, gin.H{
+			"message": "If-Match header is required, holding an ETag returned for the category",
+		})
+	} else if expectedVersion, ok := parseIfMatch(ctx); !ok {
+		ctx.JSON(http.StatusPreconditionFailed, gin.H{
+			"message": "If-Match header must hold an ETag returned for the category",
+		})
+	} else if err := controller.categoryReadService.CheckCategoryDeletion(ctx.Request.Context(), model.DeleteCategoryEvent{ID: ID}); err != nil {
+		httpError := controller.controllerAdvice.TranslateError(err)
+		ctx.JSON(httpError.GetCode(), gin.H{
+			"message": httpError.GetMessage(),
+		})
+	} else if err := controller.categoryReadService.CheckCategoryVersion(ctx.Request.Context(), ID, expectedVersion); err != nil {
+		httpError := controller.controllerAdvice.TranslateError(err)
+		ctx.JSON(httpError.GetCode(), gin.H{
+			"message": httpError.GetMessage(),
+		})
 	} else {
 		event := model.DeleteCategoryEvent{
-			ID: ID,
+			ID:              ID,
+			ExpectedVersion: expectedVersion,
 		}
-		controller.eventBus.Publish(event)
+		controller.eventBus.Publish(ctx.Request.Context(), event)
 		ctx.JSON(http.StatusNoContent, gin.H{})
 	}
 }
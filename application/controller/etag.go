@@ -0,0 +1,44 @@
+package controller
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"strconv"
+	"strings"
+)
+
+const (
+	eTagHeader    = "ETag"
+	ifMatchHeader = "If-Match"
+)
+
+// newETag returns the entity tag of a resource at the given version. Tags are strong since versions change with every
+// modification of the resource.
+func newETag(version int64) string {
+	return fmt.Sprintf(`"%d"`, version)
+}
+
+// hasIfMatch tells whether the request holds an If-Match header. It is required by the requests replacing, changing or
+// deleting a resource, answered with 428 Precondition Required without it, so that clients cannot overwrite changes
+// they did not see by omitting it. The other requests changing a resource accept a missing header as any version.
+func hasIfMatch(ctx *gin.Context) bool {
+	return strings.TrimSpace(ctx.GetHeader(ifMatchHeader)) != ""
+}
+
+// parseIfMatch returns the version required by the If-Match header of the request, nil when the header is missing or
+// matches any version. The header is not valid when it holds anything else than one strong tag returned by newETag,
+// as weak tags and lists are not supported.
+func parseIfMatch(ctx *gin.Context) (*int64, bool) {
+	header := strings.TrimSpace(ctx.GetHeader(ifMatchHeader))
+	if header == "" || header == "*" {
+		return nil, true
+	}
+	if len(header) < 2 || !strings.HasPrefix(header, `"`) || !strings.HasSuffix(header, `"`) {
+		return nil, false
+	}
+	version, err := strconv.ParseInt(header[1:len(header)-1], 10, 64)
+	if err != nil {
+		return nil, false
+	}
+	return &version, true
+}
@@ -0,0 +1,40 @@
+package controller
+
+import (
+	"event-bus-demo/application/dto"
+	"event-bus-demo/domain/service"
+	"event-bus-demo/infrastructure/health"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+type HealthController interface {
+	GetLiveness(ctx *gin.Context)
+	GetReadiness(ctx *gin.Context)
+}
+
+type healthController struct {
+	healthService service.HealthService
+}
+
+func NewHealthController(healthService service.HealthService) HealthController {
+	return &healthController{
+		healthService: healthService,
+	}
+}
+
+func (controller *healthController) GetLiveness(ctx *gin.Context) {
+	controller.writeHealth(ctx, controller.healthService.GetLiveness(ctx.Request.Context()))
+}
+
+func (controller *healthController) GetReadiness(ctx *gin.Context) {
+	controller.writeHealth(ctx, controller.healthService.GetReadiness(ctx.Request.Context()))
+}
+
+func (controller *healthController) writeHealth(ctx *gin.Context, response dto.HealthResponse) {
+	if response.Status == string(health.UpStatus) {
+		ctx.JSON(http.StatusOK, response)
+	} else {
+		ctx.JSON(http.StatusServiceUnavailable, response)
+	}
+}
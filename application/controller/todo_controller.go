@@ -11,16 +11,35 @@ import (
 	"github.com/gin-gonic/gin/binding"
 	"github.com/google/uuid"
 	"net/http"
+	"net/url"
+	"strconv"
+	"time"
 )
 
 type ToDoController interface {
 	GetToDoList(ctx *gin.Context)
 	GetToDoById(ctx *gin.Context)
+	SearchToDo(ctx *gin.Context)
 	SaveToDo(ctx *gin.Context)
 	UpdateToDo(ctx *gin.Context)
+	PatchToDo(ctx *gin.Context)
 	DeleteToDo(ctx *gin.Context)
 	RemoveCategoriesFromToDo(ctx *gin.Context)
 	AddCategoriesIntoToDo(ctx *gin.Context)
+	CompleteToDo(ctx *gin.Context)
+	ReopenToDo(ctx *gin.Context)
+	StartToDo(ctx *gin.Context)
+	CancelToDo(ctx *gin.Context)
+	RescheduleToDo(ctx *gin.Context)
+	GetToDoItems(ctx *gin.Context)
+	GetToDoOccurrences(ctx *gin.Context)
+	AddToDoItem(ctx *gin.Context)
+	UpdateToDoItem(ctx *gin.Context)
+	DeleteToDoItem(ctx *gin.Context)
+	ReorderToDoItems(ctx *gin.Context)
+	GetToDoReminders(ctx *gin.Context)
+	AddToDoReminder(ctx *gin.Context)
+	DeleteToDoReminder(ctx *gin.Context)
 }
 
 type toDoController struct {
@@ -40,23 +59,86 @@ func NewTodoController(eventBus event_sourcing.EventBus, toDoReadService service
 }
 
 func (controller *toDoController) GetToDoList(ctx *gin.Context) {
-	todoList, err := controller.toDoReadService.GetAllToDo()
-	if err != nil {
+	var request dto.GetToDoListRequest
+	if err := ctx.ShouldBindQuery(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"message": err.Error(),
+		})
+	} else if todoList, err := controller.toDoReadService.GetToDoList(ctx.Request.Context(), request.ToEvent()); err != nil {
 		httpError := controller.controllerAdvice.TranslateError(err)
 		ctx.JSON(httpError.GetCode(), gin.H{
 			"message": httpError.GetMessage(),
 		})
 	} else {
+		todoList.Links = newToDoPageLinks(ctx.Request.URL, request, todoList)
 		ctx.JSON(http.StatusOK, todoList)
 	}
 }
 
+// newToDoPageLinks builds the links to the pages around the returned one, keeping the filters and sorting of the
+// request. Links follow the pagination style of the request: by offset when an offset was given, by cursor otherwise.
+func newToDoPageLinks(requestURL *url.URL, request dto.GetToDoListRequest, response dto.GetAllToDoResponse) dto.PageLinks {
+	link := func(change func(query url.Values)) string {
+		query := requestURL.Query()
+		query.Del("cursor")
+		query.Del("offset")
+		change(query)
+		return (&url.URL{Path: requestURL.Path, RawQuery: query.Encode()}).String()
+	}
+	var links dto.PageLinks
+	if request.Offset != nil {
+		if response.HasNext {
+			links.Next = link(func(query url.Values) {
+				query.Set("offset", strconv.Itoa(*request.Offset+request.Limit))
+			})
+		}
+		if response.HasPrevious {
+			previousOffset := *request.Offset - request.Limit
+			if previousOffset < 0 {
+				previousOffset = 0
+			}
+			links.Prev = link(func(query url.Values) {
+				query.Set("offset", strconv.Itoa(previousOffset))
+			})
+		}
+		return links
+	}
+	if response.HasNext && response.NextCursor != "" {
+		links.Next = link(func(query url.Values) {
+			query.Set("cursor", response.NextCursor)
+		})
+	}
+	if response.HasPrevious && response.PreviousCursor != "" {
+		links.Prev = link(func(query url.Values) {
+			query.Set("cursor", response.PreviousCursor)
+		})
+	}
+	return links
+}
+
 func (controller *toDoController) GetToDoById(ctx *gin.Context) {
 	if ID, err := uuid.Parse(ctx.Param("id")); err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{
 			"message": "ID parameter must be a valid UUID value",
 		})
-	} else if response, err := controller.toDoReadService.GetToDo(model.GetToDoEvent{ID: ID}); err != nil {
+	} else if response, err := controller.toDoReadService.GetToDo(ctx.Request.Context(), model.GetToDoEvent{ID: ID}); err != nil {
+		httpError := controller.controllerAdvice.TranslateError(err)
+		ctx.JSON(httpError.GetCode(), gin.H{
+			"message": httpError.GetMessage(),
+		})
+	} else {
+		ctx.Header(eTagHeader, newETag(response.Version))
+		ctx.JSON(http.StatusOK, response)
+	}
+}
+
+func (controller *toDoController) SearchToDo(ctx *gin.Context) {
+	var request dto.SearchToDoRequest
+	if err := ctx.ShouldBindQuery(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"message": err.Error(),
+		})
+	} else if response, err := controller.toDoReadService.SearchToDo(ctx.Request.Context(), request.ToEvent()); err != nil {
 		httpError := controller.controllerAdvice.TranslateError(err)
 		ctx.JSON(httpError.GetCode(), gin.H{
 			"message": httpError.GetMessage(),
@@ -73,17 +155,21 @@ func (controller *toDoController) SaveToDo(ctx *gin.Context) {
 		ctx.JSON(http.StatusBadRequest, gin.H{
 			"message": err.Error(),
 		})
+	} else if recurrence, err := request.ParseRecurrence(); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"message": err.Error(),
+		})
 	} else if id, err := uuid.NewRandom(); err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{
 			"message": err.Error(),
 		})
-	} else if _, err := controller.categoryReadService.GetCategoriesByIds(request.Categories); err != nil {
+	} else if _, err := controller.categoryReadService.GetCategoriesByIds(ctx.Request.Context(), request.Categories); err != nil {
 		appErr := controller.controllerAdvice.TranslateError(err)
 		ctx.JSON(appErr.GetCode(), gin.H{
 			"message": appErr.GetMessage(),
 		})
 	} else {
-		controller.eventBus.Publish(request.ToEvent(id))
+		controller.eventBus.Publish(ctx.Request.Context(), request.ToEvent(id, recurrence))
 		response := dto.CreateToDoResponse{
 			ID: id,
 		}
@@ -101,13 +187,72 @@ func (controller *toDoController) UpdateToDo(ctx *gin.Context) {
 		ctx.JSON(http.StatusBadRequest, gin.H{
 			"message": err.Error(),
 		})
-	} else if _, err := controller.toDoReadService.GetToDo(model.GetToDoEvent{ID: ID}); err != nil {
+	} else if !hasIfMatch(ctx) {
+		ctx.JSON(http.StatusPreconditionRequired, gin.H{
+			"message": "If-Match header is required, holding an ETag returned for the ToDo",
+		})
+	} else if expectedVersion, ok := parseIfMatch(ctx); !ok {
+		ctx.JSON(http.StatusPreconditionFailed, gin.H{
+			"message": "If-Match header must hold an ETag returned for the ToDo",
+		})
+	} else if _, err := controller.toDoReadService.GetToDo(ctx.Request.Context(), model.GetToDoEvent{ID: ID}); err != nil {
+		httpError := controller.controllerAdvice.TranslateError(err)
+		ctx.JSON(httpError.GetCode(), gin.H{
+			"message": httpError.GetMessage(),
+		})
+	} else if err := controller.toDoReadService.CheckToDoVersion(ctx.Request.Context(), ID, expectedVersion); err != nil {
+		httpError := controller.controllerAdvice.TranslateError(err)
+		ctx.JSON(httpError.GetCode(), gin.H{
+			"message": httpError.GetMessage(),
+		})
+	} else {
+		controller.eventBus.Publish(ctx.Request.Context(), request.ToEvent(ID, expectedVersion))
+		ctx.JSON(http.StatusNoContent, gin.H{})
+	}
+}
+
+// PatchToDo changes the fields of a ToDo given either as a JSON merge patch or as a JSON patch, depending on the content
+// type of the request. Patches which change nothing are accepted without publishing any event.
+func (controller *toDoController) PatchToDo(ctx *gin.Context) {
+	var request dto.PatchToDoRequest
+	if ID, err := uuid.Parse(ctx.Param("id")); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"message": "ID parameter must be a valid UUID value",
+		})
+	} else if contentType := ctx.ContentType(); contentType != dto.MergePatchContentType && contentType != dto.JSONPatchContentType {
+		ctx.JSON(http.StatusUnsupportedMediaType, gin.H{
+			"message": fmt.Sprintf("content type must be %s or %s", dto.MergePatchContentType, dto.JSONPatchContentType),
+		})
+	} else if !hasIfMatch(ctx) {
+		ctx.JSON(http.StatusPreconditionRequired, gin.H{
+			"message": "If-Match header is required, holding an ETag returned for the ToDo",
+		})
+	} else if expectedVersion, ok := parseIfMatch(ctx); !ok {
+		ctx.JSON(http.StatusPreconditionFailed, gin.H{
+			"message": "If-Match header must hold an ETag returned for the ToDo",
+		})
+	} else if current, err := controller.toDoReadService.GetToDo(ctx.Request.Context(), model.GetToDoEvent{ID: ID}); err != nil {
+		httpError := controller.controllerAdvice.TranslateError(err)
+		ctx.JSON(httpError.GetCode(), gin.H{
+			"message": httpError.GetMessage(),
+		})
+	} else if err := controller.toDoReadService.CheckToDoVersion(ctx.Request.Context(), ID, expectedVersion); err != nil {
 		httpError := controller.controllerAdvice.TranslateError(err)
 		ctx.JSON(httpError.GetCode(), gin.H{
 			"message": httpError.GetMessage(),
 		})
+	} else if body, err := ctx.GetRawData(); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"message": err.Error(),
+		})
+	} else if request, err = dto.NewPatchToDoRequest(contentType, body, current); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"message": err.Error(),
+		})
+	} else if request.IsEmpty() {
+		ctx.JSON(http.StatusNoContent, gin.H{})
 	} else {
-		controller.eventBus.Publish(request.ToEvent(ID))
+		controller.eventBus.Publish(ctx.Request.Context(), request.ToEvent(ID, expectedVersion))
 		ctx.JSON(http.StatusNoContent, gin.H{})
 	}
 }
@@ -117,11 +262,25 @@ func (controller *toDoController) DeleteToDo(ctx *gin.Context) {
 		ctx.JSON(http.StatusBadRequest, gin.H{
 			"message": "ID parameter must be a valid UUID value",
 		})
+	} else if !hasIfMatch(ctx) {
+		ctx.JSON(http.StatusPreconditionRequired, gin.H{
+			"message": "If-Match header is required, holding an ETag returned for the ToDo",
+		})
+	} else if expectedVersion, ok := parseIfMatch(ctx); !ok {
+		ctx.JSON(http.StatusPreconditionFailed, gin.H{
+			"message": "If-Match header must hold an ETag returned for the ToDo",
+		})
+	} else if err := controller.toDoReadService.CheckToDoVersion(ctx.Request.Context(), ID, expectedVersion); err != nil {
+		httpError := controller.controllerAdvice.TranslateError(err)
+		ctx.JSON(httpError.GetCode(), gin.H{
+			"message": httpError.GetMessage(),
+		})
 	} else {
 		event := model.DeleteToDoEvent{
-			ID: ID,
+			ID:              ID,
+			ExpectedVersion: expectedVersion,
 		}
-		controller.eventBus.Publish(event)
+		controller.eventBus.Publish(ctx.Request.Context(), event)
 		ctx.JSON(http.StatusNoContent, gin.H{})
 	}
 }
@@ -136,7 +295,20 @@ func (controller *toDoController) RemoveCategoriesFromToDo(ctx *gin.Context) {
 		ctx.JSON(http.StatusBadRequest, gin.H{
 			"message": err.Error(),
 		})
-	} else if ok, err := controller.toDoReadService.IsToDoAlreadyInCategories(ID, request.CategoriesID); err != nil || !ok {
+	} else if !hasIfMatch(ctx) {
+		ctx.JSON(http.StatusPreconditionRequired, gin.H{
+			"message": "If-Match header is required, holding an ETag returned for the ToDo",
+		})
+	} else if expectedVersion, ok := parseIfMatch(ctx); !ok {
+		ctx.JSON(http.StatusPreconditionFailed, gin.H{
+			"message": "If-Match header must hold an ETag returned for the ToDo",
+		})
+	} else if err := controller.toDoReadService.CheckToDoVersion(ctx.Request.Context(), ID, expectedVersion); err != nil {
+		httpError := controller.controllerAdvice.TranslateError(err)
+		ctx.JSON(httpError.GetCode(), gin.H{
+			"message": httpError.GetMessage(),
+		})
+	} else if ok, err := controller.toDoReadService.IsToDoAlreadyInCategories(ctx.Request.Context(), ID, request.CategoriesID); err != nil || !ok {
 		if err != nil {
 			appErr := controller.controllerAdvice.TranslateError(err)
 			ctx.JSON(appErr.GetCode(), gin.H{
@@ -147,17 +319,18 @@ func (controller *toDoController) RemoveCategoriesFromToDo(ctx *gin.Context) {
 				"message": fmt.Sprintf("given ToDo is not registered in one or more of given categories"),
 			})
 		}
-	} else if _, err := controller.categoryReadService.GetCategoriesByIds(request.CategoriesID); err != nil {
+	} else if _, err := controller.categoryReadService.GetCategoriesByIds(ctx.Request.Context(), request.CategoriesID); err != nil {
 		appErr := controller.controllerAdvice.TranslateError(err)
 		ctx.JSON(appErr.GetCode(), gin.H{
 			"message": appErr.GetMessage(),
 		})
 	} else {
 		event := model.RemoveCategoriesFromToDoEvent{
-			ToDoID:     ID,
-			Categories: request.CategoriesID,
+			ToDoID:          ID,
+			Categories:      request.CategoriesID,
+			ExpectedVersion: expectedVersion,
 		}
-		controller.eventBus.Publish(event)
+		controller.eventBus.Publish(ctx.Request.Context(), event)
 		ctx.JSON(http.StatusNoContent, gin.H{})
 	}
 }
@@ -172,7 +345,20 @@ func (controller *toDoController) AddCategoriesIntoToDo(ctx *gin.Context) {
 		ctx.JSON(http.StatusBadRequest, gin.H{
 			"message": err.Error(),
 		})
-	} else if ok, err := controller.toDoReadService.IsToDoAlreadyInCategories(ID, request.CategoriesID); err != nil || ok {
+	} else if !hasIfMatch(ctx) {
+		ctx.JSON(http.StatusPreconditionRequired, gin.H{
+			"message": "If-Match header is required, holding an ETag returned for the ToDo",
+		})
+	} else if expectedVersion, ok := parseIfMatch(ctx); !ok {
+		ctx.JSON(http.StatusPreconditionFailed, gin.H{
+			"message": "If-Match header must hold an ETag returned for the ToDo",
+		})
+	} else if err := controller.toDoReadService.CheckToDoVersion(ctx.Request.Context(), ID, expectedVersion); err != nil {
+		httpError := controller.controllerAdvice.TranslateError(err)
+		ctx.JSON(httpError.GetCode(), gin.H{
+			"message": httpError.GetMessage(),
+		})
+	} else if ok, err := controller.toDoReadService.IsToDoAlreadyInCategories(ctx.Request.Context(), ID, request.CategoriesID); err != nil || ok {
 		if err != nil {
 			appErr := controller.controllerAdvice.TranslateError(err)
 			ctx.JSON(appErr.GetCode(), gin.H{
@@ -183,17 +369,355 @@ func (controller *toDoController) AddCategoriesIntoToDo(ctx *gin.Context) {
 				"message": fmt.Sprintf("given ToDo is already registered in one or more of given categories"),
 			})
 		}
-	} else if _, err := controller.categoryReadService.GetCategoriesByIds(request.CategoriesID); err != nil {
+	} else if _, err := controller.categoryReadService.GetCategoriesByIds(ctx.Request.Context(), request.CategoriesID); err != nil {
 		appErr := controller.controllerAdvice.TranslateError(err)
 		ctx.JSON(appErr.GetCode(), gin.H{
 			"message": appErr.GetMessage(),
 		})
 	} else {
 		event := model.AddCategoriesFromToDoEvent{
-			ToDoID:     ID,
-			Categories: request.CategoriesID,
+			ToDoID:          ID,
+			Categories:      request.CategoriesID,
+			ExpectedVersion: expectedVersion,
+		}
+		controller.eventBus.Publish(ctx.Request.Context(), event)
+		ctx.JSON(http.StatusNoContent, gin.H{})
+	}
+}
+
+func (controller *toDoController) CompleteToDo(ctx *gin.Context) {
+	controller.changeToDoStatus(ctx, model.DoneToDoStatus, func(ID uuid.UUID, expectedVersion *int64) event_sourcing.Event {
+		return model.CompleteToDoEvent{
+			ID:              ID,
+			CompletedAt:     time.Now(),
+			ExpectedVersion: expectedVersion,
+		}
+	})
+}
+
+func (controller *toDoController) ReopenToDo(ctx *gin.Context) {
+	controller.changeToDoStatus(ctx, model.OpenToDoStatus, func(ID uuid.UUID, expectedVersion *int64) event_sourcing.Event {
+		return model.ReopenToDoEvent{
+			ID:              ID,
+			ExpectedVersion: expectedVersion,
+		}
+	})
+}
+
+func (controller *toDoController) StartToDo(ctx *gin.Context) {
+	controller.changeToDoStatus(ctx, model.InProgressToDoStatus, func(ID uuid.UUID, expectedVersion *int64) event_sourcing.Event {
+		return model.StartToDoEvent{
+			ID:              ID,
+			ExpectedVersion: expectedVersion,
+		}
+	})
+}
+
+func (controller *toDoController) CancelToDo(ctx *gin.Context) {
+	controller.changeToDoStatus(ctx, model.CancelledToDoStatus, func(ID uuid.UUID, expectedVersion *int64) event_sourcing.Event {
+		return model.CancelToDoEvent{
+			ID:              ID,
+			ExpectedVersion: expectedVersion,
+		}
+	})
+}
+
+// changeToDoStatus publishes the event built by newEvent once the ToDo is known to be allowed to move to the status,
+// answering with a conflict otherwise.
+func (controller *toDoController) changeToDoStatus(ctx *gin.Context, status model.ToDoStatus, newEvent func(ID uuid.UUID, expectedVersion *int64) event_sourcing.Event) {
+	if ID, err := uuid.Parse(ctx.Param("id")); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"message": "ID parameter must be a valid UUID value",
+		})
+	} else if expectedVersion, ok := parseIfMatch(ctx); !ok {
+		ctx.JSON(http.StatusPreconditionFailed, gin.H{
+			"message": "If-Match header must hold an ETag returned for the ToDo",
+		})
+	} else if err := controller.toDoReadService.CheckToDoStatusChange(ctx.Request.Context(), ID, status, expectedVersion); err != nil {
+		httpError := controller.controllerAdvice.TranslateError(err)
+		ctx.JSON(httpError.GetCode(), gin.H{
+			"message": httpError.GetMessage(),
+		})
+	} else {
+		controller.eventBus.Publish(ctx.Request.Context(), newEvent(ID, expectedVersion))
+		ctx.JSON(http.StatusNoContent, gin.H{})
+	}
+}
+
+func (controller *toDoController) RescheduleToDo(ctx *gin.Context) {
+	var request dto.RescheduleToDoRequest
+	if ID, err := uuid.Parse(ctx.Param("id")); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"message": "ID parameter must be a valid UUID value",
+		})
+	} else if err := ctx.ShouldBindWith(&request, binding.JSON); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"message": err.Error(),
+		})
+	} else if expectedVersion, ok := parseIfMatch(ctx); !ok {
+		ctx.JSON(http.StatusPreconditionFailed, gin.H{
+			"message": "If-Match header must hold an ETag returned for the ToDo",
+		})
+	} else if err := controller.toDoReadService.CheckToDoReschedule(ctx.Request.Context(), ID, expectedVersion); err != nil {
+		httpError := controller.controllerAdvice.TranslateError(err)
+		ctx.JSON(httpError.GetCode(), gin.H{
+			"message": httpError.GetMessage(),
+		})
+	} else {
+		controller.eventBus.Publish(ctx.Request.Context(), request.ToEvent(ID, expectedVersion))
+		ctx.JSON(http.StatusNoContent, gin.H{})
+	}
+}
+
+func (controller *toDoController) GetToDoItems(ctx *gin.Context) {
+	if ID, err := uuid.Parse(ctx.Param("id")); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"message": "ID parameter must be a valid UUID value",
+		})
+	} else if response, err := controller.toDoReadService.GetToDoItems(ctx.Request.Context(), model.GetToDoEvent{ID: ID}); err != nil {
+		httpError := controller.controllerAdvice.TranslateError(err)
+		ctx.JSON(httpError.GetCode(), gin.H{
+			"message": httpError.GetMessage(),
+		})
+	} else {
+		ctx.JSON(http.StatusOK, response)
+	}
+}
+
+func (controller *toDoController) GetToDoOccurrences(ctx *gin.Context) {
+	var request dto.GetToDoOccurrencesRequest
+	if ID, err := uuid.Parse(ctx.Param("id")); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"message": "ID parameter must be a valid UUID value",
+		})
+	} else if err := ctx.ShouldBindQuery(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"message": err.Error(),
+		})
+	} else if response, err := controller.toDoReadService.GetToDoOccurrences(ctx.Request.Context(), model.GetToDoEvent{ID: ID}, request.Limit); err != nil {
+		httpError := controller.controllerAdvice.TranslateError(err)
+		ctx.JSON(httpError.GetCode(), gin.H{
+			"message": httpError.GetMessage(),
+		})
+	} else {
+		ctx.JSON(http.StatusOK, response)
+	}
+}
+
+func (controller *toDoController) AddToDoItem(ctx *gin.Context) {
+	var request dto.CreateToDoItemRequest
+	if toDoID, err := uuid.Parse(ctx.Param("id")); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"message": "ID parameter must be a valid UUID value",
+		})
+	} else if err := ctx.ShouldBindWith(&request, binding.JSON); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"message": err.Error(),
+		})
+	} else if expectedVersion, ok := parseIfMatch(ctx); !ok {
+		ctx.JSON(http.StatusPreconditionFailed, gin.H{
+			"message": "If-Match header must hold an ETag returned for the ToDo",
+		})
+	} else if err := controller.toDoReadService.CheckToDoVersion(ctx.Request.Context(), toDoID, expectedVersion); err != nil {
+		httpError := controller.controllerAdvice.TranslateError(err)
+		ctx.JSON(httpError.GetCode(), gin.H{
+			"message": httpError.GetMessage(),
+		})
+	} else if _, err := controller.toDoReadService.GetToDo(ctx.Request.Context(), model.GetToDoEvent{ID: toDoID}); err != nil {
+		httpError := controller.controllerAdvice.TranslateError(err)
+		ctx.JSON(httpError.GetCode(), gin.H{
+			"message": httpError.GetMessage(),
+		})
+	} else if ID, err := uuid.NewRandom(); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"message": err.Error(),
+		})
+	} else {
+		controller.eventBus.Publish(ctx.Request.Context(), request.ToEvent(toDoID, ID, expectedVersion))
+		response := dto.CreateToDoItemResponse{
+			ID: ID,
+		}
+		ctx.JSON(http.StatusCreated, response)
+	}
+}
+
+func (controller *toDoController) UpdateToDoItem(ctx *gin.Context) {
+	var request dto.UpdateToDoItemRequest
+	if toDoID, err := uuid.Parse(ctx.Param("id")); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"message": "ID parameter must be a valid UUID value",
+		})
+	} else if ID, err := uuid.Parse(ctx.Param("itemId")); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"message": "item ID parameter must be a valid UUID value",
+		})
+	} else if err := ctx.ShouldBindWith(&request, binding.JSON); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"message": err.Error(),
+		})
+	} else if !hasIfMatch(ctx) {
+		ctx.JSON(http.StatusPreconditionRequired, gin.H{
+			"message": "If-Match header is required, holding an ETag returned for the ToDo",
+		})
+	} else if expectedVersion, ok := parseIfMatch(ctx); !ok {
+		ctx.JSON(http.StatusPreconditionFailed, gin.H{
+			"message": "If-Match header must hold an ETag returned for the ToDo",
+		})
+	} else if err := controller.toDoReadService.CheckToDoVersion(ctx.Request.Context(), toDoID, expectedVersion); err != nil {
+		httpError := controller.controllerAdvice.TranslateError(err)
+		ctx.JSON(httpError.GetCode(), gin.H{
+			"message": httpError.GetMessage(),
+		})
+	} else if err := controller.toDoReadService.CheckToDoItem(ctx.Request.Context(), toDoID, ID); err != nil {
+		httpError := controller.controllerAdvice.TranslateError(err)
+		ctx.JSON(httpError.GetCode(), gin.H{
+			"message": httpError.GetMessage(),
+		})
+	} else {
+		controller.eventBus.Publish(ctx.Request.Context(), request.ToEvent(toDoID, ID, expectedVersion))
+		ctx.JSON(http.StatusNoContent, gin.H{})
+	}
+}
+
+func (controller *toDoController) DeleteToDoItem(ctx *gin.Context) {
+	if toDoID, err := uuid.Parse(ctx.Param("id")); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"message": "ID parameter must be a valid UUID value",
+		})
+	} else if ID, err := uuid.Parse(ctx.Param("itemId")); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"message": "item ID parameter must be a valid UUID value",
+		})
+	} else if !hasIfMatch(ctx) {
+		ctx.JSON(http.StatusPreconditionRequired, gin.H{
+			"message": "If-Match header is required, holding an ETag returned for the ToDo",
+		})
+	} else if expectedVersion, ok := parseIfMatch(ctx); !ok {
+		ctx.JSON(http.StatusPreconditionFailed, gin.H{
+			"message": "If-Match header must hold an ETag returned for the ToDo",
+		})
+	} else if err := controller.toDoReadService.CheckToDoVersion(ctx.Request.Context(), toDoID, expectedVersion); err != nil {
+		httpError := controller.controllerAdvice.TranslateError(err)
+		ctx.JSON(httpError.GetCode(), gin.H{
+			"message": httpError.GetMessage(),
+		})
+	} else if err := controller.toDoReadService.CheckToDoItem(ctx.Request.Context(), toDoID, ID); err != nil {
+		httpError := controller.controllerAdvice.TranslateError(err)
+		ctx.JSON(httpError.GetCode(), gin.H{
+			"message": httpError.GetMessage(),
+		})
+	} else {
+		event := model.DeleteToDoItemEvent{
+			ID:              ID,
+			ToDoID:          toDoID,
+			ExpectedVersion: expectedVersion,
+		}
+		controller.eventBus.Publish(ctx.Request.Context(), event)
+		ctx.JSON(http.StatusNoContent, gin.H{})
+	}
+}
+
+func (controller *toDoController) ReorderToDoItems(ctx *gin.Context) {
+	var request dto.ReorderToDoItemsRequest
+	if toDoID, err := uuid.Parse(ctx.Param("id")); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"message": "ID parameter must be a valid UUID value",
+		})
+	} else if err := ctx.ShouldBindWith(&request, binding.JSON); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"message": err.Error(),
+		})
+	} else if !hasIfMatch(ctx) {
+		ctx.JSON(http.StatusPreconditionRequired, gin.H{
+			"message": "If-Match header is required, holding an ETag returned for the ToDo",
+		})
+	} else if expectedVersion, ok := parseIfMatch(ctx); !ok {
+		ctx.JSON(http.StatusPreconditionFailed, gin.H{
+			"message": "If-Match header must hold an ETag returned for the ToDo",
+		})
+	} else if err := controller.toDoReadService.CheckToDoVersion(ctx.Request.Context(), toDoID, expectedVersion); err != nil {
+		httpError := controller.controllerAdvice.TranslateError(err)
+		ctx.JSON(httpError.GetCode(), gin.H{
+			"message": httpError.GetMessage(),
+		})
+	} else if err := controller.toDoReadService.CheckToDoItemsOrder(ctx.Request.Context(), toDoID, request.Items); err != nil {
+		httpError := controller.controllerAdvice.TranslateError(err)
+		ctx.JSON(httpError.GetCode(), gin.H{
+			"message": httpError.GetMessage(),
+		})
+	} else {
+		controller.eventBus.Publish(ctx.Request.Context(), request.ToEvent(toDoID, expectedVersion))
+		ctx.JSON(http.StatusNoContent, gin.H{})
+	}
+}
+
+func (controller *toDoController) GetToDoReminders(ctx *gin.Context) {
+	if ID, err := uuid.Parse(ctx.Param("id")); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"message": "ID parameter must be a valid UUID value",
+		})
+	} else if response, err := controller.toDoReadService.GetToDoReminders(ctx.Request.Context(), model.GetToDoEvent{ID: ID}); err != nil {
+		httpError := controller.controllerAdvice.TranslateError(err)
+		ctx.JSON(httpError.GetCode(), gin.H{
+			"message": httpError.GetMessage(),
+		})
+	} else {
+		ctx.JSON(http.StatusOK, response)
+	}
+}
+
+func (controller *toDoController) AddToDoReminder(ctx *gin.Context) {
+	var request dto.CreateToDoReminderRequest
+	if toDoID, err := uuid.Parse(ctx.Param("id")); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"message": "ID parameter must be a valid UUID value",
+		})
+	} else if err := ctx.ShouldBindWith(&request, binding.JSON); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"message": err.Error(),
+		})
+	} else if before, err := request.ParseBefore(); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"message": err.Error(),
+		})
+	} else if err := controller.toDoReadService.CheckNewToDoReminder(ctx.Request.Context(), toDoID, before); err != nil {
+		httpError := controller.controllerAdvice.TranslateError(err)
+		ctx.JSON(httpError.GetCode(), gin.H{
+			"message": httpError.GetMessage(),
+		})
+	} else if ID, err := uuid.NewRandom(); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"message": err.Error(),
+		})
+	} else {
+		controller.eventBus.Publish(ctx.Request.Context(), request.ToEvent(toDoID, ID, before))
+		response := dto.CreateToDoReminderResponse{
+			ID: ID,
+		}
+		ctx.JSON(http.StatusCreated, response)
+	}
+}
+
+func (controller *toDoController) DeleteToDoReminder(ctx *gin.Context) {
+	if toDoID, err := uuid.Parse(ctx.Param("id")); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"message": "ID parameter must be a valid UUID value",
+		})
+	} else if ID, err := uuid.Parse(ctx.Param("reminderId")); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"message": "reminder ID parameter must be a valid UUID value",
+		})
+	} else if err := controller.toDoReadService.CheckToDoReminder(ctx.Request.Context(), toDoID, ID); err != nil {
+		httpError := controller.controllerAdvice.TranslateError(err)
+		ctx.JSON(httpError.GetCode(), gin.H{
+			"message": httpError.GetMessage(),
+		})
+	} else {
+		event := model.DeleteToDoReminderEvent{
+			ID:     ID,
+			ToDoID: toDoID,
 		}
-		controller.eventBus.Publish(event)
+		controller.eventBus.Publish(ctx.Request.Context(), event)
 		ctx.JSON(http.StatusNoContent, gin.H{})
 	}
 }
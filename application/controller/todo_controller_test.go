@@ -0,0 +1,233 @@
+package controller
+
+import (
+	"context"
+	"event-bus-demo/application/dto"
+	"event-bus-demo/application/error"
+	domainError "event-bus-demo/domain/error"
+	"event-bus-demo/domain/model"
+	"event-bus-demo/domain/service"
+	dbService "event-bus-demo/infrastructure/database/service"
+	infrastructure "event-bus-demo/infrastructure/error"
+	"event-bus-demo/infrastructure/event_sourcing"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeToDoDatabaseService holds a single ToDo, the other methods of the interface are not used by the tests.
+type fakeToDoDatabaseService struct {
+	dbService.ToDoDatabaseService
+	toDo model.ToDo
+}
+
+func (dbService *fakeToDoDatabaseService) GetToDo(_ context.Context, ID uuid.UUID) (model.ToDo,
+	infrastructure.InfrastructureError) {
+	if ID != dbService.toDo.ID {
+		return model.ToDo{}, infrastructure.NewItemNotFoundError("toDo not found")
+	}
+	return dbService.toDo, nil
+}
+
+// publishedEvents records the events published by the controller instead of handling them.
+type publishedEvents struct {
+	event_sourcing.EventBus
+	mutex  sync.Mutex
+	events []event_sourcing.Event
+}
+
+func (bus *publishedEvents) Publish(_ context.Context, event event_sourcing.Event) {
+	bus.mutex.Lock()
+	bus.events = append(bus.events, event)
+	bus.mutex.Unlock()
+}
+
+func (bus *publishedEvents) published() []event_sourcing.Event {
+	bus.mutex.Lock()
+	defer bus.mutex.Unlock()
+	return append([]event_sourcing.Event(nil), bus.events...)
+}
+
+func newTestToDoRouter(toDo model.ToDo) (*gin.Engine, *publishedEvents) {
+	gin.SetMode(gin.TestMode)
+	eventBus := &publishedEvents{}
+	toDoReadService := service.NewToDoReadService(&fakeToDoDatabaseService{toDo: toDo}, nil,
+		domainError.NewDomainAdvice(), zap.NewNop())
+	controller := NewTodoController(eventBus, toDoReadService, nil, error.NewControllerAdvice())
+	router := gin.New()
+	router.GET("/v1/todo/:id", controller.GetToDoById)
+	router.PUT("/v1/todo/:id", controller.UpdateToDo)
+	router.PATCH("/v1/todo/:id", controller.PatchToDo)
+	router.POST("/v1/todo/:id/complete", controller.CompleteToDo)
+	router.POST("/v1/todo/:id/reopen", controller.ReopenToDo)
+	router.POST("/v1/todo/:id/start", controller.StartToDo)
+	router.POST("/v1/todo/:id/cancel", controller.CancelToDo)
+	return router, eventBus
+}
+
+func TestPatchToDoRequiresMatchingETag(t *testing.T) {
+	toDo := model.ToDo{ID: uuid.New(), Title: "Buy milk", Priority: model.MediumToDoPriority, Version: 3}
+	tests := []struct {
+		name            string
+		ifMatch         string
+		expectedStatus  int
+		expectedVersion *int64
+	}{
+		{name: "current version", ifMatch: `"3"`, expectedStatus: http.StatusNoContent, expectedVersion: &toDo.Version},
+		{name: "no header", expectedStatus: http.StatusPreconditionRequired},
+		{name: "any version", ifMatch: "*", expectedStatus: http.StatusNoContent},
+		{name: "stale version", ifMatch: `"2"`, expectedStatus: http.StatusPreconditionFailed},
+		{name: "weak tag", ifMatch: `W/"3"`, expectedStatus: http.StatusPreconditionFailed},
+		{name: "unquoted tag", ifMatch: "3", expectedStatus: http.StatusPreconditionFailed},
+		{name: "list of tags", ifMatch: `"2", "3"`, expectedStatus: http.StatusPreconditionFailed},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			router, eventBus := newTestToDoRouter(toDo)
+			request := httptest.NewRequest(http.MethodPatch, "/v1/todo/"+toDo.ID.String(),
+				strings.NewReader(`{"title":"Buy bread"}`))
+			request.Header.Set("Content-Type", dto.MergePatchContentType)
+			if test.ifMatch != "" {
+				request.Header.Set(ifMatchHeader, test.ifMatch)
+			}
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, request)
+			if recorder.Code != test.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", test.expectedStatus, recorder.Code, recorder.Body.String())
+			}
+			published := eventBus.published()
+			if test.expectedStatus != http.StatusNoContent {
+				if len(published) != 0 {
+					t.Errorf("expected no event to be published, got %v", published)
+				}
+				return
+			}
+			if len(published) != 1 {
+				t.Fatalf("expected one event to be published, got %v", published)
+			}
+			event, ok := published[0].(model.UpdateToDoEvent)
+			if !ok || event.Title != "Buy bread" {
+				t.Fatalf("expected an update of the title, got %+v", published[0])
+			} else if (event.ExpectedVersion == nil) != (test.expectedVersion == nil) ||
+				(event.ExpectedVersion != nil && *event.ExpectedVersion != *test.expectedVersion) {
+				t.Errorf("expected the update to require version %v, got %v", test.expectedVersion, event.ExpectedVersion)
+			}
+		})
+	}
+}
+
+func TestGetToDoByIdReturnsETagAcceptedByPatch(t *testing.T) {
+	toDo := model.ToDo{ID: uuid.New(), Title: "Buy milk", Priority: model.MediumToDoPriority, Version: 7}
+	router, eventBus := newTestToDoRouter(toDo)
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/v1/todo/"+toDo.ID.String(), nil))
+	eTag := recorder.Header().Get(eTagHeader)
+	if recorder.Code != http.StatusOK || eTag != `"7"` {
+		t.Fatalf("expected the ToDo with ETag \"7\", got status %d and ETag %q", recorder.Code, eTag)
+	}
+	request := httptest.NewRequest(http.MethodPatch, "/v1/todo/"+toDo.ID.String(),
+		strings.NewReader(`[{"op":"test","path":"/title","value":"Buy milk"},{"op":"remove","path":"/description"}]`))
+	request.Header.Set("Content-Type", dto.JSONPatchContentType)
+	request.Header.Set(ifMatchHeader, eTag)
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+	if recorder.Code != http.StatusNoContent || len(eventBus.published()) != 1 {
+		t.Errorf("expected the patch to be accepted, got status %d: %s", recorder.Code, recorder.Body.String())
+	}
+}
+
+func TestUpdateToDoRequiresDescription(t *testing.T) {
+	toDo := model.ToDo{ID: uuid.New(), Title: "Buy milk", Description: "Two bottles",
+		Priority: model.MediumToDoPriority, Version: 3}
+	tests := []struct {
+		name                string
+		body                string
+		expectedStatus      int
+		expectedDescription string
+	}{
+		{name: "title only", body: `{"title":"Buy bread"}`, expectedStatus: http.StatusBadRequest},
+		{name: "null description", body: `{"title":"Buy bread","description":null}`,
+			expectedStatus: http.StatusBadRequest},
+		{name: "empty description", body: `{"title":"Buy bread","description":""}`,
+			expectedStatus: http.StatusNoContent},
+		{name: "description", body: `{"title":"Buy bread","description":"One loaf"}`,
+			expectedStatus: http.StatusNoContent, expectedDescription: "One loaf"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			router, eventBus := newTestToDoRouter(toDo)
+			request := httptest.NewRequest(http.MethodPut, "/v1/todo/"+toDo.ID.String(), strings.NewReader(test.body))
+			request.Header.Set("Content-Type", "application/json")
+			request.Header.Set(ifMatchHeader, `"3"`)
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, request)
+			if recorder.Code != test.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", test.expectedStatus, recorder.Code, recorder.Body.String())
+			}
+			published := eventBus.published()
+			if test.expectedStatus != http.StatusNoContent {
+				if len(published) != 0 {
+					t.Errorf("expected the description to be kept, got %v", published)
+				}
+				return
+			}
+			if len(published) != 1 {
+				t.Fatalf("expected one event to be published, got %v", published)
+			} else if event := published[0].(model.UpdateToDoEvent); event.Description != test.expectedDescription {
+				t.Errorf("expected description %q, got %q", test.expectedDescription, event.Description)
+			}
+		})
+	}
+}
+
+func TestChangeToDoStatusRefusesForbiddenTransitions(t *testing.T) {
+	tests := []struct {
+		status         model.ToDoStatus
+		action         string
+		expectedStatus int
+		expectedEvent  string
+	}{
+		{status: model.OpenToDoStatus, action: "start", expectedStatus: http.StatusNoContent,
+			expectedEvent: "StartToDoEvent"},
+		{status: model.OpenToDoStatus, action: "complete", expectedStatus: http.StatusNoContent,
+			expectedEvent: "CompleteToDoEvent"},
+		{status: model.OpenToDoStatus, action: "reopen", expectedStatus: http.StatusConflict},
+		{status: model.InProgressToDoStatus, action: "start", expectedStatus: http.StatusConflict},
+		{status: model.InProgressToDoStatus, action: "cancel", expectedStatus: http.StatusNoContent,
+			expectedEvent: "CancelToDoEvent"},
+		{status: model.DoneToDoStatus, action: "complete", expectedStatus: http.StatusConflict},
+		{status: model.DoneToDoStatus, action: "start", expectedStatus: http.StatusConflict},
+		{status: model.DoneToDoStatus, action: "cancel", expectedStatus: http.StatusConflict},
+		{status: model.DoneToDoStatus, action: "reopen", expectedStatus: http.StatusNoContent,
+			expectedEvent: "ReopenToDoEvent"},
+		{status: model.CancelledToDoStatus, action: "complete", expectedStatus: http.StatusConflict},
+		{status: model.CancelledToDoStatus, action: "reopen", expectedStatus: http.StatusNoContent,
+			expectedEvent: "ReopenToDoEvent"},
+	}
+	for _, test := range tests {
+		t.Run(string(test.status)+" "+test.action, func(t *testing.T) {
+			toDo := model.ToDo{ID: uuid.New(), Title: "Buy milk", Status: test.status, Version: 3}
+			router, eventBus := newTestToDoRouter(toDo)
+			request := httptest.NewRequest(http.MethodPost, "/v1/todo/"+toDo.ID.String()+"/"+test.action, nil)
+			request.Header.Set(ifMatchHeader, `"3"`)
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, request)
+			if recorder.Code != test.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", test.expectedStatus, recorder.Code, recorder.Body.String())
+			}
+			published := eventBus.published()
+			if test.expectedEvent == "" {
+				if len(published) != 0 {
+					t.Errorf("expected no event to be published, got %v", published)
+				}
+			} else if len(published) != 1 || published[0].GetName() != test.expectedEvent {
+				t.Errorf("expected a %s to be published, got %v", test.expectedEvent, published)
+			}
+		})
+	}
+}
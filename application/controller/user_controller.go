@@ -38,7 +38,7 @@ func (controller *userController) GetUserByID(ctx *gin.Context) {
 		ctx.JSON(http.StatusBadRequest, gin.H{
 			"message": "ID parameter must be a valid UUID value",
 		})
-	} else if response, err := controller.userReadService.GetUserByID(model.GetUserByIDEvent{ID: ID}); err != nil {
+	} else if response, err := controller.userReadService.GetUserByID(ctx.Request.Context(), model.GetUserByIDEvent{ID: ID}); err != nil {
 		httpError := controller.controllerAdvice.TranslateError(err)
 		ctx.JSON(httpError.GetCode(), gin.H{
 			"message": httpError.GetMessage(),
@@ -60,7 +60,7 @@ func (controller *userController) CreateUser(ctx *gin.Context) {
 			"message": err.Error(),
 		})
 	} else {
-		controller.eventBus.Publish(request.ToEvent(id))
+		controller.eventBus.Publish(ctx.Request.Context(), request.ToEvent(id))
 		response := dto.CreateToDoResponse{
 			ID: id,
 		}
@@ -78,12 +78,12 @@ func (controller *userController) UpdateUserPassword(ctx *gin.Context) {
 		ctx.JSON(http.StatusBadRequest, gin.H{
 			"message": err.Error(),
 		})
-	} else if _, err := controller.userReadService.GetUserByID(model.GetUserByIDEvent{ID: ID}); err != nil {
+	} else if _, err := controller.userReadService.GetUserByID(ctx.Request.Context(), model.GetUserByIDEvent{ID: ID}); err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{
 			"message": err.Error(),
 		})
 	} else {
-		controller.eventBus.Publish(request.ToEvent(ID))
+		controller.eventBus.Publish(ctx.Request.Context(), request.ToEvent(ID))
 		ctx.JSON(http.StatusNoContent, gin.H{})
 	}
 }
@@ -94,7 +94,7 @@ func (controller *userController) DeleteUser(ctx *gin.Context) {
 			"message": "ID parameter must be a valid UUID value",
 		})
 	} else {
-		controller.eventBus.Publish(model.DeleteUserEvent{ID: ID})
+		controller.eventBus.Publish(ctx.Request.Context(), model.DeleteUserEvent{ID: ID})
 		ctx.JSON(http.StatusNoContent, gin.H{})
 	}
 }
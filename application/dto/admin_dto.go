@@ -0,0 +1,74 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type GetEventBusResponse struct {
+	Buffer          EventBusBufferResponse    `json:"buffer"`
+	Workers         EventBusWorkersResponse   `json:"workers"`
+	Topics          []EventBusTopicResponse   `json:"topics"`
+	RecentFailures  []EventBusFailureResponse `json:"recentFailures"`
+	CircuitBreakers []CircuitBreakerResponse  `json:"circuitBreakers"`
+}
+
+type EventBusBufferResponse struct {
+	Length   int `json:"length"`
+	Capacity int `json:"capacity"`
+}
+
+type EventBusWorkersResponse struct {
+	Busy int `json:"busy"`
+	Max  int `json:"max"`
+}
+
+type EventBusTopicResponse struct {
+	Name              string                    `json:"name"`
+	Paused            bool                      `json:"paused"`
+	Held              bool                      `json:"held"`
+	Buffer            EventBusBufferResponse    `json:"buffer"`
+	Workers           EventBusWorkersResponse   `json:"workers"`
+	Weight            int                       `json:"weight"`
+	Lanes             map[string]int            `json:"lanes"`
+	PendingEvents     int                       `json:"pendingEvents"`
+	OverflowEvents    int                       `json:"overflowEvents"`
+	AbandonedHandlers int                       `json:"abandonedHandlers"`
+	Events            []string                  `json:"events"`
+	Handlers          []EventBusHandlerResponse `json:"handlers"`
+	Subscribers       []string                  `json:"subscribers"`
+}
+
+type EventBusHandlerResponse struct {
+	Name   string   `json:"name"`
+	Events []string `json:"events"`
+}
+
+type EventBusFailureResponse struct {
+	Topic     string    `json:"topic"`
+	Event     string    `json:"event"`
+	Kind      string    `json:"kind"`
+	Error     string    `json:"error"`
+	RequestID string    `json:"requestId,omitempty"`
+	FailedAt  time.Time `json:"failedAt"`
+}
+
+type CircuitBreakerResponse struct {
+	Name                string     `json:"name"`
+	State               string     `json:"state"`
+	ConsecutiveFailures int        `json:"consecutiveFailures"`
+	OpenedAt            *time.Time `json:"openedAt,omitempty"`
+}
+
+type UpdateEventBusWorkersRequest struct {
+	MaxWorkers int `json:"maxWorkers" binding:"required,min=1"`
+}
+
+type DrainTopicRequest struct {
+	TimeoutSeconds int `form:"timeout" binding:"omitempty,min=1,max=300"`
+}
+
+type PublishEventRequest struct {
+	Name    string          `json:"name" binding:"required"`
+	Payload json.RawMessage `json:"payload"`
+}
@@ -10,8 +10,9 @@ type GetCategoriesResponse struct {
 }
 
 type GetCategoryResponse struct {
-	ID   uuid.UUID `json:"id" binding:"required"`
-	Name string    `json:"name" binding:"required"`
+	ID      uuid.UUID `json:"id" binding:"required"`
+	Name    string    `json:"name" binding:"required"`
+	Version int64     `json:"version" binding:"required"`
 }
 
 type CreateCategoryRequest struct {
@@ -33,9 +34,10 @@ type UpdateCategoryRequest struct {
 	Name string `json:"name" binding:"required"`
 }
 
-func (req UpdateCategoryRequest) ToEvent(ID uuid.UUID) model.UpdateCategoryNameEvent {
+func (req UpdateCategoryRequest) ToEvent(ID uuid.UUID, expectedVersion *int64) model.UpdateCategoryNameEvent {
 	return model.UpdateCategoryNameEvent{
-		ID:   ID,
-		Name: req.Name,
+		ID:              ID,
+		Name:            req.Name,
+		ExpectedVersion: expectedVersion,
 	}
 }
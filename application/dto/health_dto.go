@@ -0,0 +1,13 @@
+package dto
+
+type HealthResponse struct {
+	Status string                `json:"status"`
+	Checks []HealthCheckResponse `json:"checks,omitempty"`
+}
+
+type HealthCheckResponse struct {
+	Name    string                 `json:"name"`
+	Status  string                 `json:"status"`
+	Details map[string]interface{} `json:"details,omitempty"`
+	Error   string                 `json:"error,omitempty"`
+}
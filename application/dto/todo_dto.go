@@ -3,36 +3,78 @@ package dto
 import (
 	"event-bus-demo/domain/model"
 	"github.com/google/uuid"
+	"strings"
 	"time"
 )
 
+// CreateToDoRequest creates an open ToDo, of medium priority unless another one is given. Recurrence is an RRULE such
+// as FREQ=WEEKLY;BYDAY=MO,TH, the next occurrence being created when the ToDo is completed.
 type CreateToDoRequest struct {
 	Title       string      `json:"title" binding:"required"`
 	Description string      `json:"description" binding:"required"`
 	CreatedAt   time.Time   `json:"createdAt" binding:"required"`
+	Priority    string      `json:"priority" binding:"omitempty,oneof=low medium high"`
+	DueAt       *time.Time  `json:"dueAt"`
+	Recurrence  string      `json:"recurrence"`
 	Categories  []uuid.UUID `json:"categories"`
 }
 
-func (req CreateToDoRequest) ToEvent(ID uuid.UUID) model.CreateToDoEvent {
-	return model.CreateToDoEvent{
+// ParseRecurrence returns the recurrence rule of the request, nil when the ToDo does not recur.
+func (req CreateToDoRequest) ParseRecurrence() (*model.RecurrenceRule, error) {
+	return parseRecurrence(req.Recurrence)
+}
+
+func (req CreateToDoRequest) ToEvent(ID uuid.UUID, recurrence *model.RecurrenceRule) model.CreateToDoEvent {
+	event := model.CreateToDoEvent{
 		ID:          ID,
 		Title:       req.Title,
 		Description: req.Description,
 		CreatedAt:   req.CreatedAt,
+		Priority:    model.ToDoPriority(req.Priority),
+		DueAt:       req.DueAt,
+		Recurrence:  recurrence,
 		Categories:  req.Categories,
 	}
+	if event.Priority == "" {
+		event.Priority = model.MediumToDoPriority
+	}
+	return event
 }
 
+// UpdateToDoRequest replaces the fields of a ToDo, PatchToDoRequest changes some of them. The priority is kept when
+// omitted, so clients unaware of it do not reset it. The description must be given, though it may be empty as it is
+// after removing it with a patch.
 type UpdateToDoRequest struct {
-	Title       string `json:"title"`
-	Description string `json:"description"`
+	Title       string  `json:"title" binding:"required"`
+	Description *string `json:"description" binding:"required"`
+	Priority    string  `json:"priority" binding:"omitempty,oneof=low medium high"`
 }
 
-func (req UpdateToDoRequest) ToEvent(ID uuid.UUID) model.UpdateToDoEvent {
-	return model.UpdateToDoEvent{
-		ID:          ID,
-		Title:       req.Title,
-		Description: req.Description,
+func (req UpdateToDoRequest) ToEvent(ID uuid.UUID, expectedVersion *int64) model.UpdateToDoEvent {
+	event := model.UpdateToDoEvent{
+		ID:              ID,
+		Title:           req.Title,
+		Description:     *req.Description,
+		Fields:          []model.ToDoField{model.ToDoTitleField, model.ToDoDescriptionField},
+		ExpectedVersion: expectedVersion,
+	}
+	if req.Priority != "" {
+		event.Priority = model.ToDoPriority(req.Priority)
+		event.Fields = append(event.Fields, model.ToDoPriorityField)
+	}
+	return event
+}
+
+// RescheduleToDoRequest sets the due date of a ToDo, or removes it when DueAt is null.
+type RescheduleToDoRequest struct {
+	DueAt *time.Time `json:"dueAt"`
+}
+
+func (req RescheduleToDoRequest) ToEvent(ID uuid.UUID, expectedVersion *int64) model.RescheduleToDoEvent {
+	return model.RescheduleToDoEvent{
+		ID:              ID,
+		DueAt:           req.DueAt,
+		ExpectedVersion: expectedVersion,
 	}
 }
 
@@ -42,11 +84,133 @@ type GetToDoResponse struct {
 	Description string                `json:"description" binding:"required"`
 	CreatedAt   *time.Time            `json:"createdAt" binding:"required"`
 	UpdatedAt   *time.Time            `json:"updatedAt,omitempty"`
+	Status      string                `json:"status" binding:"required"`
+	Priority    string                `json:"priority" binding:"required"`
+	DueAt       *time.Time            `json:"dueAt,omitempty"`
+	CompletedAt *time.Time            `json:"completedAt,omitempty"`
+	Recurrence  string                `json:"recurrence,omitempty"`
+	SeriesID    *uuid.UUID            `json:"seriesId,omitempty"`
+	Occurrence  int                   `json:"occurrence"`
+	Version     int64                 `json:"version" binding:"required"`
 	Categories  []GetCategoryResponse `json:"categories,omitempty"`
+	Items       []GetToDoItemResponse `json:"items,omitempty"`
+	// Progress is the percentage of done checklist items
+	Progress int `json:"progress"`
+}
+
+// GetToDoOccurrencesRequest holds the query parameters of the occurrence preview of a recurring ToDo.
+type GetToDoOccurrencesRequest struct {
+	Limit int `form:"limit,default=5" binding:"min=1,max=50"`
+}
+
+// GetToDoOccurrencesResponse lists the due dates of the next occurrences of a ToDo, empty when it does not recur.
+type GetToDoOccurrencesResponse struct {
+	Recurrence  string      `json:"recurrence,omitempty"`
+	Occurrences []time.Time `json:"occurrences"`
 }
 
 type GetAllToDoResponse struct {
 	ToDos []GetToDoResponse `json:"ToDos" binding:"required"`
+	Links PageLinks         `json:"links"`
+	// Page information the links are built from
+	HasNext        bool   `json:"-"`
+	HasPrevious    bool   `json:"-"`
+	NextCursor     string `json:"-"`
+	PreviousCursor string `json:"-"`
+}
+
+type PageLinks struct {
+	Next string `json:"next,omitempty"`
+	Prev string `json:"prev,omitempty"`
+}
+
+// GetToDoListRequest holds the query parameters of the ToDo list. Pages are selected either by cursor, taken from the
+// links of a previous page, or by offset. Sort is a field name, prefixed with a minus sign for descending order.
+// Overdue keeps the unfinished ToDos past their due date, and DueWithin, a duration such as 48h, those due soon.
+type GetToDoListRequest struct {
+	Limit       int            `form:"limit,default=20" binding:"min=1,max=100"`
+	Offset      *int           `form:"offset" binding:"omitempty,min=0,excluded_with=Cursor"`
+	Cursor      string         `form:"cursor"`
+	Category    string         `form:"category" binding:"omitempty,uuid"`
+	CreatedFrom *time.Time     `form:"createdFrom" time_format:"2006-01-02T15:04:05Z07:00"`
+	CreatedTo   *time.Time     `form:"createdTo" time_format:"2006-01-02T15:04:05Z07:00"`
+	UpdatedFrom *time.Time     `form:"updatedFrom" time_format:"2006-01-02T15:04:05Z07:00"`
+	UpdatedTo   *time.Time     `form:"updatedTo" time_format:"2006-01-02T15:04:05Z07:00"`
+	Title       string         `form:"title"`
+	Status      string         `form:"status" binding:"omitempty,oneof=open in-progress done cancelled"`
+	Priority    string         `form:"priority" binding:"omitempty,oneof=low medium high"`
+	Overdue     bool           `form:"overdue" binding:"excluded_with=DueWithin"`
+	DueWithin   *time.Duration `form:"dueWithin" binding:"omitempty,gt=0"`
+	Sort        string         `form:"sort,default=createdAt" binding:"oneof=createdAt -createdAt updatedAt -updatedAt title -title"`
+}
+
+var toDoSortFields = map[string]model.ToDoSortField{
+	"createdAt": model.SortToDoByCreatedAt,
+	"updatedAt": model.SortToDoByUpdatedAt,
+	"title":     model.SortToDoByTitle,
+}
+
+func (req GetToDoListRequest) ToEvent() model.GetToDoListEvent {
+	event := model.GetToDoListEvent{
+		Filter: model.ToDoFilter{
+			CreatedFrom: req.CreatedFrom,
+			CreatedTo:   req.CreatedTo,
+			UpdatedFrom: req.UpdatedFrom,
+			UpdatedTo:   req.UpdatedTo,
+			Title:       req.Title,
+			Overdue:     req.Overdue,
+			DueWithin:   req.DueWithin,
+		},
+		SortField:  toDoSortFields[strings.TrimPrefix(req.Sort, "-")],
+		Descending: strings.HasPrefix(req.Sort, "-"),
+		Page: model.ToDoPageRequest{
+			Limit:  req.Limit,
+			Cursor: req.Cursor,
+		},
+	}
+	if req.Category != "" {
+		categoryID := uuid.MustParse(req.Category)
+		event.Filter.CategoryID = &categoryID
+	}
+	if req.Status != "" {
+		status := model.ToDoStatus(req.Status)
+		event.Filter.Status = &status
+	}
+	if req.Priority != "" {
+		priority := model.ToDoPriority(req.Priority)
+		event.Filter.Priority = &priority
+	}
+	if req.Offset != nil {
+		event.Page.Offset = *req.Offset
+	}
+	return event
+}
+
+// SearchToDoRequest holds the query parameters of the ToDo text search. Q accepts the web search syntax: quoted
+// phrases, "or" and words excluded with a minus sign.
+type SearchToDoRequest struct {
+	Q     string `form:"q" binding:"required,max=200"`
+	Limit int    `form:"limit,default=20" binding:"min=1,max=100"`
+}
+
+func (req SearchToDoRequest) ToEvent() model.SearchToDoEvent {
+	return model.SearchToDoEvent{
+		Query: req.Q,
+		Limit: req.Limit,
+	}
+}
+
+type SearchToDoResponse struct {
+	Results []SearchToDoResult `json:"results" binding:"required"`
+}
+
+// SearchToDoResult is a matching ToDo, with its title and an extract of its description where matched words are
+// wrapped in <mark> tags.
+type SearchToDoResult struct {
+	ToDo           GetToDoResponse `json:"todo"`
+	Rank           float64         `json:"rank"`
+	TitleHighlight string          `json:"titleHighlight"`
+	Snippet        string          `json:"snippet"`
 }
 
 type CreateToDoResponse struct {
@@ -60,3 +224,14 @@ type RemoveCategoriesFromToDoRequest struct {
 type AddCategoriesFromToDoRequest struct {
 	CategoriesID []uuid.UUID `json:"categories_id" binding:"required"`
 }
+
+func parseRecurrence(value string) (*model.RecurrenceRule, error) {
+	if value == "" {
+		return nil, nil
+	}
+	rule, err := model.ParseRecurrenceRule(value)
+	if err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
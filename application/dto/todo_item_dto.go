@@ -0,0 +1,74 @@
+package dto
+
+import (
+	"event-bus-demo/domain/model"
+	"github.com/google/uuid"
+	"time"
+)
+
+// CreateToDoItemRequest adds an item to the checklist of a ToDo, at the given position starting from 0, or after the
+// last item when no position is given.
+type CreateToDoItemRequest struct {
+	Title    string `json:"title" binding:"required"`
+	Position *int   `json:"position" binding:"omitempty,min=0"`
+}
+
+func (req CreateToDoItemRequest) ToEvent(toDoID uuid.UUID, ID uuid.UUID, expectedVersion *int64) model.CreateToDoItemEvent {
+	return model.CreateToDoItemEvent{
+		ID:              ID,
+		ToDoID:          toDoID,
+		Title:           req.Title,
+		Position:        req.Position,
+		CreatedAt:       time.Now(),
+		ExpectedVersion: expectedVersion,
+	}
+}
+
+// UpdateToDoItemRequest changes the title or completion of a checklist item, omitted fields being kept.
+type UpdateToDoItemRequest struct {
+	Title *string `json:"title" binding:"required_without=Done,omitempty,min=1"`
+	Done  *bool   `json:"done"`
+}
+
+func (req UpdateToDoItemRequest) ToEvent(toDoID uuid.UUID, ID uuid.UUID, expectedVersion *int64) model.UpdateToDoItemEvent {
+	return model.UpdateToDoItemEvent{
+		ID:              ID,
+		ToDoID:          toDoID,
+		Title:           req.Title,
+		Done:            req.Done,
+		UpdatedAt:       time.Now(),
+		ExpectedVersion: expectedVersion,
+	}
+}
+
+// ReorderToDoItemsRequest lists every item of the checklist once, in their new order.
+type ReorderToDoItemsRequest struct {
+	Items []uuid.UUID `json:"items" binding:"required"`
+}
+
+func (req ReorderToDoItemsRequest) ToEvent(toDoID uuid.UUID, expectedVersion *int64) model.ReorderToDoItemsEvent {
+	return model.ReorderToDoItemsEvent{
+		ToDoID:          toDoID,
+		Items:           req.Items,
+		ExpectedVersion: expectedVersion,
+	}
+}
+
+type GetToDoItemResponse struct {
+	ID        uuid.UUID  `json:"id" binding:"required"`
+	Title     string     `json:"title" binding:"required"`
+	Done      bool       `json:"done"`
+	Position  int        `json:"position"`
+	CreatedAt *time.Time `json:"createdAt" binding:"required"`
+	UpdatedAt *time.Time `json:"updatedAt,omitempty"`
+}
+
+// GetToDoItemsResponse is the checklist of a ToDo, with the percentage of done items.
+type GetToDoItemsResponse struct {
+	Items    []GetToDoItemResponse `json:"items" binding:"required"`
+	Progress int                   `json:"progress"`
+}
+
+type CreateToDoItemResponse struct {
+	ID uuid.UUID `json:"id" binding:"required"`
+}
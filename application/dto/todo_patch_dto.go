@@ -0,0 +1,228 @@
+package dto
+
+import (
+	"encoding/json"
+	"errors"
+	"event-bus-demo/domain/model"
+	"fmt"
+	"github.com/google/uuid"
+	"strings"
+)
+
+const (
+	MergePatchContentType = "application/merge-patch+json"
+	JSONPatchContentType  = "application/json-patch+json"
+)
+
+// PatchToDoRequest holds the fields of a ToDo changed by a patch, nil fields are kept. An empty recurrence stops the
+// ToDo from recurring.
+type PatchToDoRequest struct {
+	Title       *string
+	Description *string
+	Priority    *string
+	Recurrence  *string
+}
+
+// IsEmpty tells whether the patch leaves the ToDo unchanged.
+func (req PatchToDoRequest) IsEmpty() bool {
+	return req.Title == nil && req.Description == nil && req.Priority == nil && req.Recurrence == nil
+}
+
+func (req PatchToDoRequest) ToEvent(ID uuid.UUID, expectedVersion *int64) model.UpdateToDoEvent {
+	event := model.UpdateToDoEvent{
+		ID:              ID,
+		Fields:          make([]model.ToDoField, 0, len(patchableToDoFields)),
+		ExpectedVersion: expectedVersion,
+	}
+	if req.Title != nil {
+		event.Title = *req.Title
+		event.Fields = append(event.Fields, model.ToDoTitleField)
+	}
+	if req.Description != nil {
+		event.Description = *req.Description
+		event.Fields = append(event.Fields, model.ToDoDescriptionField)
+	}
+	if req.Priority != nil {
+		event.Priority = model.ToDoPriority(*req.Priority)
+		event.Fields = append(event.Fields, model.ToDoPriorityField)
+	}
+	if req.Recurrence != nil {
+		// The rule was validated when reading the patch
+		event.Recurrence, _ = parseRecurrence(*req.Recurrence)
+		event.Fields = append(event.Fields, model.ToDoRecurrenceField)
+	}
+	return event
+}
+
+// NewPatchToDoRequest reads a patch of the given content type, which is either a JSON patch or a JSON merge patch.
+func NewPatchToDoRequest(contentType string, body []byte, current GetToDoResponse) (PatchToDoRequest, error) {
+	if contentType == JSONPatchContentType {
+		return NewPatchToDoRequestFromJSONPatch(body, current)
+	}
+	return NewPatchToDoRequestFromMergePatch(body)
+}
+
+// patchableToDoFields are the members of a ToDo which patches may change. Other members are either read only or, like
+// categories, changed through their own endpoint.
+var patchableToDoFields = []model.ToDoField{model.ToDoTitleField, model.ToDoDescriptionField, model.ToDoPriorityField,
+	model.ToDoRecurrenceField}
+
+// NewPatchToDoRequestFromMergePatch reads a JSON merge patch (RFC 7396). Removing the description, by setting it to
+// null, empties it, removing the recurrence stops the ToDo from recurring, while the title and priority cannot be
+// removed.
+func NewPatchToDoRequestFromMergePatch(body []byte) (PatchToDoRequest, error) {
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(body, &members); err != nil || members == nil {
+		return PatchToDoRequest{}, errors.New("merge patch must be a JSON object")
+	}
+	document := make(map[model.ToDoField]*string)
+	for name, value := range members {
+		field, err := findPatchableToDoField(name)
+		if err != nil {
+			return PatchToDoRequest{}, err
+		}
+		if string(value) == "null" {
+			document[field] = nil
+			continue
+		}
+		var text string
+		if err := json.Unmarshal(value, &text); err != nil {
+			return PatchToDoRequest{}, fmt.Errorf("%s must be a string", name)
+		}
+		document[field] = &text
+	}
+	return newPatchToDoRequest(document)
+}
+
+type jsonPatchOperation struct {
+	Op    string           `json:"op"`
+	Path  string           `json:"path"`
+	From  string           `json:"from"`
+	Value *json.RawMessage `json:"value"`
+}
+
+// NewPatchToDoRequestFromJSONPatch applies a JSON patch (RFC 6902) to the title, description, priority and recurrence
+// of the current ToDo. Every operation is supported on these members, the patch being refused as a whole when one operation
+// fails.
+func NewPatchToDoRequestFromJSONPatch(body []byte, current GetToDoResponse) (PatchToDoRequest, error) {
+	var operations []jsonPatchOperation
+	if err := json.Unmarshal(body, &operations); err != nil || operations == nil {
+		return PatchToDoRequest{}, errors.New("JSON patch must be an array of operations")
+	}
+	values := map[model.ToDoField]*string{
+		model.ToDoTitleField:       &current.Title,
+		model.ToDoDescriptionField: &current.Description,
+		model.ToDoPriorityField:    &current.Priority,
+		model.ToDoRecurrenceField:  nil,
+	}
+	if current.Recurrence != "" {
+		values[model.ToDoRecurrenceField] = &current.Recurrence
+	}
+	document := make(map[model.ToDoField]*string)
+	for i, operation := range operations {
+		field, err := findJSONPatchField(operation.Path)
+		if err != nil {
+			return PatchToDoRequest{}, fmt.Errorf("operation %d: %w", i, err)
+		}
+		switch operation.Op {
+		case "add", "replace":
+			value, err := readJSONPatchValue(operation)
+			if err != nil {
+				return PatchToDoRequest{}, fmt.Errorf("operation %d: %w", i, err)
+			} else if operation.Op == "replace" && values[field] == nil {
+				return PatchToDoRequest{}, fmt.Errorf("operation %d: %s is not set", i, operation.Path)
+			}
+			values[field], document[field] = &value, &value
+		case "remove":
+			if values[field] == nil {
+				return PatchToDoRequest{}, fmt.Errorf("operation %d: %s is not set", i, operation.Path)
+			}
+			values[field], document[field] = nil, nil
+		case "test":
+			value, err := readJSONPatchValue(operation)
+			if err != nil {
+				return PatchToDoRequest{}, fmt.Errorf("operation %d: %w", i, err)
+			} else if values[field] == nil || *values[field] != value {
+				return PatchToDoRequest{}, fmt.Errorf("operation %d: test of %s failed", i, operation.Path)
+			}
+		case "move", "copy":
+			from, err := findJSONPatchField(operation.From)
+			if err != nil {
+				return PatchToDoRequest{}, fmt.Errorf("operation %d: %w", i, err)
+			} else if values[from] == nil {
+				return PatchToDoRequest{}, fmt.Errorf("operation %d: %s is not set", i, operation.From)
+			}
+			value := *values[from]
+			if operation.Op == "move" && from != field {
+				values[from], document[from] = nil, nil
+			}
+			values[field], document[field] = &value, &value
+		default:
+			return PatchToDoRequest{}, fmt.Errorf("operation %d: unknown operation %q", i, operation.Op)
+		}
+	}
+	return newPatchToDoRequest(document)
+}
+
+// newPatchToDoRequest builds the request from the members set by a patch, nil members being removed.
+func newPatchToDoRequest(document map[model.ToDoField]*string) (PatchToDoRequest, error) {
+	var request PatchToDoRequest
+	if title, ok := document[model.ToDoTitleField]; ok {
+		if title == nil {
+			return PatchToDoRequest{}, errors.New("title cannot be removed")
+		}
+		request.Title = title
+	}
+	if description, ok := document[model.ToDoDescriptionField]; ok {
+		if description == nil {
+			description = new(string)
+		}
+		request.Description = description
+	}
+	if priority, ok := document[model.ToDoPriorityField]; ok {
+		if priority == nil {
+			return PatchToDoRequest{}, errors.New("priority cannot be removed")
+		} else if !model.ToDoPriority(*priority).IsValid() {
+			return PatchToDoRequest{}, fmt.Errorf("unknown priority %q", *priority)
+		}
+		request.Priority = priority
+	}
+	if recurrence, ok := document[model.ToDoRecurrenceField]; ok {
+		if recurrence == nil {
+			recurrence = new(string)
+		} else if _, err := parseRecurrence(*recurrence); err != nil {
+			return PatchToDoRequest{}, err
+		}
+		request.Recurrence = recurrence
+	}
+	return request, nil
+}
+
+func findPatchableToDoField(name string) (model.ToDoField, error) {
+	for _, field := range patchableToDoFields {
+		if string(field) == name {
+			return field, nil
+		}
+	}
+	return "", fmt.Errorf("%s cannot be patched", name)
+}
+
+// findJSONPatchField reads the JSON pointer of an operation, which can only point to a member of the ToDo.
+func findJSONPatchField(path string) (model.ToDoField, error) {
+	if !strings.HasPrefix(path, "/") {
+		return "", fmt.Errorf("invalid path %q", path)
+	}
+	name := strings.NewReplacer("~1", "/", "~0", "~").Replace(path[1:])
+	return findPatchableToDoField(name)
+}
+
+func readJSONPatchValue(operation jsonPatchOperation) (string, error) {
+	if operation.Value == nil {
+		return "", fmt.Errorf("%s operation requires a value", operation.Op)
+	}
+	var value string
+	if err := json.Unmarshal(*operation.Value, &value); err != nil {
+		return "", fmt.Errorf("%s must be a string", operation.Path)
+	}
+	return value, nil
+}
@@ -0,0 +1,295 @@
+package dto
+
+import (
+	"event-bus-demo/domain/model"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+var patchedToDo = GetToDoResponse{
+	Title:       "Buy milk",
+	Description: "Two bottles",
+	Priority:    "medium",
+}
+
+func text(value string) *string {
+	return &value
+}
+
+func TestNewPatchToDoRequestFromJSONPatch(t *testing.T) {
+	tests := []struct {
+		name     string
+		patch    string
+		expected PatchToDoRequest
+	}{
+		{
+			name:     "test then replace",
+			patch:    `[{"op":"test","path":"/title","value":"Buy milk"},{"op":"replace","path":"/title","value":"Buy bread"}]`,
+			expected: PatchToDoRequest{Title: text("Buy bread")},
+		},
+		{
+			name:     "test of a value set by a previous operation",
+			patch:    `[{"op":"replace","path":"/priority","value":"high"},{"op":"test","path":"/priority","value":"high"}]`,
+			expected: PatchToDoRequest{Priority: text("high")},
+		},
+		{
+			name:     "move empties the source",
+			patch:    `[{"op":"move","from":"/description","path":"/title"}]`,
+			expected: PatchToDoRequest{Title: text("Two bottles"), Description: text("")},
+		},
+		{
+			name:     "move onto itself",
+			patch:    `[{"op":"move","from":"/title","path":"/title"}]`,
+			expected: PatchToDoRequest{Title: text("Buy milk")},
+		},
+		{
+			name:     "copy keeps the source",
+			patch:    `[{"op":"copy","from":"/title","path":"/description"}]`,
+			expected: PatchToDoRequest{Description: text("Buy milk")},
+		},
+		{
+			name:     "copy of a value set by a previous operation",
+			patch:    `[{"op":"add","path":"/title","value":"Buy bread"},{"op":"copy","from":"/title","path":"/description"}]`,
+			expected: PatchToDoRequest{Title: text("Buy bread"), Description: text("Buy bread")},
+		},
+		{
+			name:     "remove description",
+			patch:    `[{"op":"remove","path":"/description"}]`,
+			expected: PatchToDoRequest{Description: text("")},
+		},
+		{
+			name:     "add recurrence",
+			patch:    `[{"op":"add","path":"/recurrence","value":"FREQ=DAILY"}]`,
+			expected: PatchToDoRequest{Recurrence: text("FREQ=DAILY")},
+		},
+		{
+			name:     "no operation",
+			patch:    `[]`,
+			expected: PatchToDoRequest{},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			request, err := NewPatchToDoRequestFromJSONPatch([]byte(test.patch), patchedToDo)
+			if err != nil {
+				t.Fatalf("unexpected error %s", err.Error())
+			} else if !reflect.DeepEqual(request, test.expected) {
+				t.Errorf("expected %s, got %s", describePatch(test.expected), describePatch(request))
+			}
+		})
+	}
+}
+
+func TestNewPatchToDoRequestFromJSONPatchRejectsInvalidPatches(t *testing.T) {
+	tests := []struct {
+		name     string
+		patch    string
+		expected string
+	}{
+		{
+			name:     "not an array",
+			patch:    `{"op":"remove","path":"/description"}`,
+			expected: "JSON patch must be an array of operations",
+		},
+		{
+			name:     "failed test",
+			patch:    `[{"op":"replace","path":"/title","value":"Buy bread"},{"op":"test","path":"/title","value":"Buy milk"}]`,
+			expected: "operation 1: test of /title failed",
+		},
+		{
+			name:     "test of an unset member",
+			patch:    `[{"op":"test","path":"/recurrence","value":""}]`,
+			expected: "operation 0: test of /recurrence failed",
+		},
+		{
+			name:     "test without value",
+			patch:    `[{"op":"test","path":"/title"}]`,
+			expected: "operation 0: test operation requires a value",
+		},
+		{
+			name:     "move from an unset member",
+			patch:    `[{"op":"move","from":"/recurrence","path":"/description"}]`,
+			expected: "operation 0: /recurrence is not set",
+		},
+		{
+			name:     "copy from an unknown member",
+			patch:    `[{"op":"copy","from":"/status","path":"/title"}]`,
+			expected: "operation 0: status cannot be patched",
+		},
+		{
+			name:     "replace an unset member",
+			patch:    `[{"op":"replace","path":"/recurrence","value":"FREQ=DAILY"}]`,
+			expected: "operation 0: /recurrence is not set",
+		},
+		{
+			name:     "remove title",
+			patch:    `[{"op":"remove","path":"/title"}]`,
+			expected: "title cannot be removed",
+		},
+		{
+			name:     "moving the priority removes it",
+			patch:    `[{"op":"move","from":"/priority","path":"/description"}]`,
+			expected: "priority cannot be removed",
+		},
+		{
+			name:     "read only member",
+			patch:    `[{"op":"replace","path":"/status","value":"DONE"}]`,
+			expected: "operation 0: status cannot be patched",
+		},
+		{
+			name:     "nested path",
+			patch:    `[{"op":"remove","path":"/categories/0"}]`,
+			expected: "operation 0: categories/0 cannot be patched",
+		},
+		{
+			name:     "relative path",
+			patch:    `[{"op":"replace","path":"title","value":"Buy bread"}]`,
+			expected: `operation 0: invalid path "title"`,
+		},
+		{
+			name:     "unknown operation",
+			patch:    `[{"op":"increment","path":"/title","value":"Buy bread"}]`,
+			expected: `operation 0: unknown operation "increment"`,
+		},
+		{
+			name:     "value which is not a string",
+			patch:    `[{"op":"add","path":"/title","value":42}]`,
+			expected: "operation 0: /title must be a string",
+		},
+		{
+			name:     "unknown priority",
+			patch:    `[{"op":"replace","path":"/priority","value":"urgent"}]`,
+			expected: `unknown priority "urgent"`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			request, err := NewPatchToDoRequestFromJSONPatch([]byte(test.patch), patchedToDo)
+			if err == nil {
+				t.Fatalf("expected an error, got %s", describePatch(request))
+			} else if err.Error() != test.expected {
+				t.Errorf("expected error %q, got %q", test.expected, err.Error())
+			}
+		})
+	}
+}
+
+func TestNewPatchToDoRequestFromMergePatch(t *testing.T) {
+	tests := []struct {
+		name     string
+		patch    string
+		expected PatchToDoRequest
+	}{
+		{
+			name:     "changed members",
+			patch:    `{"title":"Buy bread","priority":"low"}`,
+			expected: PatchToDoRequest{Title: text("Buy bread"), Priority: text("low")},
+		},
+		{
+			name:     "null description is emptied",
+			patch:    `{"description":null}`,
+			expected: PatchToDoRequest{Description: text("")},
+		},
+		{
+			name:     "null recurrence stops recurring",
+			patch:    `{"recurrence":null}`,
+			expected: PatchToDoRequest{Recurrence: text("")},
+		},
+		{
+			name:     "empty object",
+			patch:    `{}`,
+			expected: PatchToDoRequest{},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			request, err := NewPatchToDoRequestFromMergePatch([]byte(test.patch))
+			if err != nil {
+				t.Fatalf("unexpected error %s", err.Error())
+			} else if !reflect.DeepEqual(request, test.expected) {
+				t.Errorf("expected %s, got %s", describePatch(test.expected), describePatch(request))
+			}
+		})
+	}
+}
+
+func TestNewPatchToDoRequestFromMergePatchRejectsInvalidPatches(t *testing.T) {
+	tests := []struct {
+		name     string
+		patch    string
+		expected string
+	}{
+		{
+			name:     "not an object",
+			patch:    `[{"op":"remove","path":"/description"}]`,
+			expected: "merge patch must be a JSON object",
+		},
+		{
+			name:     "null document",
+			patch:    `null`,
+			expected: "merge patch must be a JSON object",
+		},
+		{
+			name:     "null title",
+			patch:    `{"title":null}`,
+			expected: "title cannot be removed",
+		},
+		{
+			name:     "null priority",
+			patch:    `{"priority":null}`,
+			expected: "priority cannot be removed",
+		},
+		{
+			name:     "unknown member",
+			patch:    `{"dueAt":"2022-01-01T00:00:00Z"}`,
+			expected: "dueAt cannot be patched",
+		},
+		{
+			name:     "value which is not a string",
+			patch:    `{"description":{"text":"Two bottles"}}`,
+			expected: "description must be a string",
+		},
+		{
+			name:     "invalid recurrence",
+			patch:    `{"recurrence":"FREQ=HOURLY"}`,
+			expected: "unsupported recurrence frequency HOURLY",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			request, err := NewPatchToDoRequestFromMergePatch([]byte(test.patch))
+			if err == nil {
+				t.Fatalf("expected an error, got %s", describePatch(request))
+			} else if !strings.Contains(err.Error(), test.expected) {
+				t.Errorf("expected an error containing %q, got %q", test.expected, err.Error())
+			}
+		})
+	}
+}
+
+func TestPatchToDoRequestToEventChangesPatchedFieldsOnly(t *testing.T) {
+	expectedVersion := int64(3)
+	request := PatchToDoRequest{Description: text(""), Priority: text("high")}
+	event := request.ToEvent(patchedToDo.ID, &expectedVersion)
+	expected := []model.ToDoField{model.ToDoDescriptionField, model.ToDoPriorityField}
+	if !reflect.DeepEqual(event.Fields, expected) {
+		t.Fatalf("expected the description and priority to be changed, got %v", event.Fields)
+	}
+	if event.Description != "" || event.Priority != model.HighToDoPriority || event.ExpectedVersion != &expectedVersion {
+		t.Errorf("expected the patched values and version, got %+v", event)
+	}
+}
+
+func describePatch(request PatchToDoRequest) string {
+	members := make([]string, 0)
+	for name, value := range map[string]*string{"title": request.Title, "description": request.Description,
+		"priority": request.Priority, "recurrence": request.Recurrence} {
+		if value != nil {
+			members = append(members, name+"="+*value)
+		}
+	}
+	sort.Strings(members)
+	return "{" + strings.Join(members, ", ") + "}"
+}
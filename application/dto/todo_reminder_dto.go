@@ -0,0 +1,53 @@
+package dto
+
+import (
+	"errors"
+	"event-bus-demo/domain/model"
+	"github.com/google/uuid"
+	"time"
+)
+
+// CreateToDoReminderRequest adds a reminder sent some time before the ToDo is due. Before is a duration such as 30m or
+// 24h, 0s reminding at the due date itself.
+type CreateToDoReminderRequest struct {
+	Before string `json:"before" binding:"required"`
+}
+
+// ParseBefore reads the time before the due date, which must be a positive or zero number of whole seconds.
+func (req CreateToDoReminderRequest) ParseBefore() (time.Duration, error) {
+	before, err := time.ParseDuration(req.Before)
+	if err != nil {
+		return 0, errors.New("before must be a duration such as 30m or 24h")
+	} else if before < 0 || before%time.Second != 0 {
+		return 0, errors.New("before must be a positive or zero number of whole seconds")
+	}
+	return before, nil
+}
+
+func (req CreateToDoReminderRequest) ToEvent(toDoID uuid.UUID, ID uuid.UUID, before time.Duration) model.CreateToDoReminderEvent {
+	return model.CreateToDoReminderEvent{
+		ID:        ID,
+		ToDoID:    toDoID,
+		BeforeDue: before,
+		CreatedAt: time.Now(),
+	}
+}
+
+// GetToDoReminderResponse is a reminder of a ToDo. RemindAt is only given while the ToDo is due, and Sent tells whether
+// the reminder was already sent for its current due date.
+type GetToDoReminderResponse struct {
+	ID        uuid.UUID  `json:"id" binding:"required"`
+	Before    string     `json:"before" binding:"required"`
+	RemindAt  *time.Time `json:"remindAt,omitempty"`
+	Sent      bool       `json:"sent"`
+	SentAt    *time.Time `json:"sentAt,omitempty"`
+	CreatedAt time.Time  `json:"createdAt" binding:"required"`
+}
+
+type GetToDoRemindersResponse struct {
+	Reminders []GetToDoReminderResponse `json:"reminders" binding:"required"`
+}
+
+type CreateToDoReminderResponse struct {
+	ID uuid.UUID `json:"id" binding:"required"`
+}
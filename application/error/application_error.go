@@ -56,6 +56,13 @@ func NewNotFoundError(message string) ApplicationError {
 	}
 }
 
+func NewConflictError(message string) ApplicationError {
+	return &applicationError{
+		Code:    http.StatusConflict,
+		Message: message,
+	}
+}
+
 func NewPreconditionFailedError(message string) ApplicationError {
 	return &applicationError{
 		Code:    http.StatusPreconditionFailed,
@@ -69,3 +76,10 @@ func NewInternalServerError(message string) ApplicationError {
 		Message: message,
 	}
 }
+
+func NewServiceUnavailableError(message string) ApplicationError {
+	return &applicationError{
+		Code:    http.StatusServiceUnavailable,
+		Message: message,
+	}
+}
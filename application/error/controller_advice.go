@@ -26,6 +26,16 @@ func (advice *controllerAdvice) TranslateError(err errorDomain.DomainError) Appl
 		return NewInternalServerError("error while processing information")
 	case errorDomain.CryptographicError:
 		return NewInternalServerError("error while performing encryption/decryption")
+	case errorDomain.InvalidCredentials:
+		return NewUnauthorizedError(err.GetMessage())
+	case errorDomain.InvalidArgument:
+		return NewBadRequestError(err.GetMessage())
+	case errorDomain.ServiceUnavailable:
+		return NewServiceUnavailableError(err.GetMessage())
+	case errorDomain.Conflict:
+		return NewConflictError(err.GetMessage())
+	case errorDomain.VersionMismatch:
+		return NewPreconditionFailedError(err.GetMessage())
 	case errorDomain.GenericError:
 		return NewInternalServerError("unhandled error in domain model")
 	default:
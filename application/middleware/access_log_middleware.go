@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"event-bus-demo/infrastructure/configuration"
+	"event-bus-demo/infrastructure/logging"
+	"event-bus-demo/infrastructure/util"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"math/rand"
+	"net/http"
+	"time"
+)
+
+// NewAccessLogMiddleware logs every served request through zap. Requests finished with an error status are always
+// logged, while successful ones are sampled with the configured rate. Excluded paths are never logged.
+func NewAccessLogMiddleware(config configuration.AccessLogConfiguration, logger *zap.Logger) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if util.Contains[string](config.ExcludedPaths, ctx.Request.URL.Path) {
+			ctx.Next()
+			return
+		}
+		start := time.Now()
+		ctx.Next()
+		status := ctx.Writer.Status()
+		if status < http.StatusBadRequest && rand.Float64() >= *config.SampleRate {
+			return
+		}
+		route := ctx.FullPath()
+		if route == "" {
+			route = unmatchedRoute
+		}
+		fields := []zap.Field{
+			zap.String("method", ctx.Request.Method),
+			zap.String("route", route),
+			zap.String("path", ctx.Request.URL.Path),
+			zap.Int("status", status),
+			zap.Duration("latency", time.Since(start)),
+			zap.String("client_ip", ctx.ClientIP()),
+			zap.Int64("request_size", ctx.Request.ContentLength),
+			zap.Int("response_size", ctx.Writer.Size()),
+		}
+		requestLogger := logging.FromContext(ctx.Request.Context(), logger)
+		if status >= http.StatusInternalServerError {
+			requestLogger.Error("request served", fields...)
+		} else if status >= http.StatusBadRequest {
+			requestLogger.Warn("request served", fields...)
+		} else {
+			requestLogger.Info("request served", fields...)
+		}
+	}
+}
@@ -0,0 +1,21 @@
+package middleware
+
+import (
+	"event-bus-demo/infrastructure/metrics"
+	"github.com/gin-gonic/gin"
+	"time"
+)
+
+const unmatchedRoute = "unmatched"
+
+func NewMetricsMiddleware(httpMetrics metrics.HTTPMetrics) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		start := time.Now()
+		ctx.Next()
+		route := ctx.FullPath()
+		if route == "" {
+			route = unmatchedRoute
+		}
+		httpMetrics.ObserveRequest(ctx.Request.Method, route, ctx.Writer.Status(), time.Since(start))
+	}
+}
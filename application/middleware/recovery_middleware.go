@@ -0,0 +1,26 @@
+package middleware
+
+import (
+	"event-bus-demo/infrastructure/logging"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"net/http"
+)
+
+func NewRecoveryMiddleware(logger *zap.Logger) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				logging.FromContext(ctx.Request.Context(), logger).Error("panic recovered while serving request",
+					zap.Any("panic", recovered),
+					zap.String("method", ctx.Request.Method),
+					zap.String("path", ctx.Request.URL.Path),
+					zap.Stack("stack"))
+				ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+					"message": "internal server error",
+				})
+			}
+		}()
+		ctx.Next()
+	}
+}
@@ -0,0 +1,26 @@
+package middleware
+
+import (
+	"event-bus-demo/infrastructure/constants"
+	"event-bus-demo/infrastructure/logging"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const maxRequestIDLength = 128
+
+func NewRequestIDMiddleware(logger *zap.Logger) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		requestID := ctx.GetHeader(constants.RequestIDHeader)
+		if requestID == "" || len(requestID) > maxRequestIDLength {
+			requestID = uuid.NewString()
+		}
+		ctx.Header(constants.RequestIDHeader, requestID)
+		ctx.Set(logging.RequestIDField, requestID)
+		requestLogger := logger.With(zap.String(logging.RequestIDField, requestID))
+		requestCtx := logging.WithLogger(logging.WithRequestID(ctx.Request.Context(), requestID), requestLogger)
+		ctx.Request = ctx.Request.WithContext(requestCtx)
+		ctx.Next()
+	}
+}
@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"event-bus-demo/application/error"
+	"event-bus-demo/domain/service"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+const authenticationRealm = `Basic realm="event-bus-demo"`
+
+// NewRoleAuthMiddleware authenticates requests with HTTP basic credentials of the USERS table and only lets through
+// the users with the given role.
+func NewRoleAuthMiddleware(userReadService service.UserReadService, controllerAdvice error.ControllerAdvice, role string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		username, password, ok := ctx.Request.BasicAuth()
+		if !ok {
+			ctx.Header("WWW-Authenticate", authenticationRealm)
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"message": "authentication required",
+			})
+			return
+		}
+		user, err := userReadService.AuthenticateUser(ctx.Request.Context(), username, password)
+		if err != nil {
+			httpError := controllerAdvice.TranslateError(err)
+			if httpError.GetCode() == http.StatusUnauthorized {
+				ctx.Header("WWW-Authenticate", authenticationRealm)
+			}
+			ctx.AbortWithStatusJSON(httpError.GetCode(), gin.H{
+				"message": httpError.GetMessage(),
+			})
+			return
+		}
+		if user.Role != role {
+			ctx.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"message": "user is not allowed to access this resource",
+			})
+			return
+		}
+		ctx.Next()
+	}
+}
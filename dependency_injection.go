@@ -1,8 +1,11 @@
 package main
 
 import (
+	"database/sql"
+	"errors"
 	"event-bus-demo/application/controller"
 	applicationError "event-bus-demo/application/error"
+	"event-bus-demo/application/middleware"
 	domainError "event-bus-demo/domain/error"
 	"event-bus-demo/domain/event"
 	"event-bus-demo/domain/model"
@@ -12,21 +15,41 @@ import (
 	"event-bus-demo/infrastructure/database/repository"
 	dbService "event-bus-demo/infrastructure/database/service"
 	"event-bus-demo/infrastructure/event_sourcing"
+	"event-bus-demo/infrastructure/health"
+	"event-bus-demo/infrastructure/metrics"
+	"event-bus-demo/infrastructure/notification"
+	"event-bus-demo/infrastructure/resilience"
+	"event-bus-demo/infrastructure/search"
+	"event-bus-demo/infrastructure/tracing"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"go.uber.org/zap"
+	"net/http"
+	"time"
 )
 
 type RequiredDependencies struct {
-	EventBus            event_sourcing.EventBus
-	RequiredControllers RequiredControllers
+	Logger                *zap.Logger
+	Database              *sql.DB
+	ToDoSearchIndex       search.ToDoSearchIndex
+	EventBus              event_sourcing.EventBus
+	ToDoReminderScheduler event.ToDoReminderScheduler
+	HTTPMetrics           metrics.HTTPMetrics
+	MetricsHandler        http.Handler
+	AdminMiddleware       gin.HandlerFunc
+	CircuitBreakers       []resilience.CircuitBreaker
+	RequiredControllers   RequiredControllers
 }
 
 type RequiredControllers struct {
 	ToDoController     controller.ToDoController
 	CategoryController controller.CategoryController
 	UserController     controller.UserController
+	AdminController    controller.AdminController
+	HealthController   controller.HealthController
 }
 
-func initializeDependencies(config configuration.ApplicationConfiguration) (RequiredDependencies, error) {
+func initializeDependencies(profiles []string, config configuration.ApplicationConfiguration) (RequiredDependencies, error) {
 	env, err := constants.NewEnvironment(*config.Gin.Environment)
 	if err != nil {
 		return RequiredDependencies{}, err
@@ -42,12 +65,82 @@ func initializeDependencies(config configuration.ApplicationConfiguration) (Requ
 		return RequiredDependencies{}, err
 	}
 
+	// Tracing
+	if *config.Tracing.Enabled {
+		if _, err := tracing.InitializeTracerProvider(*config.Tracing); err != nil {
+			return RequiredDependencies{}, err
+		}
+	}
+
+	// Metrics
+	var httpMetrics metrics.HTTPMetrics
+	var metricsHandler http.Handler
+	eventBusMetrics := event_sourcing.NewNoOpEventBusMetrics()
+	circuitBreakerObserver := resilience.NewNoOpCircuitBreakerObserver()
+	if *config.Metrics.Enabled {
+		registry := metrics.NewRegistry()
+		eventBusMetrics = metrics.NewEventBusMetrics(registry)
+		circuitBreakerObserver = metrics.NewCircuitBreakerMetrics(registry)
+		httpMetrics = metrics.NewHTTPMetrics(registry)
+		metrics.RegisterDatabaseMetrics(registry, connectionPool, *config.Rdbms.Database)
+		metricsHandler = metrics.NewMetricsHandler(registry)
+	}
+
 	// Event bus
-	eventChannel := event_sourcing.NewBufferedEventChannel(*config.Event.ChannelBufferSize)
-	eventBus := event_sourcing.NewEventBus(eventChannel, *config.Event.MaxWorkers, logger)
+	eventBus := event_sourcing.NewEventBus(*config.Event.ChannelBufferSize, *config.Event.MaxWorkers, eventBusMetrics, logger)
+	handlerTimeout, err := time.ParseDuration(*config.Event.HandlerTimeout)
+	if err != nil {
+		return RequiredDependencies{}, err
+	}
+	eventBus.SetHandlerTimeout(handlerTimeout)
+	for topic, topicConfig := range config.Event.Topics {
+		topicOptions := event_sourcing.TopicOptions{
+			MaxWorkers: *topicConfig.MaxWorkers,
+			Weight:     *topicConfig.Weight,
+		}
+		if topicConfig.HandlerTimeout != nil {
+			if topicOptions.HandlerTimeout, err = time.ParseDuration(*topicConfig.HandlerTimeout); err != nil {
+				return RequiredDependencies{}, err
+			}
+		}
+		eventBus.ConfigureTopic(topic, topicOptions)
+	}
+	priorityOptions := event_sourcing.PriorityOptions{
+		Events:              make(map[string]event_sourcing.Priority),
+		StarvationThreshold: *config.Event.Priorities.StarvationThreshold,
+	}
+	for eventName, priorityName := range config.Event.Priorities.Events {
+		if priorityOptions.Events[eventName], err = event_sourcing.ParsePriority(priorityName); err != nil {
+			return RequiredDependencies{}, err
+		}
+	}
+	eventBus.ConfigurePriorities(priorityOptions)
+
+	// Circuit breakers
+	circuitBreakers := make([]resilience.CircuitBreaker, 0)
+	newCircuitBreaker := func(name string, breakerConfig configuration.CircuitBreakerConfiguration) (resilience.CircuitBreaker, error) {
+		openTimeout, err := time.ParseDuration(*breakerConfig.OpenTimeout)
+		if err != nil {
+			return nil, err
+		}
+		breaker := resilience.NewCircuitBreaker(name, resilience.CircuitBreakerOptions{
+			FailureThreshold: *breakerConfig.FailureThreshold,
+			OpenTimeout:      openTimeout,
+			HalfOpenProbes:   *breakerConfig.HalfOpenProbes,
+		}, circuitBreakerObserver, logger)
+		circuitBreakers = append(circuitBreakers, breaker)
+		return breaker, nil
+	}
 
 	// Repository
 	transactionalRepository := repository.NewTransactionalRepository(logger, connectionPool)
+	if *config.Rdbms.CircuitBreaker.Enabled {
+		databaseBreaker, err := newCircuitBreaker("database", *config.Rdbms.CircuitBreaker)
+		if err != nil {
+			return RequiredDependencies{}, err
+		}
+		transactionalRepository = repository.NewCircuitBreakerTransactionalRepository(transactionalRepository, databaseBreaker)
+	}
 	toDoRepository := repository.NewToDoRepository(logger, connectionPool)
 	categoryRepository := repository.NewCategoryRepository(logger, connectionPool)
 	userRepository := repository.NewUserRepository(logger, connectionPool)
@@ -57,45 +150,229 @@ func initializeDependencies(config configuration.ApplicationConfiguration) (Requ
 	categoryDatabaseService := dbService.NewCategoryDatabaseService(transactionalRepository, categoryRepository)
 	userDatabaseService := dbService.NewUserDatabaseService(transactionalRepository, userRepository)
 
+	// Search index
+	var toDoSearchIndex search.ToDoSearchIndex
+	if *config.Search.Backend == search.MemoryBackend {
+		toDoSearchIndex = search.NewMemoryToDoSearchIndex(toDoDatabaseService, logger)
+	} else {
+		toDoSearchIndex = search.NewPostgresToDoSearchIndex(toDoDatabaseService)
+	}
+
 	// Domain service
+	categoryDeletionPolicy := model.CategoryDeletionPolicy{
+		Mode: model.CategoryDeletionMode(*config.Category.DeletionPolicy),
+	}
+	if categoryDeletionPolicy.Mode == model.ReassignCategoryDeletion {
+		if config.Category.DefaultCategoryID == nil {
+			return RequiredDependencies{}, errors.New("category.default-category-id is required by the reassign deletion policy")
+		} else if categoryDeletionPolicy.DefaultCategoryID, err = uuid.Parse(*config.Category.DefaultCategoryID); err != nil {
+			return RequiredDependencies{}, err
+		}
+	}
 	domainAdvice := domainError.NewDomainAdvice()
-	toDoReadService := service.NewToDoReadService(toDoDatabaseService, domainAdvice, logger)
+	toDoReadService := service.NewToDoReadService(toDoDatabaseService, toDoSearchIndex, domainAdvice, logger)
 	toDoWriteService := service.NewToDoWriteService(toDoDatabaseService, domainAdvice, logger)
-	categoryReadService := service.NewCategoryReadService(categoryDatabaseService, domainAdvice, logger)
-	categoryWriteService := service.NewCategoryWriteService(categoryDatabaseService, domainAdvice, logger)
+	categoryReadService := service.NewCategoryReadService(categoryDatabaseService, categoryDeletionPolicy, domainAdvice, logger)
+	categoryWriteService := service.NewCategoryWriteService(categoryDatabaseService, categoryDeletionPolicy, domainAdvice, logger)
 	userReadService := service.NewUserReadService(userDatabaseService, domainAdvice, logger)
 	userWriteService := service.NewUserWriteService(userDatabaseService, domainAdvice, logger)
 
 	// Event handler
-	toDoEventHandler := event.NewToDoEventHandler(toDoWriteService, logger)
-	categoryEventHandler := event.NewCategoryEventHandler(categoryWriteService, logger)
-	userEventHandler := event.NewUserEventHandler(userWriteService, logger)
+	toDoEventHandler, err := event.NewToDoEventHandler(toDoWriteService, logger)
+	if err != nil {
+		return RequiredDependencies{}, err
+	}
+	categoryEventHandler, err := event.NewCategoryEventHandler(categoryWriteService, logger)
+	if err != nil {
+		return RequiredDependencies{}, err
+	}
+	userEventHandler, err := event.NewUserEventHandler(userWriteService, logger)
+	if err != nil {
+		return RequiredDependencies{}, err
+	}
+	reminderEventHandler, err := event.NewReminderEventHandler(toDoWriteService, logger)
+	if err != nil {
+		return RequiredDependencies{}, err
+	}
+
+	if *config.Event.CircuitBreaker.Enabled {
+		mode := event_sourcing.HoldCircuitBreakerMode
+		if config.Event.CircuitBreaker.Mode != nil {
+			mode = event_sourcing.CircuitBreakerMode(*config.Event.CircuitBreaker.Mode)
+		}
+		withCircuitBreaker := func(topic string, handler event_sourcing.EventHandler) (event_sourcing.EventHandler, error) {
+			breaker, err := newCircuitBreaker(topic+" handler", *config.Event.CircuitBreaker)
+			if err != nil {
+				return nil, err
+			}
+			return event_sourcing.NewCircuitBreakerHandler(handler, breaker, mode, event.IsDatabaseFailure), nil
+		}
+		if toDoEventHandler, err = withCircuitBreaker(model.ToDoEventTopic, toDoEventHandler); err != nil {
+			return RequiredDependencies{}, err
+		}
+		if categoryEventHandler, err = withCircuitBreaker(model.CategoryEventTopic, categoryEventHandler); err != nil {
+			return RequiredDependencies{}, err
+		}
+		if userEventHandler, err = withCircuitBreaker(model.UserEventTopic, userEventHandler); err != nil {
+			return RequiredDependencies{}, err
+		}
+		if reminderEventHandler, err = withCircuitBreaker(model.ReminderEventTopic, reminderEventHandler); err != nil {
+			return RequiredDependencies{}, err
+		}
+	}
+
+	// Operations
+	eventBusAdminService := service.NewEventBusAdminService(eventBus, circuitBreakers, domainAdvice, logger)
+	healthTimeout, err := time.ParseDuration(*config.Health.Timeout)
+	if err != nil {
+		return RequiredDependencies{}, err
+	}
+	healthService := service.NewHealthService(healthTimeout, []health.Checker{
+		health.NewConfigurationChecker(profiles),
+		health.NewDatabaseChecker(connectionPool),
+		health.NewEventBusChecker(eventBus, *config.Health.QueueSaturationThreshold),
+		health.NewCircuitBreakerChecker(circuitBreakers),
+	}, logger)
 
 	// Event Subscriber
 	loggerSubscriber := event.NewEventLoggerSubscriber(logger)
+	toDoSearchSubscriber := event.NewToDoSearchSubscriber(toDoDatabaseService, toDoSearchIndex, logger)
+	toDoRecurrenceSubscriber := event.NewToDoRecurrenceSubscriber(eventBus, toDoDatabaseService, logger)
+	reminderNotifiers, err := newReminderNotifiers(*config.Reminder.Notifiers, logger)
+	if err != nil {
+		return RequiredDependencies{}, err
+	}
+	var toDoReminderScheduler event.ToDoReminderScheduler
+	if *config.Reminder.Enabled {
+		pollInterval, err := time.ParseDuration(*config.Reminder.PollInterval)
+		if err != nil {
+			return RequiredDependencies{}, err
+		}
+		toDoReminderScheduler = event.NewToDoReminderScheduler(eventBus, toDoDatabaseService, pollInterval,
+			*config.Reminder.BatchSize, logger)
+	}
 
 	// Controller
 	controllerAdvice := applicationError.NewControllerAdvice()
 	toDoController := controller.NewTodoController(eventBus, toDoReadService, categoryReadService, controllerAdvice)
 	categoryController := controller.NewCategoryController(eventBus, categoryReadService, controllerAdvice)
 	userController := controller.NewUserController(eventBus, userReadService, controllerAdvice)
+	adminController := controller.NewAdminController(eventBusAdminService, controllerAdvice)
+	healthController := controller.NewHealthController(healthService)
+
+	// Register published events on eventBus
+	eventBus.RegisterEvents(
+		model.CreateToDoEvent{},
+		model.UpdateToDoEvent{},
+		model.DeleteToDoEvent{},
+		model.AddCategoriesFromToDoEvent{},
+		model.RemoveCategoriesFromToDoEvent{},
+		model.CompleteToDoEvent{},
+		model.ReopenToDoEvent{},
+		model.StartToDoEvent{},
+		model.CancelToDoEvent{},
+		model.RescheduleToDoEvent{},
+		model.CreateToDoItemEvent{},
+		model.UpdateToDoItemEvent{},
+		model.DeleteToDoItemEvent{},
+		model.ReorderToDoItemsEvent{},
+		model.CreateToDoReminderEvent{},
+		model.DeleteToDoReminderEvent{},
+		model.ToDoReminderDueEvent{},
+		model.CreateCategoryEvent{},
+		model.UpdateCategoryNameEvent{},
+		model.DeleteCategoryEvent{},
+		model.CreateUserEvent{},
+		model.UpdateUserPasswordEvent{},
+		model.DeleteUserEvent{},
+	)
 
 	// Register handlers on eventBus
 	eventBus.RegisterHandler(model.ToDoEventTopic, toDoEventHandler)
 	eventBus.RegisterHandler(model.CategoryEventTopic, categoryEventHandler)
 	eventBus.RegisterHandler(model.UserEventTopic, userEventHandler)
+	eventBus.RegisterHandler(model.ReminderEventTopic, reminderEventHandler)
 
 	// Register subscribers on eventBus
 	eventBus.RegisterSubscriber(model.ToDoEventTopic, loggerSubscriber)
 	eventBus.RegisterSubscriber(model.CategoryEventTopic, loggerSubscriber)
 	eventBus.RegisterSubscriber(model.UserEventTopic, loggerSubscriber)
+	eventBus.RegisterSubscriber(model.ReminderEventTopic, loggerSubscriber)
+	eventBus.RegisterSubscriber(model.ToDoEventTopic, toDoRecurrenceSubscriber)
+	for _, notifier := range reminderNotifiers {
+		eventBus.RegisterSubscriber(model.ReminderEventTopic, event.NewToDoReminderSubscriber(notifier, logger))
+	}
+	if toDoReminderScheduler != nil {
+		eventBus.RegisterSubscriber(model.ToDoEventTopic, toDoReminderScheduler)
+		eventBus.RegisterSubscriber(model.ReminderEventTopic, toDoReminderScheduler)
+	}
+	if *config.Search.Backend == search.MemoryBackend {
+		eventBus.RegisterSubscriber(model.ToDoEventTopic, toDoSearchSubscriber)
+		eventBus.RegisterSubscriber(model.CategoryEventTopic, toDoSearchSubscriber)
+	}
+
+	if err := eventBus.Validate(); err != nil {
+		return RequiredDependencies{}, err
+	}
 
 	return RequiredDependencies{
-		EventBus: eventBus,
+		Logger:                logger,
+		Database:              connectionPool,
+		ToDoSearchIndex:       toDoSearchIndex,
+		EventBus:              eventBus,
+		ToDoReminderScheduler: toDoReminderScheduler,
+		HTTPMetrics:           httpMetrics,
+		MetricsHandler:        metricsHandler,
+		CircuitBreakers:       circuitBreakers,
+		AdminMiddleware:       middleware.NewRoleAuthMiddleware(userReadService, controllerAdvice, model.AdminRole),
 		RequiredControllers: RequiredControllers{
 			ToDoController:     toDoController,
 			CategoryController: categoryController,
 			UserController:     userController,
+			AdminController:    adminController,
+			HealthController:   healthController,
 		},
 	}, nil
 }
+
+// newReminderNotifiers builds the notifiers enabled by the configuration, each one delivering every due reminder.
+func newReminderNotifiers(config configuration.ReminderNotifiersConfiguration, logger *zap.Logger) ([]notification.Notifier, error) {
+	notifiers := make([]notification.Notifier, 0)
+	if *config.Log.Enabled {
+		notifiers = append(notifiers, notification.NewLogNotifier(logger))
+	}
+	if *config.Webhook.Enabled {
+		if config.Webhook.URL == nil {
+			return nil, errors.New("reminder.notifiers.webhook.url is required by the webhook notifier")
+		}
+		timeout, err := time.ParseDuration(*config.Webhook.Timeout)
+		if err != nil {
+			return nil, err
+		}
+		notifiers = append(notifiers, notification.NewWebhookNotifier(*config.Webhook.URL, timeout))
+	}
+	if *config.SMTP.Enabled {
+		if len(config.SMTP.To) == 0 {
+			return nil, errors.New("reminder.notifiers.smtp.to requires at least one recipient")
+		}
+		timeout, err := time.ParseDuration(*config.SMTP.Timeout)
+		if err != nil {
+			return nil, err
+		}
+		options := notification.SMTPOptions{
+			Host:    *config.SMTP.Host,
+			Port:    *config.SMTP.Port,
+			From:    *config.SMTP.From,
+			To:      config.SMTP.To,
+			Timeout: timeout,
+		}
+		if config.SMTP.Username != nil {
+			options.Username = *config.SMTP.Username
+		}
+		if config.SMTP.Password != nil {
+			options.Password = *config.SMTP.Password
+		}
+		notifiers = append(notifiers, notification.NewSMTPNotifier(options))
+	}
+	return notifiers, nil
+}
@@ -28,6 +28,14 @@ func (advice *domainAdvice) TranslateError(err errorInfrastructure.Infrastructur
 		return NewConfigurationError("error while parsing configuration file")
 	case errorInfrastructure.HashingError:
 		return NewCryptoError("error while hashing password")
+	case errorInfrastructure.InvalidArgument:
+		return NewInvalidArgumentError(err.GetMessage())
+	case errorInfrastructure.CircuitOpen:
+		return NewServiceUnavailableError(err.GetMessage())
+	case errorInfrastructure.Conflict:
+		return NewConflictError(err.GetMessage())
+	case errorInfrastructure.VersionMismatch:
+		return NewVersionMismatchError(err.GetMessage())
 	default:
 		return NewGenericError(err.GetMessage())
 	}
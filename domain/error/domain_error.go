@@ -12,6 +12,11 @@ const (
 	ConfigurationError DomainErrorCode = "CONFIGURATION_ERROR"
 	CryptographicError DomainErrorCode = "CRYPTO_ERROR"
 	GenericError       DomainErrorCode = "GENERIC_ERROR"
+	InvalidCredentials DomainErrorCode = "INVALID_CREDENTIALS"
+	InvalidArgument    DomainErrorCode = "INVALID_ARGUMENT"
+	ServiceUnavailable DomainErrorCode = "SERVICE_UNAVAILABLE"
+	Conflict           DomainErrorCode = "CONFLICT"
+	VersionMismatch    DomainErrorCode = "VERSION_MISMATCH"
 )
 
 type DomainError interface {
@@ -71,3 +76,38 @@ func NewCryptoError(message string) DomainError {
 		Message: message,
 	}
 }
+
+func NewInvalidCredentialsError(message string) DomainError {
+	return &domainError{
+		Code:    InvalidCredentials,
+		Message: message,
+	}
+}
+
+func NewInvalidArgumentError(message string) DomainError {
+	return &domainError{
+		Code:    InvalidArgument,
+		Message: message,
+	}
+}
+
+func NewServiceUnavailableError(message string) DomainError {
+	return &domainError{
+		Code:    ServiceUnavailable,
+		Message: message,
+	}
+}
+
+func NewConflictError(message string) DomainError {
+	return &domainError{
+		Code:    Conflict,
+		Message: message,
+	}
+}
+
+func NewVersionMismatchError(message string) DomainError {
+	return &domainError{
+		Code:    VersionMismatch,
+		Message: message,
+	}
+}
@@ -1,10 +1,10 @@
 package event
 
 import (
+	"context"
 	"event-bus-demo/domain/model"
 	"event-bus-demo/domain/service"
 	"event-bus-demo/infrastructure/event_sourcing"
-	"fmt"
 	"go.uber.org/zap"
 )
 
@@ -13,25 +13,30 @@ type categoryEventHandler struct {
 	logger          *zap.Logger
 }
 
-func NewCategoryEventHandler(categoryService service.CategoryWriteService, logger *zap.Logger) event_sourcing.EventHandler {
+func NewCategoryEventHandler(categoryService service.CategoryWriteService, logger *zap.Logger) (event_sourcing.EventHandler, error) {
 	eventHandler := &categoryEventHandler{
 		categoryService: categoryService,
 		logger:          logger,
 	}
-	return eventHandler
+	handler := event_sourcing.NewTypedEventHandler(model.CategoryEventTopic, logger)
+	if err := event_sourcing.Handle(handler, eventHandler.handleCreateCategory); err != nil {
+		return nil, err
+	} else if err := event_sourcing.Handle(handler, eventHandler.handleUpdateCategoryName); err != nil {
+		return nil, err
+	} else if err := event_sourcing.Handle(handler, eventHandler.handleDeleteCategory); err != nil {
+		return nil, err
+	}
+	return handler, nil
 }
 
-func (handler *categoryEventHandler) Handle(event event_sourcing.Event) event_sourcing.EventResult {
-	var err error
-	switch event.(type) {
-	case model.CreateCategoryEvent:
-		err = handler.categoryService.AddUser(event.(model.CreateCategoryEvent))
-	case model.UpdateCategoryNameEvent:
-		err = handler.categoryService.UpdateUser(event.(model.UpdateCategoryNameEvent))
-	case model.DeleteCategoryEvent:
-		err = handler.categoryService.DeleteUser(event.(model.DeleteCategoryEvent))
-	default:
-		err = fmt.Errorf("unknown event")
-	}
-	return HandleError(handler.logger, event, err)
+func (handler *categoryEventHandler) handleCreateCategory(ctx context.Context, event model.CreateCategoryEvent) error {
+	return handler.categoryService.AddUser(ctx, event)
+}
+
+func (handler *categoryEventHandler) handleUpdateCategoryName(ctx context.Context, event model.UpdateCategoryNameEvent) error {
+	return handler.categoryService.UpdateUser(ctx, event)
+}
+
+func (handler *categoryEventHandler) handleDeleteCategory(ctx context.Context, event model.DeleteCategoryEvent) error {
+	return handler.categoryService.DeleteUser(ctx, event)
 }
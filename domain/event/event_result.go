@@ -0,0 +1,20 @@
+package event
+
+import (
+	"errors"
+	domainError "event-bus-demo/domain/error"
+	"event-bus-demo/infrastructure/event_sourcing"
+)
+
+// IsDatabaseFailure tells whether the event could not be handled because the database failed or did not answer in
+// time, which is what handler circuit breakers count.
+func IsDatabaseFailure(result event_sourcing.EventResult) bool {
+	if result.Succeeded {
+		return false
+	} else if result.FailureKind == event_sourcing.TimeoutFailure {
+		return true
+	}
+	var err domainError.DomainError
+	return errors.As(result.Error, &err) &&
+		(err.GetCode() == domainError.DatabaseError || err.GetCode() == domainError.ServiceUnavailable)
+}
@@ -1,7 +1,9 @@
 package event
 
 import (
+	"event-bus-demo/infrastructure/constants"
 	"event-bus-demo/infrastructure/event_sourcing"
+	"event-bus-demo/infrastructure/logging"
 	"go.uber.org/zap"
 )
 
@@ -16,5 +18,6 @@ func NewEventLoggerSubscriber(logger *zap.Logger) event_sourcing.EventSubscriber
 }
 
 func (subscriber *eventLoggerSubscriber) Notify(result event_sourcing.EventResult) {
-	subscriber.logger.Info("Subscriber received result", zap.Any("result", result))
+	subscriber.logger.Info("Subscriber received result", zap.Any("result", result),
+		zap.String(logging.RequestIDField, result.Metadata[constants.RequestIDMetadataKey]))
 }
@@ -0,0 +1,30 @@
+package event
+
+import (
+	"context"
+	"event-bus-demo/domain/model"
+	"event-bus-demo/domain/service"
+	"event-bus-demo/infrastructure/event_sourcing"
+	"go.uber.org/zap"
+)
+
+type reminderEventHandler struct {
+	toDoService service.ToDoWriteService
+	logger      *zap.Logger
+}
+
+func NewReminderEventHandler(toDoService service.ToDoWriteService, logger *zap.Logger) (event_sourcing.EventHandler, error) {
+	eventHandler := &reminderEventHandler{
+		toDoService: toDoService,
+		logger:      logger,
+	}
+	handler := event_sourcing.NewTypedEventHandler(model.ReminderEventTopic, logger)
+	if err := event_sourcing.Handle(handler, eventHandler.handleToDoReminderDue); err != nil {
+		return nil, err
+	}
+	return handler, nil
+}
+
+func (handler *reminderEventHandler) handleToDoReminderDue(ctx context.Context, event model.ToDoReminderDueEvent) error {
+	return handler.toDoService.SendToDoReminder(ctx, event)
+}
@@ -1,10 +1,10 @@
 package event
 
 import (
+	"context"
 	"event-bus-demo/domain/model"
 	"event-bus-demo/domain/service"
 	"event-bus-demo/infrastructure/event_sourcing"
-	"fmt"
 	"go.uber.org/zap"
 )
 
@@ -13,29 +13,108 @@ type toDoEventHandler struct {
 	logger      *zap.Logger
 }
 
-func NewToDoEventHandler(toDoService service.ToDoWriteService, logger *zap.Logger) event_sourcing.EventHandler {
+func NewToDoEventHandler(toDoService service.ToDoWriteService, logger *zap.Logger) (event_sourcing.EventHandler, error) {
 	eventHandler := &toDoEventHandler{
 		toDoService: toDoService,
 		logger:      logger,
 	}
-	return eventHandler
-}
-
-func (handler *toDoEventHandler) Handle(event event_sourcing.Event) event_sourcing.EventResult {
-	var err error
-	switch event.(type) {
-	case model.CreateToDoEvent:
-		err = handler.toDoService.AddToDo(event.(model.CreateToDoEvent))
-	case model.UpdateToDoEvent:
-		err = handler.toDoService.UpdateToDo(event.(model.UpdateToDoEvent))
-	case model.DeleteToDoEvent:
-		err = handler.toDoService.DeleteToDo(event.(model.DeleteToDoEvent))
-	case model.AddCategoriesFromToDoEvent:
-		err = handler.toDoService.AddCategoriesIntoToDo(event.(model.AddCategoriesFromToDoEvent))
-	case model.RemoveCategoriesFromToDoEvent:
-		err = handler.toDoService.RemoveCategoriesFromToDo(event.(model.RemoveCategoriesFromToDoEvent))
-	default:
-		err = fmt.Errorf("unknown event")
+	handler := event_sourcing.NewTypedEventHandler(model.ToDoEventTopic, logger)
+	if err := event_sourcing.Handle(handler, eventHandler.handleCreateToDo); err != nil {
+		return nil, err
+	} else if err := event_sourcing.Handle(handler, eventHandler.handleUpdateToDo); err != nil {
+		return nil, err
+	} else if err := event_sourcing.Handle(handler, eventHandler.handleDeleteToDo); err != nil {
+		return nil, err
+	} else if err := event_sourcing.Handle(handler, eventHandler.handleAddCategoriesIntoToDo); err != nil {
+		return nil, err
+	} else if err := event_sourcing.Handle(handler, eventHandler.handleRemoveCategoriesFromToDo); err != nil {
+		return nil, err
+	} else if err := event_sourcing.Handle(handler, eventHandler.handleCompleteToDo); err != nil {
+		return nil, err
+	} else if err := event_sourcing.Handle(handler, eventHandler.handleReopenToDo); err != nil {
+		return nil, err
+	} else if err := event_sourcing.Handle(handler, eventHandler.handleStartToDo); err != nil {
+		return nil, err
+	} else if err := event_sourcing.Handle(handler, eventHandler.handleCancelToDo); err != nil {
+		return nil, err
+	} else if err := event_sourcing.Handle(handler, eventHandler.handleRescheduleToDo); err != nil {
+		return nil, err
+	} else if err := event_sourcing.Handle(handler, eventHandler.handleCreateToDoItem); err != nil {
+		return nil, err
+	} else if err := event_sourcing.Handle(handler, eventHandler.handleUpdateToDoItem); err != nil {
+		return nil, err
+	} else if err := event_sourcing.Handle(handler, eventHandler.handleDeleteToDoItem); err != nil {
+		return nil, err
+	} else if err := event_sourcing.Handle(handler, eventHandler.handleReorderToDoItems); err != nil {
+		return nil, err
+	} else if err := event_sourcing.Handle(handler, eventHandler.handleCreateToDoReminder); err != nil {
+		return nil, err
+	} else if err := event_sourcing.Handle(handler, eventHandler.handleDeleteToDoReminder); err != nil {
+		return nil, err
 	}
-	return HandleError(handler.logger, event, err)
+	return handler, nil
+}
+
+func (handler *toDoEventHandler) handleCreateToDo(ctx context.Context, event model.CreateToDoEvent) error {
+	return handler.toDoService.AddToDo(ctx, event)
+}
+
+func (handler *toDoEventHandler) handleUpdateToDo(ctx context.Context, event model.UpdateToDoEvent) error {
+	return handler.toDoService.UpdateToDo(ctx, event)
+}
+
+func (handler *toDoEventHandler) handleDeleteToDo(ctx context.Context, event model.DeleteToDoEvent) error {
+	return handler.toDoService.DeleteToDo(ctx, event)
+}
+
+func (handler *toDoEventHandler) handleAddCategoriesIntoToDo(ctx context.Context, event model.AddCategoriesFromToDoEvent) error {
+	return handler.toDoService.AddCategoriesIntoToDo(ctx, event)
+}
+
+func (handler *toDoEventHandler) handleRemoveCategoriesFromToDo(ctx context.Context, event model.RemoveCategoriesFromToDoEvent) error {
+	return handler.toDoService.RemoveCategoriesFromToDo(ctx, event)
+}
+
+func (handler *toDoEventHandler) handleCompleteToDo(ctx context.Context, event model.CompleteToDoEvent) error {
+	return handler.toDoService.CompleteToDo(ctx, event)
+}
+
+func (handler *toDoEventHandler) handleReopenToDo(ctx context.Context, event model.ReopenToDoEvent) error {
+	return handler.toDoService.ReopenToDo(ctx, event)
+}
+
+func (handler *toDoEventHandler) handleStartToDo(ctx context.Context, event model.StartToDoEvent) error {
+	return handler.toDoService.StartToDo(ctx, event)
+}
+
+func (handler *toDoEventHandler) handleCancelToDo(ctx context.Context, event model.CancelToDoEvent) error {
+	return handler.toDoService.CancelToDo(ctx, event)
+}
+
+func (handler *toDoEventHandler) handleRescheduleToDo(ctx context.Context, event model.RescheduleToDoEvent) error {
+	return handler.toDoService.RescheduleToDo(ctx, event)
+}
+
+func (handler *toDoEventHandler) handleCreateToDoItem(ctx context.Context, event model.CreateToDoItemEvent) error {
+	return handler.toDoService.AddToDoItem(ctx, event)
+}
+
+func (handler *toDoEventHandler) handleUpdateToDoItem(ctx context.Context, event model.UpdateToDoItemEvent) error {
+	return handler.toDoService.UpdateToDoItem(ctx, event)
+}
+
+func (handler *toDoEventHandler) handleDeleteToDoItem(ctx context.Context, event model.DeleteToDoItemEvent) error {
+	return handler.toDoService.DeleteToDoItem(ctx, event)
+}
+
+func (handler *toDoEventHandler) handleReorderToDoItems(ctx context.Context, event model.ReorderToDoItemsEvent) error {
+	return handler.toDoService.ReorderToDoItems(ctx, event)
+}
+
+func (handler *toDoEventHandler) handleCreateToDoReminder(ctx context.Context, event model.CreateToDoReminderEvent) error {
+	return handler.toDoService.AddToDoReminder(ctx, event)
+}
+
+func (handler *toDoEventHandler) handleDeleteToDoReminder(ctx context.Context, event model.DeleteToDoReminderEvent) error {
+	return handler.toDoService.DeleteToDoReminder(ctx, event)
 }
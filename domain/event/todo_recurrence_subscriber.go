@@ -0,0 +1,97 @@
+package event
+
+import (
+	"context"
+	"event-bus-demo/domain/model"
+	"event-bus-demo/infrastructure/constants"
+	"event-bus-demo/infrastructure/database/service"
+	errorInfrastructure "event-bus-demo/infrastructure/error"
+	"event-bus-demo/infrastructure/event_sourcing"
+	"event-bus-demo/infrastructure/logging"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"strconv"
+	"time"
+)
+
+type toDoRecurrenceSubscriber struct {
+	eventBus            event_sourcing.EventBus
+	toDoDatabaseService service.ToDoDatabaseService
+	logger              *zap.Logger
+}
+
+// NewToDoRecurrenceSubscriber creates the next occurrence of a recurring ToDo once it is completed. The occurrence is
+// due as scheduled by the rule from the due date of the completed ToDo, or from its completion when it had none.
+// Occurrence IDs are derived from their series and number, so completing a reopened ToDo again does not create the
+// next occurrence twice.
+func NewToDoRecurrenceSubscriber(eventBus event_sourcing.EventBus, toDoDatabaseService service.ToDoDatabaseService,
+	logger *zap.Logger) event_sourcing.EventSubscriber {
+	return &toDoRecurrenceSubscriber{
+		eventBus:            eventBus,
+		toDoDatabaseService: toDoDatabaseService,
+		logger:              logger,
+	}
+}
+
+func (subscriber *toDoRecurrenceSubscriber) Notify(result event_sourcing.EventResult) {
+	if !result.Succeeded {
+		return
+	}
+	if event, ok := result.Event.(model.CompleteToDoEvent); ok {
+		subscriber.scheduleNextOccurrence(result, event)
+	}
+}
+
+func (subscriber *toDoRecurrenceSubscriber) scheduleNextOccurrence(result event_sourcing.EventResult,
+	event model.CompleteToDoEvent) {
+	requestID := result.Metadata[constants.RequestIDMetadataKey]
+	ctx := logging.WithRequestID(context.Background(), requestID)
+	logger := subscriber.logger.With(zap.Stringer("id", event.ID), zap.String(logging.RequestIDField, requestID))
+	toDo, err := subscriber.toDoDatabaseService.GetToDo(ctx, event.ID)
+	if err != nil {
+		logger.Error("error while reading completed toDo", zap.Error(err))
+		return
+	} else if toDo.Recurrence == nil {
+		return
+	}
+	anchor := event.CompletedAt
+	if toDo.DueAt != nil {
+		anchor = *toDo.DueAt
+	} else if toDo.CompletedAt != nil {
+		anchor = *toDo.CompletedAt
+	}
+	next, ok := toDo.Recurrence.Next(anchor, toDo.Occurrence)
+	if !ok {
+		logger.Debug("toDo series is over")
+		return
+	}
+	seriesID := toDo.ID
+	if toDo.SeriesID != nil {
+		seriesID = *toDo.SeriesID
+	}
+	nextID := uuid.NewSHA1(seriesID, []byte(strconv.Itoa(toDo.Occurrence+1)))
+	if _, err := subscriber.toDoDatabaseService.GetToDo(ctx, nextID); err == nil {
+		logger.Debug("next toDo occurrence already exists", zap.Stringer("next", nextID))
+		return
+	} else if err.GetCode() != errorInfrastructure.ItemNotFound {
+		logger.Error("error while reading next toDo occurrence", zap.Stringer("next", nextID), zap.Error(err))
+		return
+	}
+	categories := make([]uuid.UUID, 0, len(toDo.Categories))
+	for _, category := range toDo.Categories {
+		categories = append(categories, category.ID)
+	}
+	logger.Debug("scheduling next toDo occurrence", zap.Stringer("next", nextID), zap.Time("dueAt", next))
+	subscriber.eventBus.Publish(ctx, model.CreateToDoEvent{
+		ID:          nextID,
+		Title:       toDo.Title,
+		Description: toDo.Description,
+		CreatedAt:   time.Now(),
+		Priority:    toDo.Priority,
+		DueAt:       &next,
+		Categories:  categories,
+		Recurrence:  toDo.Recurrence,
+		SeriesID:    &seriesID,
+		Occurrence:  toDo.Occurrence + 1,
+	})
+}
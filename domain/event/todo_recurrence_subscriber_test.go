@@ -0,0 +1,155 @@
+package event
+
+import (
+	"context"
+	"errors"
+	"event-bus-demo/domain/model"
+	"event-bus-demo/infrastructure/database/service"
+	errorInfrastructure "event-bus-demo/infrastructure/error"
+	"event-bus-demo/infrastructure/event_sourcing"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeToDoStore holds the ToDos read by the subscriber and creates those of the CreateToDoEvent published to it, as
+// the ToDo handler would. Only the methods used by the subscriber are implemented.
+type fakeToDoStore struct {
+	service.ToDoDatabaseService
+	event_sourcing.EventBus
+	mutex     sync.Mutex
+	toDos     map[uuid.UUID]model.ToDo
+	published []model.CreateToDoEvent
+}
+
+func newFakeToDoStore(toDos ...model.ToDo) *fakeToDoStore {
+	store := &fakeToDoStore{toDos: make(map[uuid.UUID]model.ToDo)}
+	for _, toDo := range toDos {
+		store.toDos[toDo.ID] = toDo
+	}
+	return store
+}
+
+func (store *fakeToDoStore) GetToDo(_ context.Context, ID uuid.UUID) (model.ToDo,
+	errorInfrastructure.InfrastructureError) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	toDo, ok := store.toDos[ID]
+	if !ok {
+		return model.ToDo{}, errorInfrastructure.NewItemNotFoundError("toDo not found")
+	}
+	return toDo, nil
+}
+
+func (store *fakeToDoStore) Publish(_ context.Context, event event_sourcing.Event) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	create, ok := event.(model.CreateToDoEvent)
+	if !ok {
+		return
+	}
+	store.published = append(store.published, create)
+	store.toDos[create.ID] = model.ToDo{
+		ID:         create.ID,
+		Title:      create.Title,
+		Status:     model.OpenToDoStatus,
+		Priority:   create.Priority,
+		DueAt:      create.DueAt,
+		Recurrence: create.Recurrence,
+		SeriesID:   create.SeriesID,
+		Occurrence: create.Occurrence,
+	}
+}
+
+func (store *fakeToDoStore) update(ID uuid.UUID, change func(toDo *model.ToDo)) {
+	store.mutex.Lock()
+	toDo := store.toDos[ID]
+	change(&toDo)
+	store.toDos[ID] = toDo
+	store.mutex.Unlock()
+}
+
+func (store *fakeToDoStore) created() []model.CreateToDoEvent {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	return append([]model.CreateToDoEvent(nil), store.published...)
+}
+
+func newRecurringToDo(rule string, dueAt time.Time) model.ToDo {
+	recurrence, err := model.ParseRecurrenceRule(rule)
+	if err != nil {
+		panic(err)
+	}
+	return model.ToDo{ID: uuid.New(), Title: "Water the plants", Status: model.OpenToDoStatus,
+		Priority: model.MediumToDoPriority, DueAt: &dueAt, Recurrence: &recurrence}
+}
+
+func newTestRecurrenceSubscriber(store *fakeToDoStore) event_sourcing.EventSubscriber {
+	return NewToDoRecurrenceSubscriber(store, store, zap.NewNop())
+}
+
+// complete marks the ToDo as done and notifies the subscriber as the event bus does once it is handled.
+func complete(store *fakeToDoStore, subscriber event_sourcing.EventSubscriber, ID uuid.UUID) {
+	completedAt := time.Date(2026, time.October, 20, 12, 0, 0, 0, time.UTC)
+	store.update(ID, func(toDo *model.ToDo) {
+		toDo.Status, toDo.CompletedAt = model.DoneToDoStatus, &completedAt
+	})
+	subscriber.Notify(event_sourcing.EventResult{Succeeded: true,
+		Event: model.CompleteToDoEvent{ID: ID, CompletedAt: completedAt}})
+}
+
+func TestToDoRecurrenceSubscriberCreatesNextOccurrenceOnce(t *testing.T) {
+	dueAt := time.Date(2026, time.October, 20, 9, 0, 0, 0, time.UTC)
+	toDo := newRecurringToDo("FREQ=WEEKLY", dueAt)
+	store := newFakeToDoStore(toDo)
+	subscriber := newTestRecurrenceSubscriber(store)
+
+	complete(store, subscriber, toDo.ID)
+	store.update(toDo.ID, func(toDo *model.ToDo) {
+		toDo.Status, toDo.CompletedAt = model.OpenToDoStatus, nil
+	})
+	subscriber.Notify(event_sourcing.EventResult{Succeeded: true, Event: model.ReopenToDoEvent{ID: toDo.ID}})
+	complete(store, subscriber, toDo.ID)
+
+	created := store.created()
+	if len(created) != 1 {
+		t.Fatalf("expected a single next occurrence, got %d", len(created))
+	}
+	next := created[0]
+	if next.ID != uuid.NewSHA1(toDo.ID, []byte("1")) || next.SeriesID == nil || *next.SeriesID != toDo.ID ||
+		next.Occurrence != 1 {
+		t.Errorf("expected the first occurrence of the series %s, got %+v", toDo.ID, next)
+	}
+	if expected := dueAt.AddDate(0, 0, 7); next.DueAt == nil || !next.DueAt.Equal(expected) {
+		t.Errorf("expected the next occurrence to be due at %s, got %v", expected, next.DueAt)
+	}
+
+	complete(store, subscriber, next.ID)
+	created = store.created()
+	if len(created) != 2 || created[1].Occurrence != 2 || *created[1].SeriesID != toDo.ID ||
+		created[1].ID != uuid.NewSHA1(toDo.ID, []byte("2")) {
+		t.Errorf("expected completing the occurrence to continue the series, got %+v", created)
+	}
+}
+
+func TestToDoRecurrenceSubscriberIgnoresOtherResults(t *testing.T) {
+	dueAt := time.Date(2026, time.October, 20, 9, 0, 0, 0, time.UTC)
+	recurring := newRecurringToDo("FREQ=DAILY;COUNT=2", dueAt)
+	lastOccurrence := newRecurringToDo("FREQ=DAILY;COUNT=2", dueAt)
+	lastOccurrence.Occurrence = 1
+	single := model.ToDo{ID: uuid.New(), Title: "Buy milk", Status: model.DoneToDoStatus}
+	store := newFakeToDoStore(recurring, lastOccurrence, single)
+	subscriber := newTestRecurrenceSubscriber(store)
+
+	subscriber.Notify(event_sourcing.EventResult{Succeeded: false, Error: errors.New("version mismatch"),
+		Event: model.CompleteToDoEvent{ID: recurring.ID, CompletedAt: dueAt}})
+	complete(store, subscriber, lastOccurrence.ID)
+	complete(store, subscriber, single.ID)
+	complete(store, subscriber, uuid.New())
+
+	if created := store.created(); len(created) != 0 {
+		t.Errorf("expected no occurrence to be created, got %+v", created)
+	}
+}
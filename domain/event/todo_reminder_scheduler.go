@@ -0,0 +1,161 @@
+package event
+
+import (
+	"context"
+	"event-bus-demo/domain/model"
+	"event-bus-demo/infrastructure/database/service"
+	"event-bus-demo/infrastructure/event_sourcing"
+	"fmt"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"sync"
+	"time"
+)
+
+// fullBatchPollDelay is the delay before polling again when a whole batch of reminders was due, so the reminders left
+// behind are not kept waiting for the poll interval.
+const fullBatchPollDelay = time.Second
+
+// ToDoReminderScheduler publishes a ToDoReminderDueEvent once a reminder is due. It subscribes to the ToDo topic, to
+// look again for due reminders as soon as reminders or due dates change, and to the reminder topic, to know which
+// published reminders were handled.
+type ToDoReminderScheduler interface {
+	event_sourcing.EventSubscriber
+	Run()
+	Stop()
+}
+
+type toDoReminderScheduler struct {
+	eventBus            event_sourcing.EventBus
+	toDoDatabaseService service.ToDoDatabaseService
+	pollInterval        time.Duration
+	batchSize           int
+	logger              *zap.Logger
+	wakeUpChannel       chan struct{}
+	quitSignalChannel   chan struct{}
+	stopOnce            sync.Once
+	mutex               sync.Mutex
+	// pending holds when the reminders not handled yet were published. They are published again after a poll interval
+	// in case their event was dropped, the handler making sure they are sent once.
+	pending map[string]time.Time
+}
+
+// NewToDoReminderScheduler builds a scheduler which reads the reminders due within the poll interval, batchSize at a
+// time, and waits until the earliest one is due. Sent reminders are recorded in the database when their event is
+// handled, so reminders due while the application was down are sent once it is back, and none is sent twice.
+func NewToDoReminderScheduler(eventBus event_sourcing.EventBus, toDoDatabaseService service.ToDoDatabaseService,
+	pollInterval time.Duration, batchSize int, logger *zap.Logger) ToDoReminderScheduler {
+	return &toDoReminderScheduler{
+		eventBus:            eventBus,
+		toDoDatabaseService: toDoDatabaseService,
+		pollInterval:        pollInterval,
+		batchSize:           batchSize,
+		logger:              logger,
+		wakeUpChannel:       make(chan struct{}, 1),
+		quitSignalChannel:   make(chan struct{}),
+		pending:             make(map[string]time.Time),
+	}
+}
+
+func (scheduler *toDoReminderScheduler) Run() {
+	scheduler.logger.Info("starting toDo reminder scheduler", zap.Duration("pollInterval", scheduler.pollInterval))
+	go scheduler.schedule()
+}
+
+func (scheduler *toDoReminderScheduler) Stop() {
+	scheduler.stopOnce.Do(func() {
+		close(scheduler.quitSignalChannel)
+	})
+}
+
+func (scheduler *toDoReminderScheduler) Notify(result event_sourcing.EventResult) {
+	switch event := result.Event.(type) {
+	case model.ToDoReminderDueEvent:
+		scheduler.mutex.Lock()
+		delete(scheduler.pending, newPendingReminderKey(event.ID, event.DueAt))
+		scheduler.mutex.Unlock()
+	case model.CreateToDoReminderEvent, model.RescheduleToDoEvent, model.ReopenToDoEvent:
+		if result.Succeeded {
+			scheduler.wakeUp()
+		}
+	}
+}
+
+func (scheduler *toDoReminderScheduler) schedule() {
+	for {
+		timer := time.NewTimer(scheduler.publishDueReminders())
+		select {
+		case <-scheduler.wakeUpChannel:
+		case <-timer.C:
+		case <-scheduler.quitSignalChannel:
+			timer.Stop()
+			scheduler.logger.Info("toDo reminder scheduler signaled to stop")
+			return
+		}
+		timer.Stop()
+	}
+}
+
+// wakeUp tells the scheduler that a reminder may be due sooner than it expects.
+func (scheduler *toDoReminderScheduler) wakeUp() {
+	select {
+	case scheduler.wakeUpChannel <- struct{}{}:
+	default:
+	}
+}
+
+// publishDueReminders publishes the due reminders and returns how long to wait before looking for reminders again.
+func (scheduler *toDoReminderScheduler) publishDueReminders() time.Duration {
+	now := time.Now()
+	reminders, err := scheduler.toDoDatabaseService.GetUpcomingToDoReminders(context.Background(),
+		now.Add(scheduler.pollInterval), scheduler.batchSize)
+	if err != nil {
+		scheduler.logger.Error("error while reading upcoming toDo reminders", zap.Error(err))
+		return scheduler.pollInterval
+	}
+	published := 0
+	for _, reminder := range reminders {
+		// Reminders come sorted by time, the first one not due yet tells when to look again
+		if reminder.RemindAt.After(now) {
+			return reminder.RemindAt.Sub(now)
+		} else if !scheduler.setPending(reminder, now) {
+			continue
+		}
+		scheduler.eventBus.Publish(context.Background(), model.ToDoReminderDueEvent{
+			ID:       reminder.ID,
+			ToDoID:   reminder.ToDoID,
+			Title:    reminder.Title,
+			DueAt:    reminder.DueAt,
+			RemindAt: reminder.RemindAt,
+		})
+		published++
+	}
+	// The reminders left behind a full batch are read shortly, unless the whole batch was pending and would be read as is
+	if len(reminders) == scheduler.batchSize && published > 0 {
+		return fullBatchPollDelay
+	}
+	return scheduler.pollInterval
+}
+
+// setPending records the reminder as published, returning false when it was published less than a poll interval ago.
+// The reminders published before are forgotten, they are published again anyway if their event was not handled, so
+// that the reminders whose result never comes, or which were rescheduled meanwhile, are not kept forever.
+func (scheduler *toDoReminderScheduler) setPending(reminder model.UpcomingToDoReminder, now time.Time) bool {
+	scheduler.mutex.Lock()
+	defer scheduler.mutex.Unlock()
+	for key, publishedAt := range scheduler.pending {
+		if now.Sub(publishedAt) >= scheduler.pollInterval {
+			delete(scheduler.pending, key)
+		}
+	}
+	key := newPendingReminderKey(reminder.ID, reminder.DueAt)
+	if _, ok := scheduler.pending[key]; ok {
+		return false
+	}
+	scheduler.pending[key] = now
+	return true
+}
+
+func newPendingReminderKey(ID uuid.UUID, dueAt time.Time) string {
+	return fmt.Sprintf("%s-%d", ID, dueAt.Unix())
+}
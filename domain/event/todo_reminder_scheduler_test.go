@@ -0,0 +1,237 @@
+package event
+
+import (
+	"context"
+	domainError "event-bus-demo/domain/error"
+	"event-bus-demo/domain/model"
+	domainService "event-bus-demo/domain/service"
+	"event-bus-demo/infrastructure/database/service"
+	errorInfrastructure "event-bus-demo/infrastructure/error"
+	"event-bus-demo/infrastructure/event_sourcing"
+	"event-bus-demo/infrastructure/notification"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeReminderStore holds a single reminder, and when it was sent, as the TODO_REMINDERS table does.
+type fakeReminderStore struct {
+	service.ToDoDatabaseService
+	mutex     sync.Mutex
+	reminder  model.UpcomingToDoReminder
+	sentDueAt *time.Time
+}
+
+func (store *fakeReminderStore) GetUpcomingToDoReminders(_ context.Context, remindBefore time.Time,
+	_ int) ([]model.UpcomingToDoReminder, errorInfrastructure.InfrastructureError) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	if (store.sentDueAt != nil && store.sentDueAt.Equal(store.reminder.DueAt)) ||
+		!store.reminder.RemindAt.Before(remindBefore) {
+		return nil, nil
+	}
+	return []model.UpcomingToDoReminder{store.reminder}, nil
+}
+
+func (store *fakeReminderStore) MarkToDoReminderSent(_ context.Context,
+	event model.ToDoReminderDueEvent) errorInfrastructure.InfrastructureError {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	if (store.sentDueAt != nil && store.sentDueAt.Equal(event.DueAt)) || !store.reminder.DueAt.Equal(event.DueAt) {
+		return errorInfrastructure.NewConflictError("reminder was already sent or is not due anymore")
+	}
+	dueAt := event.DueAt
+	store.sentDueAt = &dueAt
+	return nil
+}
+
+func (store *fakeReminderStore) reschedule(dueAt time.Time) {
+	store.mutex.Lock()
+	store.reminder.DueAt, store.reminder.RemindAt = dueAt, dueAt.Add(-time.Hour)
+	store.mutex.Unlock()
+}
+
+type countingNotifier struct {
+	mutex     sync.Mutex
+	reminders []notification.Reminder
+}
+
+func (notifier *countingNotifier) GetName() string {
+	return "counting"
+}
+
+func (notifier *countingNotifier) Send(_ context.Context, reminder notification.Reminder) error {
+	notifier.mutex.Lock()
+	notifier.reminders = append(notifier.reminders, reminder)
+	notifier.mutex.Unlock()
+	return nil
+}
+
+func (notifier *countingNotifier) sent() []notification.Reminder {
+	notifier.mutex.Lock()
+	defer notifier.mutex.Unlock()
+	return append([]notification.Reminder(nil), notifier.reminders...)
+}
+
+// reminderBus handles the published reminders right away, as the event bus would with the reminder handler, and
+// notifies its subscribers of the results.
+type reminderBus struct {
+	event_sourcing.EventBus
+	mutex       sync.Mutex
+	toDoService domainService.ToDoWriteService
+	subscribers []event_sourcing.EventSubscriber
+	published   int
+}
+
+func newReminderBus(store *fakeReminderStore, notifier notification.Notifier) *reminderBus {
+	return &reminderBus{
+		toDoService: domainService.NewToDoWriteService(store, domainError.NewDomainAdvice(), zap.NewNop()),
+		subscribers: []event_sourcing.EventSubscriber{NewToDoReminderSubscriber(notifier, zap.NewNop())},
+	}
+}
+
+func (bus *reminderBus) Publish(ctx context.Context, event event_sourcing.Event) {
+	bus.mutex.Lock()
+	bus.published++
+	subscribers := append([]event_sourcing.EventSubscriber(nil), bus.subscribers...)
+	bus.mutex.Unlock()
+	result := event_sourcing.EventResult{Succeeded: true, Event: event}
+	if err := bus.toDoService.SendToDoReminder(ctx, event.(model.ToDoReminderDueEvent)); err != nil {
+		result.Succeeded, result.Error = false, err
+	}
+	for _, subscriber := range subscribers {
+		subscriber.Notify(result)
+	}
+}
+
+func (bus *reminderBus) RegisterSubscriber(_ string, subscriber event_sourcing.EventSubscriber) {
+	bus.mutex.Lock()
+	bus.subscribers = append(bus.subscribers, subscriber)
+	bus.mutex.Unlock()
+}
+
+func (bus *reminderBus) publishedEvents() int {
+	bus.mutex.Lock()
+	defer bus.mutex.Unlock()
+	return bus.published
+}
+
+func newTestReminderScheduler(bus *reminderBus, store *fakeReminderStore) *toDoReminderScheduler {
+	scheduler := NewToDoReminderScheduler(bus, store, time.Minute, 10, zap.NewNop())
+	bus.RegisterSubscriber(model.ReminderEventTopic, scheduler)
+	return scheduler.(*toDoReminderScheduler)
+}
+
+func TestToDoReminderSchedulerSendsReminderOncePerDueDateAcrossRestarts(t *testing.T) {
+	dueAt := time.Now().Add(30 * time.Minute).Truncate(time.Second)
+	store := &fakeReminderStore{reminder: model.UpcomingToDoReminder{ID: uuid.New(), ToDoID: uuid.New(),
+		Title: "Water the plants", DueAt: dueAt, RemindAt: dueAt.Add(-time.Hour)}}
+	notifier := &countingNotifier{}
+	bus := newReminderBus(store, notifier)
+
+	newTestReminderScheduler(bus, store).publishDueReminders()
+	if bus.publishedEvents() != 1 || len(notifier.sent()) != 1 || store.sentDueAt == nil {
+		t.Fatalf("expected the due reminder to be published, sent and recorded, got %d events and %d sent",
+			bus.publishedEvents(), len(notifier.sent()))
+	}
+
+	// A restarted scheduler has no pending reminder, the database telling which were sent
+	restarted := newTestReminderScheduler(bus, store)
+	if len(restarted.pending) != 0 {
+		t.Fatalf("expected a new scheduler to have no pending reminder, got %v", restarted.pending)
+	}
+	restarted.publishDueReminders()
+	if bus.publishedEvents() != 1 {
+		t.Errorf("expected the sent reminder not to be published again, got %d events", bus.publishedEvents())
+	}
+
+	// An event published again, as done for pending reminders, is refused by the handler and not delivered
+	reminder := store.reminder
+	bus.Publish(context.Background(), model.ToDoReminderDueEvent{ID: reminder.ID, ToDoID: reminder.ToDoID,
+		Title: reminder.Title, DueAt: reminder.DueAt, RemindAt: reminder.RemindAt})
+	if sent := notifier.sent(); len(sent) != 1 {
+		t.Errorf("expected the reminder to be delivered once for its due date, got %d deliveries", len(sent))
+	}
+
+	// Moving the due date by a few minutes keeps the reminder due
+	store.reschedule(dueAt.Add(10 * time.Minute))
+	restarted.publishDueReminders()
+	if sent := notifier.sent(); len(sent) != 2 || !sent[1].DueAt.Equal(store.reminder.DueAt) {
+		t.Errorf("expected the rescheduled reminder to be delivered for its new due date, got %+v", sent)
+	}
+}
+
+func TestToDoReminderSchedulerWaitsForPendingReminders(t *testing.T) {
+	dueAt := time.Now().Add(30 * time.Minute).Truncate(time.Second)
+	store := &fakeReminderStore{reminder: model.UpcomingToDoReminder{ID: uuid.New(), ToDoID: uuid.New(),
+		Title: "Water the plants", DueAt: dueAt, RemindAt: dueAt.Add(-time.Hour)}}
+	// The handler of the first event did not run yet, so the reminder is still listed as upcoming
+	dropping := &droppingBus{}
+	scheduler := NewToDoReminderScheduler(dropping, store, time.Minute, 10, zap.NewNop()).(*toDoReminderScheduler)
+	scheduler.publishDueReminders()
+	scheduler.publishDueReminders()
+	if dropping.publishedEvents() != 1 {
+		t.Errorf("expected a pending reminder not to be published again within the poll interval, got %d events",
+			dropping.publishedEvents())
+	}
+}
+
+func TestToDoReminderSchedulerForgetsRemindersPublishedBeforeLastPoll(t *testing.T) {
+	dueAt := time.Now().Add(30 * time.Minute).Truncate(time.Second)
+	store := &fakeReminderStore{reminder: model.UpcomingToDoReminder{ID: uuid.New(), ToDoID: uuid.New(),
+		Title: "Water the plants", DueAt: dueAt, RemindAt: dueAt.Add(-time.Hour)}}
+	dropping := &droppingBus{}
+	scheduler := NewToDoReminderScheduler(dropping, store, time.Minute, 10, zap.NewNop()).(*toDoReminderScheduler)
+	// A reminder rescheduled after being published is never handled for its former due date
+	stale := newPendingReminderKey(store.reminder.ID, dueAt.Add(-time.Hour))
+	scheduler.pending[stale] = time.Now().Add(-2 * time.Minute)
+	scheduler.publishDueReminders()
+	key := newPendingReminderKey(store.reminder.ID, dueAt)
+	if _, ok := scheduler.pending[key]; !ok || len(scheduler.pending) != 1 {
+		t.Errorf("expected only the reminder published by the last poll to be pending, got %v", scheduler.pending)
+	}
+	// The event of the reminder was dropped, it is published again once a poll interval went by
+	scheduler.pending[key] = time.Now().Add(-time.Minute)
+	scheduler.publishDueReminders()
+	if dropping.publishedEvents() != 2 {
+		t.Errorf("expected a pending reminder to be published again after the poll interval, got %d events",
+			dropping.publishedEvents())
+	}
+}
+
+func TestToDoReminderSchedulerPollsSoonerOnlyAfterPublishingFullBatch(t *testing.T) {
+	dueAt := time.Now().Add(30 * time.Minute).Truncate(time.Second)
+	store := &fakeReminderStore{reminder: model.UpcomingToDoReminder{ID: uuid.New(), ToDoID: uuid.New(),
+		Title: "Water the plants", DueAt: dueAt, RemindAt: dueAt.Add(-time.Hour)}}
+	dropping := &droppingBus{}
+	scheduler := NewToDoReminderScheduler(dropping, store, time.Minute, 1, zap.NewNop()).(*toDoReminderScheduler)
+	if delay := scheduler.publishDueReminders(); delay != fullBatchPollDelay {
+		t.Errorf("expected a full batch of published reminders to be followed by a poll after %s, got %s",
+			fullBatchPollDelay, delay)
+	}
+	if delay := scheduler.publishDueReminders(); delay != time.Minute {
+		t.Errorf("expected a full batch of pending reminders to be followed by a poll after the poll interval, got %s",
+			delay)
+	}
+}
+
+// droppingBus records the published events without handling them.
+type droppingBus struct {
+	event_sourcing.EventBus
+	mutex     sync.Mutex
+	published int
+}
+
+func (bus *droppingBus) Publish(context.Context, event_sourcing.Event) {
+	bus.mutex.Lock()
+	bus.published++
+	bus.mutex.Unlock()
+}
+
+func (bus *droppingBus) publishedEvents() int {
+	bus.mutex.Lock()
+	defer bus.mutex.Unlock()
+	return bus.published
+}
@@ -0,0 +1,47 @@
+package event
+
+import (
+	"context"
+	"event-bus-demo/domain/model"
+	"event-bus-demo/infrastructure/constants"
+	"event-bus-demo/infrastructure/event_sourcing"
+	"event-bus-demo/infrastructure/logging"
+	"event-bus-demo/infrastructure/notification"
+	"go.uber.org/zap"
+)
+
+type toDoReminderSubscriber struct {
+	notifier notification.Notifier
+	logger   *zap.Logger
+}
+
+// NewToDoReminderSubscriber delivers the due reminders through the notifier. Only reminders whose event succeeded are
+// delivered, the event failing when the reminder was already sent for its due date. A failed delivery is logged and
+// not retried, so a reminder is delivered at most once by every notifier.
+func NewToDoReminderSubscriber(notifier notification.Notifier, logger *zap.Logger) event_sourcing.EventSubscriber {
+	return &toDoReminderSubscriber{
+		notifier: notifier,
+		logger:   logger,
+	}
+}
+
+func (subscriber *toDoReminderSubscriber) Notify(result event_sourcing.EventResult) {
+	event, ok := result.Event.(model.ToDoReminderDueEvent)
+	if !ok || !result.Succeeded {
+		return
+	}
+	requestID := result.Metadata[constants.RequestIDMetadataKey]
+	ctx := logging.WithRequestID(context.Background(), requestID)
+	err := subscriber.notifier.Send(ctx, notification.Reminder{
+		ID:       event.ID,
+		ToDoID:   event.ToDoID,
+		Title:    event.Title,
+		DueAt:    event.DueAt,
+		RemindAt: event.RemindAt,
+	})
+	if err != nil {
+		subscriber.logger.Error("error while sending toDo reminder", zap.String("notifier", subscriber.notifier.GetName()),
+			zap.Stringer("id", event.ToDoID), zap.Stringer("reminder", event.ID), zap.Error(err),
+			zap.String(logging.RequestIDField, requestID))
+	}
+}
@@ -0,0 +1,88 @@
+package event
+
+import (
+	"context"
+	"event-bus-demo/domain/model"
+	"event-bus-demo/infrastructure/constants"
+	"event-bus-demo/infrastructure/database/service"
+	"event-bus-demo/infrastructure/event_sourcing"
+	"event-bus-demo/infrastructure/logging"
+	"event-bus-demo/infrastructure/search"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type toDoSearchSubscriber struct {
+	toDoDatabaseService service.ToDoDatabaseService
+	toDoSearchIndex     search.ToDoSearchIndex
+	logger              *zap.Logger
+}
+
+// NewToDoSearchSubscriber keeps a search index in sync with the ToDo and category events which were handled
+// successfully. Changed ToDos are read back from the database, so the index holds them as stored, categories included.
+func NewToDoSearchSubscriber(toDoDatabaseService service.ToDoDatabaseService, toDoSearchIndex search.ToDoSearchIndex,
+	logger *zap.Logger) event_sourcing.EventSubscriber {
+	return &toDoSearchSubscriber{
+		toDoDatabaseService: toDoDatabaseService,
+		toDoSearchIndex:     toDoSearchIndex,
+		logger:              logger,
+	}
+}
+
+func (subscriber *toDoSearchSubscriber) Notify(result event_sourcing.EventResult) {
+	if !result.Succeeded {
+		return
+	}
+	switch event := result.Event.(type) {
+	case model.CreateToDoEvent:
+		subscriber.reindex(result, event.ID)
+	case model.UpdateToDoEvent:
+		subscriber.reindex(result, event.ID)
+	case model.AddCategoriesFromToDoEvent:
+		subscriber.reindex(result, event.ToDoID)
+	case model.RemoveCategoriesFromToDoEvent:
+		subscriber.reindex(result, event.ToDoID)
+	case model.CompleteToDoEvent:
+		subscriber.reindex(result, event.ID)
+	case model.ReopenToDoEvent:
+		subscriber.reindex(result, event.ID)
+	case model.StartToDoEvent:
+		subscriber.reindex(result, event.ID)
+	case model.CancelToDoEvent:
+		subscriber.reindex(result, event.ID)
+	case model.RescheduleToDoEvent:
+		subscriber.reindex(result, event.ID)
+	case model.CreateToDoItemEvent:
+		subscriber.reindex(result, event.ToDoID)
+	case model.UpdateToDoItemEvent:
+		subscriber.reindex(result, event.ToDoID)
+	case model.DeleteToDoItemEvent:
+		subscriber.reindex(result, event.ToDoID)
+	case model.ReorderToDoItemsEvent:
+		subscriber.reindex(result, event.ToDoID)
+	case model.DeleteToDoEvent:
+		subscriber.toDoSearchIndex.Remove(event.ID)
+	case model.UpdateCategoryNameEvent:
+		subscriber.reindexCategory(result, event.ID)
+	case model.DeleteCategoryEvent:
+		subscriber.reindexCategory(result, event.ID)
+	}
+}
+
+// reindexCategory indexes again the ToDos of a renamed or deleted category, as they hold its name.
+func (subscriber *toDoSearchSubscriber) reindexCategory(result event_sourcing.EventResult, categoryID uuid.UUID) {
+	for _, ID := range subscriber.toDoSearchIndex.CategoryToDos(categoryID) {
+		subscriber.reindex(result, ID)
+	}
+}
+
+func (subscriber *toDoSearchSubscriber) reindex(result event_sourcing.EventResult, ID uuid.UUID) {
+	toDo, err := subscriber.toDoDatabaseService.GetToDo(context.Background(), ID)
+	if err != nil {
+		subscriber.logger.Error("error while indexing toDo",
+			zap.Stringer("id", ID), zap.String("event", result.Event.GetName()), zap.Error(err),
+			zap.String(logging.RequestIDField, result.Metadata[constants.RequestIDMetadataKey]))
+		return
+	}
+	subscriber.toDoSearchIndex.Index(toDo)
+}
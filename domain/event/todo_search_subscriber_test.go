@@ -0,0 +1,63 @@
+package event
+
+import (
+	"context"
+	"event-bus-demo/domain/model"
+	"event-bus-demo/infrastructure/event_sourcing"
+	"event-bus-demo/infrastructure/search"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"reflect"
+	"testing"
+)
+
+func searchedCategories(t *testing.T, index search.ToDoSearchIndex, query string) []model.Category {
+	t.Helper()
+	hits, err := index.Search(context.Background(), query, 10)
+	if err != nil || len(hits) != 1 {
+		t.Fatalf("expected a single hit, got %v and error %v", hits, err)
+	}
+	return hits[0].ToDo.Categories
+}
+
+func TestToDoSearchSubscriberReindexesToDosOfChangedCategories(t *testing.T) {
+	shopping := model.Category{ID: uuid.New(), Name: "Shopping"}
+	home := model.Category{ID: uuid.New(), Name: "Home"}
+	toDo := model.ToDo{ID: uuid.New(), Title: "Buy milk", Categories: []model.Category{shopping, home}}
+	other := model.ToDo{ID: uuid.New(), Title: "Water the plants", Categories: []model.Category{home}}
+	store := newFakeToDoStore(toDo, other)
+	index := search.NewMemoryToDoSearchIndex(store, zap.NewNop())
+	index.Index(toDo)
+	index.Index(other)
+	subscriber := NewToDoSearchSubscriber(store, index, zap.NewNop())
+
+	// A failed rename changes nothing
+	store.update(toDo.ID, func(toDo *model.ToDo) {
+		toDo.Categories = []model.Category{{ID: shopping.ID, Name: "Groceries"}, home}
+	})
+	subscriber.Notify(event_sourcing.EventResult{Succeeded: false,
+		Event: model.UpdateCategoryNameEvent{ID: shopping.ID, Name: "Groceries"}})
+	if categories := searchedCategories(t, index, "milk"); !reflect.DeepEqual(categories, toDo.Categories) {
+		t.Errorf("expected the categories to be kept after a failed rename, got %+v", categories)
+	}
+
+	subscriber.Notify(event_sourcing.EventResult{Succeeded: true,
+		Event: model.UpdateCategoryNameEvent{ID: shopping.ID, Name: "Groceries"}})
+	if categories := searchedCategories(t, index, "milk"); categories[0].Name != "Groceries" {
+		t.Errorf("expected the category to be renamed, got %+v", categories)
+	}
+
+	store.update(toDo.ID, func(toDo *model.ToDo) {
+		toDo.Categories = []model.Category{home}
+	})
+	subscriber.Notify(event_sourcing.EventResult{Succeeded: true, Event: model.DeleteCategoryEvent{ID: shopping.ID}})
+	if categories := searchedCategories(t, index, "milk"); !reflect.DeepEqual(categories, []model.Category{home}) {
+		t.Errorf("expected the deleted category to be removed, got %+v", categories)
+	}
+	if IDs := index.CategoryToDos(shopping.ID); len(IDs) != 0 {
+		t.Errorf("expected no ToDo left in the deleted category, got %v", IDs)
+	}
+	if IDs := index.CategoryToDos(home.ID); len(IDs) != 2 {
+		t.Errorf("expected the ToDos of other categories to be kept, got %v", IDs)
+	}
+}
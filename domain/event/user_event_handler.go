@@ -1,10 +1,10 @@
 package event
 
 import (
+	"context"
 	"event-bus-demo/domain/model"
 	"event-bus-demo/domain/service"
 	"event-bus-demo/infrastructure/event_sourcing"
-	"fmt"
 	"go.uber.org/zap"
 )
 
@@ -13,25 +13,30 @@ type userEventHandler struct {
 	logger      *zap.Logger
 }
 
-func NewUserEventHandler(userService service.UserWriteService, logger *zap.Logger) event_sourcing.EventHandler {
+func NewUserEventHandler(userService service.UserWriteService, logger *zap.Logger) (event_sourcing.EventHandler, error) {
 	eventHandler := &userEventHandler{
 		userService: userService,
 		logger:      logger,
 	}
-	return eventHandler
+	handler := event_sourcing.NewTypedEventHandler(model.UserEventTopic, logger)
+	if err := event_sourcing.Handle(handler, eventHandler.handleCreateUser); err != nil {
+		return nil, err
+	} else if err := event_sourcing.Handle(handler, eventHandler.handleUpdateUserPassword); err != nil {
+		return nil, err
+	} else if err := event_sourcing.Handle(handler, eventHandler.handleDeleteUser); err != nil {
+		return nil, err
+	}
+	return handler, nil
 }
 
-func (handler *userEventHandler) Handle(event event_sourcing.Event) event_sourcing.EventResult {
-	var err error
-	switch event.(type) {
-	case model.CreateUserEvent:
-		err = handler.userService.AddUser(event.(model.CreateUserEvent))
-	case model.UpdateUserPasswordEvent:
-		err = handler.userService.UpdateUserPassword(event.(model.UpdateUserPasswordEvent))
-	case model.DeleteUserEvent:
-		err = handler.userService.DeleteUser(event.(model.DeleteUserEvent))
-	default:
-		err = fmt.Errorf("unknown event")
-	}
-	return HandleError(handler.logger, event, err)
+func (handler *userEventHandler) handleCreateUser(ctx context.Context, event model.CreateUserEvent) error {
+	return handler.userService.AddUser(ctx, event)
+}
+
+func (handler *userEventHandler) handleUpdateUserPassword(ctx context.Context, event model.UpdateUserPasswordEvent) error {
+	return handler.userService.UpdateUserPassword(ctx, event)
+}
+
+func (handler *userEventHandler) handleDeleteUser(ctx context.Context, event model.DeleteUserEvent) error {
+	return handler.userService.DeleteUser(ctx, event)
 }
@@ -7,8 +7,9 @@ import (
 
 func NewGetCategoryResponseFromDomainModel(category domainModel.Category) dto.GetCategoryResponse {
 	return dto.GetCategoryResponse{
-		ID:   category.ID,
-		Name: category.Name,
+		ID:      category.ID,
+		Name:    category.Name,
+		Version: category.Version,
 	}
 }
 
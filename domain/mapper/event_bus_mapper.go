@@ -0,0 +1,88 @@
+package mapper
+
+import (
+	"event-bus-demo/application/dto"
+	"event-bus-demo/infrastructure/event_sourcing"
+	"event-bus-demo/infrastructure/resilience"
+)
+
+func NewGetEventBusResponse(description event_sourcing.EventBusDescription, stats event_sourcing.EventBusStats, circuitBreakers []resilience.CircuitBreakerSnapshot) dto.GetEventBusResponse {
+	topicStats := make(map[string]event_sourcing.TopicStats)
+	for _, topic := range stats.Topics {
+		topicStats[topic.Name] = topic
+	}
+	topics := make([]dto.EventBusTopicResponse, 0)
+	for _, topic := range description.Topics {
+		topics = append(topics, newEventBusTopicResponse(topic, topicStats[topic.Name]))
+	}
+	failures := make([]dto.EventBusFailureResponse, 0)
+	for _, failure := range stats.RecentFailures {
+		failures = append(failures, dto.EventBusFailureResponse{
+			Topic:     failure.Topic,
+			Event:     failure.Event,
+			Kind:      string(failure.Kind),
+			Error:     failure.Error,
+			RequestID: failure.RequestID,
+			FailedAt:  failure.FailedAt,
+		})
+	}
+	return dto.GetEventBusResponse{
+		Buffer: dto.EventBusBufferResponse{
+			Length:   stats.BufferLength,
+			Capacity: stats.BufferCapacity,
+		},
+		Workers: dto.EventBusWorkersResponse{
+			Busy: stats.BusyWorkers,
+			Max:  stats.MaxWorkers,
+		},
+		Topics:          topics,
+		RecentFailures:  failures,
+		CircuitBreakers: NewCircuitBreakerResponseList(circuitBreakers),
+	}
+}
+
+func NewCircuitBreakerResponseList(circuitBreakers []resilience.CircuitBreakerSnapshot) []dto.CircuitBreakerResponse {
+	responses := make([]dto.CircuitBreakerResponse, 0)
+	for _, breaker := range circuitBreakers {
+		response := dto.CircuitBreakerResponse{
+			Name:                breaker.Name,
+			State:               string(breaker.State),
+			ConsecutiveFailures: breaker.ConsecutiveFailures,
+		}
+		if !breaker.OpenedAt.IsZero() {
+			response.OpenedAt = &breaker.OpenedAt
+		}
+		responses = append(responses, response)
+	}
+	return responses
+}
+
+func newEventBusTopicResponse(topic event_sourcing.TopicDescription, stats event_sourcing.TopicStats) dto.EventBusTopicResponse {
+	handlers := make([]dto.EventBusHandlerResponse, 0)
+	for _, handler := range topic.Handlers {
+		handlers = append(handlers, dto.EventBusHandlerResponse{
+			Name:   handler.Name,
+			Events: handler.Events,
+		})
+	}
+	return dto.EventBusTopicResponse{
+		Name:   topic.Name,
+		Paused: stats.Paused,
+		Buffer: dto.EventBusBufferResponse{
+			Length:   stats.BufferLength,
+			Capacity: stats.BufferCapacity,
+		},
+		Workers: dto.EventBusWorkersResponse{
+			Busy: stats.BusyWorkers,
+			Max:  stats.MaxWorkers,
+		},
+		Weight:            stats.Weight,
+		Lanes:             stats.LaneLengths,
+		PendingEvents:     stats.PendingEvents,
+		OverflowEvents:    stats.OverflowLength,
+		AbandonedHandlers: stats.AbandonedHandlers,
+		Events:            topic.Events,
+		Handlers:          handlers,
+		Subscribers:       topic.Subscribers,
+	}
+}
@@ -0,0 +1,22 @@
+package mapper
+
+import (
+	"event-bus-demo/application/dto"
+	"event-bus-demo/infrastructure/health"
+)
+
+func NewHealthResponse(report health.Report) dto.HealthResponse {
+	checks := make([]dto.HealthCheckResponse, 0)
+	for _, check := range report.Checks {
+		checks = append(checks, dto.HealthCheckResponse{
+			Name:    check.Name,
+			Status:  string(check.Status),
+			Details: check.Details,
+			Error:   check.Error,
+		})
+	}
+	return dto.HealthResponse{
+		Status: string(report.Status),
+		Checks: checks,
+	}
+}
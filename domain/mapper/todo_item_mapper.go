@@ -0,0 +1,28 @@
+package mapper
+
+import (
+	"event-bus-demo/application/dto"
+	domainModel "event-bus-demo/domain/model"
+)
+
+func NewGetToDoItemResponseFromDomainModel(item domainModel.ToDoItem) dto.GetToDoItemResponse {
+	return dto.GetToDoItemResponse{
+		ID:        item.ID,
+		Title:     item.Title,
+		Done:      item.Done,
+		Position:  item.Position,
+		CreatedAt: item.CreatedAt,
+		UpdatedAt: item.UpdatedAt,
+	}
+}
+
+func NewGetToDoItemsResponseFromDomainModelList(items []domainModel.ToDoItem) dto.GetToDoItemsResponse {
+	dtoList := make([]dto.GetToDoItemResponse, 0)
+	for _, item := range items {
+		dtoList = append(dtoList, NewGetToDoItemResponseFromDomainModel(item))
+	}
+	return dto.GetToDoItemsResponse{
+		Items:    dtoList,
+		Progress: domainModel.ToDoProgress(items),
+	}
+}
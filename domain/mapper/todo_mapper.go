@@ -3,25 +3,70 @@ package mapper
 import (
 	"event-bus-demo/application/dto"
 	"event-bus-demo/domain/model"
+	"time"
 )
 
 func NewGetToDoResponseFromDomainModel(model model.ToDo) dto.GetToDoResponse {
+	items := NewGetToDoItemsResponseFromDomainModelList(model.Items)
 	return dto.GetToDoResponse{
 		ID:          model.ID,
 		Title:       model.Title,
 		Description: model.Description,
 		CreatedAt:   model.CreatedAt,
 		UpdatedAt:   model.UpdatedAt,
+		Status:      string(model.Status),
+		Priority:    string(model.Priority),
+		DueAt:       model.DueAt,
+		CompletedAt: model.CompletedAt,
+		Recurrence:  newRecurrenceString(model.Recurrence),
+		SeriesID:    model.SeriesID,
+		Occurrence:  model.Occurrence,
+		Version:     model.Version,
 		Categories:  NewGetCategoriesResponseFromDomainModelList(model.Categories).Categories,
+		Items:       items.Items,
+		Progress:    items.Progress,
 	}
 }
 
-func NewGetAllToDoResponseFromDomainModel(modelList []model.ToDo) dto.GetAllToDoResponse {
+func NewGetAllToDoResponseFromDomainModel(page model.ToDoPage) dto.GetAllToDoResponse {
 	responseList := make([]dto.GetToDoResponse, 0)
-	for _, toDo := range modelList {
+	for _, toDo := range page.ToDos {
 		responseList = append(responseList, NewGetToDoResponseFromDomainModel(toDo))
 	}
 	return dto.GetAllToDoResponse{
-		ToDos: responseList,
+		ToDos:          responseList,
+		HasNext:        page.HasNext,
+		HasPrevious:    page.HasPrevious,
+		NextCursor:     page.NextCursor,
+		PreviousCursor: page.PreviousCursor,
 	}
 }
+
+func NewSearchToDoResponseFromDomainModelList(hits []model.ToDoSearchHit) dto.SearchToDoResponse {
+	results := make([]dto.SearchToDoResult, 0)
+	for _, hit := range hits {
+		results = append(results, dto.SearchToDoResult{
+			ToDo:           NewGetToDoResponseFromDomainModel(hit.ToDo),
+			Rank:           hit.Rank,
+			TitleHighlight: hit.TitleHighlight,
+			Snippet:        hit.Snippet,
+		})
+	}
+	return dto.SearchToDoResponse{
+		Results: results,
+	}
+}
+
+func NewGetToDoOccurrencesResponse(recurrence *model.RecurrenceRule, occurrences []time.Time) dto.GetToDoOccurrencesResponse {
+	return dto.GetToDoOccurrencesResponse{
+		Recurrence:  newRecurrenceString(recurrence),
+		Occurrences: occurrences,
+	}
+}
+
+func newRecurrenceString(rule *model.RecurrenceRule) string {
+	if rule == nil {
+		return ""
+	}
+	return rule.String()
+}
@@ -0,0 +1,29 @@
+package mapper
+
+import (
+	"event-bus-demo/application/dto"
+	domainModel "event-bus-demo/domain/model"
+	"time"
+)
+
+func NewGetToDoReminderResponseFromDomainModel(reminder domainModel.ToDoReminder, dueAt *time.Time) dto.GetToDoReminderResponse {
+	return dto.GetToDoReminderResponse{
+		ID:        reminder.ID,
+		Before:    reminder.BeforeDue.String(),
+		RemindAt:  reminder.RemindAt(dueAt),
+		Sent:      reminder.IsSent(dueAt),
+		SentAt:    reminder.SentAt,
+		CreatedAt: reminder.CreatedAt,
+	}
+}
+
+// NewGetToDoRemindersResponseFromDomainModelList maps the reminders of a ToDo due at the given date.
+func NewGetToDoRemindersResponseFromDomainModelList(reminders []domainModel.ToDoReminder, dueAt *time.Time) dto.GetToDoRemindersResponse {
+	dtoList := make([]dto.GetToDoReminderResponse, 0)
+	for _, reminder := range reminders {
+		dtoList = append(dtoList, NewGetToDoReminderResponseFromDomainModel(reminder, dueAt))
+	}
+	return dto.GetToDoRemindersResponse{
+		Reminders: dtoList,
+	}
+}
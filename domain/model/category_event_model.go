@@ -17,9 +17,12 @@ func (CreateCategoryEvent) GetName() string {
 	return "CreateCategoryEvent"
 }
 
+// UpdateCategoryNameEvent renames a category. When ExpectedVersion is set, the update is rejected if the category was
+// changed since that version was read.
 type UpdateCategoryNameEvent struct {
-	ID   uuid.UUID
-	Name string
+	ID              uuid.UUID
+	Name            string
+	ExpectedVersion *int64
 }
 
 func (UpdateCategoryNameEvent) GetTopic() string {
@@ -31,7 +34,8 @@ func (UpdateCategoryNameEvent) GetName() string {
 }
 
 type DeleteCategoryEvent struct {
-	ID uuid.UUID
+	ID              uuid.UUID
+	ExpectedVersion *int64
 }
 
 func (DeleteCategoryEvent) GetTopic() string {
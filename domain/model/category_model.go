@@ -7,4 +7,22 @@ import (
 type Category struct {
 	ID   uuid.UUID
 	Name string
+	// Version is incremented by every change of the category
+	Version int64
+}
+
+type CategoryDeletionMode string
+
+const (
+	// RestrictCategoryDeletion refuses to delete categories which still have ToDos
+	RestrictCategoryDeletion CategoryDeletionMode = "restrict"
+	// UnlinkCategoryDeletion removes the category from its ToDos before deleting it
+	UnlinkCategoryDeletion CategoryDeletionMode = "cascade-unlink"
+	// ReassignCategoryDeletion moves the ToDos of the category to the default category before deleting it
+	ReassignCategoryDeletion CategoryDeletionMode = "reassign"
+)
+
+type CategoryDeletionPolicy struct {
+	Mode              CategoryDeletionMode
+	DefaultCategoryID uuid.UUID
 }
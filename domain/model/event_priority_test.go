@@ -0,0 +1,30 @@
+package model
+
+import (
+	"event-bus-demo/infrastructure/event_sourcing"
+	"testing"
+)
+
+func TestEventPriorities(t *testing.T) {
+	tests := []struct {
+		event    event_sourcing.Event
+		expected event_sourcing.Priority
+	}{
+		{event: DeleteUserEvent{}, expected: event_sourcing.HighPriority},
+		{event: UpdateUserPasswordEvent{}, expected: event_sourcing.HighPriority},
+		{event: CreateToDoEvent{}, expected: event_sourcing.LowPriority},
+		{event: CreateUserEvent{}, expected: event_sourcing.NormalPriority},
+		{event: UpdateToDoEvent{}, expected: event_sourcing.NormalPriority},
+	}
+	for _, test := range tests {
+		t.Run(test.event.GetName(), func(t *testing.T) {
+			priority := event_sourcing.NormalPriority
+			if prioritizedEvent, ok := test.event.(event_sourcing.PrioritizedEvent); ok {
+				priority = prioritizedEvent.GetPriority()
+			}
+			if priority != test.expected {
+				t.Errorf("expected priority %s, got %s", test.expected, priority)
+			}
+		})
+	}
+}
@@ -0,0 +1,222 @@
+package model
+
+import (
+	"errors"
+	"event-bus-demo/infrastructure/util"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type RecurrenceFrequency string
+
+const (
+	DailyRecurrence   RecurrenceFrequency = "DAILY"
+	WeeklyRecurrence  RecurrenceFrequency = "WEEKLY"
+	MonthlyRecurrence RecurrenceFrequency = "MONTHLY"
+)
+
+const recurrenceUntilLayout = "20060102T150405Z"
+
+var recurrenceWeekdays = map[string]time.Weekday{
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+	"SU": time.Sunday,
+}
+
+// RecurrenceRule is the subset of iCalendar recurrence rules (RFC 5545) supported by ToDos, such as
+// FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH;COUNT=10. Occurrences are computed from the due date of the previous one, keeping
+// its time of day.
+type RecurrenceRule struct {
+	Frequency RecurrenceFrequency
+	Interval  int
+	// ByDay restricts daily and weekly rules to some days of the week, weeks starting on Monday
+	ByDay []time.Weekday
+	// Count limits the number of occurrences of the series, the first one included, zero meaning no limit
+	Count int
+	Until *time.Time
+}
+
+// ParseRecurrenceRule reads a rule written as in the RRULE property, with or without its RRULE: prefix. An UNTIL date
+// without time includes the whole day.
+func ParseRecurrenceRule(value string) (RecurrenceRule, error) {
+	rule := RecurrenceRule{Interval: 1}
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(strings.TrimPrefix(strings.ToUpper(value), "RRULE:"), ";") {
+		name, value, found := strings.Cut(part, "=")
+		if !found || value == "" {
+			return RecurrenceRule{}, fmt.Errorf("invalid recurrence rule part %q", part)
+		} else if seen[name] {
+			return RecurrenceRule{}, fmt.Errorf("recurrence rule part %s is repeated", name)
+		}
+		seen[name] = true
+		var err error
+		switch name {
+		case "FREQ":
+			rule.Frequency = RecurrenceFrequency(value)
+			if rule.Frequency != DailyRecurrence && rule.Frequency != WeeklyRecurrence && rule.Frequency != MonthlyRecurrence {
+				err = fmt.Errorf("unsupported recurrence frequency %s", value)
+			}
+		case "INTERVAL":
+			rule.Interval, err = parsePositiveRecurrenceNumber(name, value)
+		case "COUNT":
+			rule.Count, err = parsePositiveRecurrenceNumber(name, value)
+		case "UNTIL":
+			rule.Until, err = parseRecurrenceUntil(value)
+		case "BYDAY":
+			rule.ByDay, err = parseRecurrenceWeekdays(value)
+		default:
+			err = fmt.Errorf("unsupported recurrence rule part %s", name)
+		}
+		if err != nil {
+			return RecurrenceRule{}, err
+		}
+	}
+	if rule.Frequency == "" {
+		return RecurrenceRule{}, errors.New("recurrence rule requires FREQ")
+	} else if rule.Count > 0 && rule.Until != nil {
+		return RecurrenceRule{}, errors.New("recurrence rule cannot have both COUNT and UNTIL")
+	} else if rule.Frequency == MonthlyRecurrence && len(rule.ByDay) > 0 {
+		return RecurrenceRule{}, errors.New("BYDAY is only supported by daily and weekly recurrence rules")
+	}
+	return rule, nil
+}
+
+func parsePositiveRecurrenceNumber(name string, value string) (int, error) {
+	number, err := strconv.Atoi(value)
+	if err != nil || number < 1 {
+		return 0, fmt.Errorf("%s must be a positive number", name)
+	}
+	return number, nil
+}
+
+func parseRecurrenceUntil(value string) (*time.Time, error) {
+	if until, err := time.Parse(recurrenceUntilLayout, value); err == nil {
+		return &until, nil
+	} else if day, err := time.Parse("20060102", value); err == nil {
+		until := day.Add(24*time.Hour - time.Second)
+		return &until, nil
+	}
+	return nil, fmt.Errorf("UNTIL must be a date such as 20261231 or a UTC time such as 20261231T235959Z")
+}
+
+func parseRecurrenceWeekdays(value string) ([]time.Weekday, error) {
+	weekdays := make([]time.Weekday, 0)
+	for _, name := range strings.Split(value, ",") {
+		weekday, ok := recurrenceWeekdays[name]
+		if !ok {
+			return nil, fmt.Errorf("unsupported BYDAY value %s", name)
+		}
+		weekdays = append(weekdays, weekday)
+	}
+	sort.Slice(weekdays, func(i, j int) bool {
+		return weekdayIndex(weekdays[i]) < weekdayIndex(weekdays[j])
+	})
+	return weekdays, nil
+}
+
+// String writes the rule back in the RRULE format, in a canonical form.
+func (rule RecurrenceRule) String() string {
+	parts := []string{"FREQ=" + string(rule.Frequency)}
+	if rule.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(rule.Interval))
+	}
+	if len(rule.ByDay) > 0 {
+		names := make([]string, 0, len(rule.ByDay))
+		for _, weekday := range rule.ByDay {
+			names = append(names, strings.ToUpper(weekday.String()[:2]))
+		}
+		parts = append(parts, "BYDAY="+strings.Join(names, ","))
+	}
+	if rule.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(rule.Count))
+	}
+	if rule.Until != nil {
+		parts = append(parts, "UNTIL="+rule.Until.UTC().Format(recurrenceUntilLayout))
+	}
+	return strings.Join(parts, ";")
+}
+
+// Next returns the occurrence following current, which is the occurrence of the series with the given index, starting
+// from 0. False is returned once the series is over.
+func (rule RecurrenceRule) Next(current time.Time, occurrence int) (time.Time, bool) {
+	if rule.Count > 0 && occurrence+1 >= rule.Count {
+		return time.Time{}, false
+	}
+	var next time.Time
+	var found bool
+	switch rule.Frequency {
+	case DailyRecurrence:
+		next, found = rule.nextDay(current)
+	case WeeklyRecurrence:
+		next, found = rule.nextWeekDay(current), true
+	case MonthlyRecurrence:
+		next, found = rule.nextMonthDay(current)
+	}
+	if !found || (rule.Until != nil && next.After(*rule.Until)) {
+		return time.Time{}, false
+	}
+	return next, true
+}
+
+// Upcoming returns at most limit occurrences following current, as Next does.
+func (rule RecurrenceRule) Upcoming(current time.Time, occurrence int, limit int) []time.Time {
+	occurrences := make([]time.Time, 0, limit)
+	for len(occurrences) < limit {
+		next, ok := rule.Next(current, occurrence)
+		if !ok {
+			break
+		}
+		occurrences = append(occurrences, next)
+		current, occurrence = next, occurrence+1
+	}
+	return occurrences
+}
+
+func (rule RecurrenceRule) nextDay(current time.Time) (time.Time, bool) {
+	// Weekdays come back after 7 steps at most, so a rule whose interval never lands on its days has no next occurrence
+	for step := 1; step <= 7; step++ {
+		next := current.AddDate(0, 0, step*rule.Interval)
+		if len(rule.ByDay) == 0 || util.Contains(rule.ByDay, next.Weekday()) {
+			return next, true
+		}
+	}
+	return time.Time{}, false
+}
+
+func (rule RecurrenceRule) nextWeekDay(current time.Time) time.Time {
+	if len(rule.ByDay) == 0 {
+		return current.AddDate(0, 0, 7*rule.Interval)
+	}
+	currentIndex := weekdayIndex(current.Weekday())
+	for _, weekday := range rule.ByDay {
+		if index := weekdayIndex(weekday); index > currentIndex {
+			return current.AddDate(0, 0, index-currentIndex)
+		}
+	}
+	weekStart := current.AddDate(0, 0, -currentIndex)
+	return weekStart.AddDate(0, 0, 7*rule.Interval+weekdayIndex(rule.ByDay[0]))
+}
+
+// nextMonthDay keeps the day of the month of current, skipping the months too short to have it.
+func (rule RecurrenceRule) nextMonthDay(current time.Time) (time.Time, bool) {
+	for step := 1; step <= 48; step++ {
+		next := time.Date(current.Year(), current.Month()+time.Month(step*rule.Interval), current.Day(), current.Hour(),
+			current.Minute(), current.Second(), current.Nanosecond(), current.Location())
+		if next.Day() == current.Day() {
+			return next, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// weekdayIndex numbers the days of a week starting on Monday.
+func weekdayIndex(weekday time.Weekday) int {
+	return (int(weekday) + 6) % 7
+}
@@ -0,0 +1,217 @@
+package model
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func at(value string) time.Time {
+	result, err := time.Parse("2006-01-02T15:04", value)
+	if err != nil {
+		panic(err)
+	}
+	return result
+}
+
+func TestRecurrenceRuleNext(t *testing.T) {
+	tests := []struct {
+		name       string
+		rule       string
+		current    string
+		occurrence int
+		expected   string
+	}{
+		{
+			name:     "weekly days within the week",
+			rule:     "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH",
+			current:  "2026-10-19T09:00",
+			expected: "2026-10-22T09:00",
+		},
+		{
+			name:     "weekly days wrapping to the week after the interval",
+			rule:     "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH",
+			current:  "2026-10-22T09:00",
+			expected: "2026-11-02T09:00",
+		},
+		{
+			name:     "weekly days wrapping from a day after all of them",
+			rule:     "FREQ=WEEKLY;INTERVAL=3;BYDAY=TH,MO",
+			current:  "2026-10-25T18:30",
+			expected: "2026-11-09T18:30",
+		},
+		{
+			name:     "weekly without days",
+			rule:     "FREQ=WEEKLY;INTERVAL=2",
+			current:  "2026-10-22T09:00",
+			expected: "2026-11-05T09:00",
+		},
+		{
+			name:     "monthly",
+			rule:     "FREQ=MONTHLY",
+			current:  "2026-01-15T09:00",
+			expected: "2026-02-15T09:00",
+		},
+		{
+			name:     "monthly on the 31st skipping February",
+			rule:     "FREQ=MONTHLY",
+			current:  "2026-01-31T09:00",
+			expected: "2026-03-31T09:00",
+		},
+		{
+			name:     "monthly on the 31st skipping April",
+			rule:     "FREQ=MONTHLY",
+			current:  "2026-03-31T09:00",
+			expected: "2026-05-31T09:00",
+		},
+		{
+			name:     "monthly interval skipping several short months",
+			rule:     "FREQ=MONTHLY;INTERVAL=2",
+			current:  "2026-07-31T09:00",
+			expected: "2027-01-31T09:00",
+		},
+		{
+			name:     "monthly on a leap day",
+			rule:     "FREQ=MONTHLY;INTERVAL=12",
+			current:  "2024-02-29T09:00",
+			expected: "2028-02-29T09:00",
+		},
+		{
+			name:       "count not reached",
+			rule:       "FREQ=DAILY;COUNT=3",
+			current:    "2026-10-20T09:00",
+			occurrence: 1,
+			expected:   "2026-10-21T09:00",
+		},
+		{
+			name:       "count reached",
+			rule:       "FREQ=DAILY;COUNT=3",
+			current:    "2026-10-20T09:00",
+			occurrence: 2,
+		},
+		{
+			name:    "count of a single occurrence",
+			rule:    "FREQ=DAILY;COUNT=1",
+			current: "2026-10-20T09:00",
+		},
+		{
+			name:     "date only until includes its whole day",
+			rule:     "FREQ=DAILY;UNTIL=20261031",
+			current:  "2026-10-30T23:30",
+			expected: "2026-10-31T23:30",
+		},
+		{
+			name:    "date only until excludes the next day",
+			rule:    "FREQ=DAILY;UNTIL=20261031",
+			current: "2026-10-31T00:00",
+		},
+		{
+			name:    "until time",
+			rule:    "FREQ=DAILY;UNTIL=20261031T080000Z",
+			current: "2026-10-30T09:00",
+		},
+		{
+			name:     "daily days",
+			rule:     "FREQ=DAILY;BYDAY=MO,FR",
+			current:  "2026-10-23T09:00",
+			expected: "2026-10-26T09:00",
+		},
+		{
+			name:     "daily interval landing on a day after some steps",
+			rule:     "FREQ=DAILY;INTERVAL=2;BYDAY=MO",
+			current:  "2026-10-20T09:00",
+			expected: "2026-10-26T09:00",
+		},
+		{
+			name:     "daily interval of two weeks",
+			rule:     "FREQ=DAILY;INTERVAL=14;BYDAY=TU,WE",
+			current:  "2026-10-20T09:00",
+			expected: "2026-11-03T09:00",
+		},
+		{
+			name:    "daily interval never landing on a day",
+			rule:    "FREQ=DAILY;INTERVAL=7;BYDAY=MO",
+			current: "2026-10-20T09:00",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rule, err := ParseRecurrenceRule(test.rule)
+			if err != nil {
+				t.Fatalf("unexpected error %s", err.Error())
+			}
+			next, ok := rule.Next(at(test.current), test.occurrence)
+			if test.expected == "" {
+				if ok {
+					t.Errorf("expected the series to be over, got %s", next)
+				}
+			} else if !ok || !next.Equal(at(test.expected)) {
+				t.Errorf("expected %s, got %s (%t)", test.expected, next, ok)
+			}
+		})
+	}
+}
+
+func TestRecurrenceRuleUpcomingStopsAtCount(t *testing.T) {
+	rule, err := ParseRecurrenceRule("FREQ=WEEKLY;COUNT=3")
+	if err != nil {
+		t.Fatalf("unexpected error %s", err.Error())
+	}
+	expected := []time.Time{at("2026-10-27T09:00"), at("2026-11-03T09:00")}
+	if upcoming := rule.Upcoming(at("2026-10-20T09:00"), 0, 5); !reflect.DeepEqual(upcoming, expected) {
+		t.Errorf("expected %v, got %v", expected, upcoming)
+	}
+	if upcoming := rule.Upcoming(at("2026-10-20T09:00"), 0, 1); len(upcoming) != 1 {
+		t.Errorf("expected the limit to apply, got %v", upcoming)
+	}
+}
+
+func TestParseRecurrenceRuleWritesCanonicalRule(t *testing.T) {
+	tests := []struct {
+		rule     string
+		expected string
+	}{
+		{rule: "rrule:freq=weekly;byday=th,mo;interval=1", expected: "FREQ=WEEKLY;BYDAY=MO,TH"},
+		{rule: "FREQ=DAILY;INTERVAL=2;COUNT=10", expected: "FREQ=DAILY;INTERVAL=2;COUNT=10"},
+		{rule: "FREQ=MONTHLY;UNTIL=20261031", expected: "FREQ=MONTHLY;UNTIL=20261031T235959Z"},
+	}
+	for _, test := range tests {
+		t.Run(test.rule, func(t *testing.T) {
+			rule, err := ParseRecurrenceRule(test.rule)
+			if err != nil {
+				t.Fatalf("unexpected error %s", err.Error())
+			} else if rule.String() != test.expected {
+				t.Errorf("expected %s, got %s", test.expected, rule.String())
+			}
+		})
+	}
+}
+
+func TestParseRecurrenceRuleRejectsUnsupportedRules(t *testing.T) {
+	tests := []struct {
+		rule     string
+		expected string
+	}{
+		{rule: "INTERVAL=2", expected: "recurrence rule requires FREQ"},
+		{rule: "FREQ=YEARLY", expected: "unsupported recurrence frequency YEARLY"},
+		{rule: "FREQ=DAILY;INTERVAL=0", expected: "INTERVAL must be a positive number"},
+		{rule: "FREQ=DAILY;FREQ=WEEKLY", expected: "recurrence rule part FREQ is repeated"},
+		{rule: "FREQ=DAILY;COUNT=2;UNTIL=20261031", expected: "cannot have both COUNT and UNTIL"},
+		{rule: "FREQ=MONTHLY;BYDAY=MO", expected: "BYDAY is only supported by daily and weekly recurrence rules"},
+		{rule: "FREQ=WEEKLY;BYDAY=1MO", expected: "unsupported BYDAY value 1MO"},
+		{rule: "FREQ=DAILY;UNTIL=2026-10-31", expected: "UNTIL must be a date"},
+		{rule: "FREQ=DAILY;BYMONTH=1", expected: "unsupported recurrence rule part BYMONTH"},
+		{rule: "FREQ=DAILY;", expected: "invalid recurrence rule part"},
+	}
+	for _, test := range tests {
+		t.Run(test.rule, func(t *testing.T) {
+			rule, err := ParseRecurrenceRule(test.rule)
+			if err == nil {
+				t.Fatalf("expected an error, got %s", rule)
+			} else if !strings.Contains(err.Error(), test.expected) {
+				t.Errorf("expected an error containing %q, got %q", test.expected, err.Error())
+			}
+		})
+	}
+}
@@ -0,0 +1,27 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+const ReminderEventTopic = "REMINDER"
+
+// ToDoReminderDueEvent is published by the reminder scheduler once a reminder is due. Handling it marks the reminder
+// as sent for DueAt, which fails when it was already sent or the ToDo changed since, so subscribers only deliver the
+// reminders whose event succeeded.
+type ToDoReminderDueEvent struct {
+	ID       uuid.UUID
+	ToDoID   uuid.UUID
+	Title    string
+	DueAt    time.Time
+	RemindAt time.Time
+}
+
+func (ToDoReminderDueEvent) GetTopic() string {
+	return ReminderEventTopic
+}
+
+func (ToDoReminderDueEvent) GetName() string {
+	return "ToDoReminderDueEvent"
+}
@@ -1,6 +1,7 @@
 package model
 
 import (
+	"event-bus-demo/infrastructure/event_sourcing"
 	"github.com/google/uuid"
 	"time"
 )
@@ -12,7 +13,12 @@ type CreateToDoEvent struct {
 	Title       string
 	Description string
 	CreatedAt   time.Time
+	Priority    ToDoPriority
+	DueAt       *time.Time
 	Categories  []uuid.UUID
+	Recurrence  *RecurrenceRule
+	SeriesID    *uuid.UUID
+	Occurrence  int
 }
 
 func (CreateToDoEvent) GetTopic() string {
@@ -23,11 +29,23 @@ func (CreateToDoEvent) GetName() string {
 	return "CreateToDoEvent"
 }
 
+// GetPriority keeps the creation of ToDos, which may come in bulk, from delaying the changes of existing ones.
+func (CreateToDoEvent) GetPriority() event_sourcing.Priority {
+	return event_sourcing.LowPriority
+}
+
+// UpdateToDoEvent changes the fields of a ToDo listed in Fields, the other ones are kept. When ExpectedVersion is set,
+// the update is rejected if the ToDo was changed since that version was read. A nil Recurrence listed in Fields stops
+// the ToDo from recurring.
 type UpdateToDoEvent struct {
-	ID          uuid.UUID
-	Title       string
-	Description string
-	UpdatedAt   time.Time
+	ID              uuid.UUID
+	Title           string
+	Description     string
+	Priority        ToDoPriority
+	Recurrence      *RecurrenceRule
+	Fields          []ToDoField
+	UpdatedAt       time.Time
+	ExpectedVersion *int64
 }
 
 func (UpdateToDoEvent) GetTopic() string {
@@ -39,7 +57,8 @@ func (UpdateToDoEvent) GetName() string {
 }
 
 type DeleteToDoEvent struct {
-	ID uuid.UUID
+	ID              uuid.UUID
+	ExpectedVersion *int64
 }
 
 func (DeleteToDoEvent) GetTopic() string {
@@ -50,6 +69,172 @@ func (DeleteToDoEvent) GetName() string {
 	return "DeleteToDoEvent"
 }
 
+// CompleteToDoEvent marks an open or in progress ToDo as done.
+type CompleteToDoEvent struct {
+	ID              uuid.UUID
+	CompletedAt     time.Time
+	ExpectedVersion *int64
+}
+
+func (CompleteToDoEvent) GetTopic() string {
+	return ToDoEventTopic
+}
+
+func (CompleteToDoEvent) GetName() string {
+	return "CompleteToDoEvent"
+}
+
+// ReopenToDoEvent opens again a ToDo which was done or cancelled, or puts back an in progress ToDo.
+type ReopenToDoEvent struct {
+	ID              uuid.UUID
+	ExpectedVersion *int64
+}
+
+func (ReopenToDoEvent) GetTopic() string {
+	return ToDoEventTopic
+}
+
+func (ReopenToDoEvent) GetName() string {
+	return "ReopenToDoEvent"
+}
+
+// StartToDoEvent marks an open ToDo as in progress.
+type StartToDoEvent struct {
+	ID              uuid.UUID
+	ExpectedVersion *int64
+}
+
+func (StartToDoEvent) GetTopic() string {
+	return ToDoEventTopic
+}
+
+func (StartToDoEvent) GetName() string {
+	return "StartToDoEvent"
+}
+
+// CancelToDoEvent gives up an open or in progress ToDo.
+type CancelToDoEvent struct {
+	ID              uuid.UUID
+	ExpectedVersion *int64
+}
+
+func (CancelToDoEvent) GetTopic() string {
+	return ToDoEventTopic
+}
+
+func (CancelToDoEvent) GetName() string {
+	return "CancelToDoEvent"
+}
+
+// RescheduleToDoEvent changes the due date of an unfinished ToDo, a nil due date removing it.
+type RescheduleToDoEvent struct {
+	ID              uuid.UUID
+	DueAt           *time.Time
+	ExpectedVersion *int64
+}
+
+func (RescheduleToDoEvent) GetTopic() string {
+	return ToDoEventTopic
+}
+
+func (RescheduleToDoEvent) GetName() string {
+	return "RescheduleToDoEvent"
+}
+
+// CreateToDoItemEvent adds an item to the checklist of a ToDo, at the given position or after the last item.
+type CreateToDoItemEvent struct {
+	ID              uuid.UUID
+	ToDoID          uuid.UUID
+	Title           string
+	Position        *int
+	CreatedAt       time.Time
+	ExpectedVersion *int64
+}
+
+func (CreateToDoItemEvent) GetTopic() string {
+	return ToDoEventTopic
+}
+
+func (CreateToDoItemEvent) GetName() string {
+	return "CreateToDoItemEvent"
+}
+
+// UpdateToDoItemEvent changes the title or completion of a checklist item, nil fields being kept.
+type UpdateToDoItemEvent struct {
+	ID              uuid.UUID
+	ToDoID          uuid.UUID
+	Title           *string
+	Done            *bool
+	UpdatedAt       time.Time
+	ExpectedVersion *int64
+}
+
+func (UpdateToDoItemEvent) GetTopic() string {
+	return ToDoEventTopic
+}
+
+func (UpdateToDoItemEvent) GetName() string {
+	return "UpdateToDoItemEvent"
+}
+
+type DeleteToDoItemEvent struct {
+	ID              uuid.UUID
+	ToDoID          uuid.UUID
+	ExpectedVersion *int64
+}
+
+func (DeleteToDoItemEvent) GetTopic() string {
+	return ToDoEventTopic
+}
+
+func (DeleteToDoItemEvent) GetName() string {
+	return "DeleteToDoItemEvent"
+}
+
+// ReorderToDoItemsEvent sorts the checklist of a ToDo in the order of Items, which lists every item of the ToDo once.
+type ReorderToDoItemsEvent struct {
+	ToDoID          uuid.UUID
+	Items           []uuid.UUID
+	ExpectedVersion *int64
+}
+
+func (ReorderToDoItemsEvent) GetTopic() string {
+	return ToDoEventTopic
+}
+
+func (ReorderToDoItemsEvent) GetName() string {
+	return "ReorderToDoItemsEvent"
+}
+
+// CreateToDoReminderEvent adds a reminder sent BeforeDue the due date of a ToDo.
+type CreateToDoReminderEvent struct {
+	ID        uuid.UUID
+	ToDoID    uuid.UUID
+	BeforeDue time.Duration
+	CreatedAt time.Time
+}
+
+func (CreateToDoReminderEvent) GetTopic() string {
+	return ToDoEventTopic
+}
+
+func (CreateToDoReminderEvent) GetName() string {
+	return "CreateToDoReminderEvent"
+}
+
+type DeleteToDoReminderEvent struct {
+	ID     uuid.UUID
+	ToDoID uuid.UUID
+}
+
+func (DeleteToDoReminderEvent) GetTopic() string {
+	return ToDoEventTopic
+}
+
+func (DeleteToDoReminderEvent) GetName() string {
+	return "DeleteToDoReminderEvent"
+}
+
 type GetToDoEvent struct {
 	ID uuid.UUID
 }
@@ -62,9 +247,25 @@ func (GetToDoEvent) GetName() string {
 	return "GetToDoEvent"
 }
 
+type GetToDoListEvent struct {
+	Filter     ToDoFilter
+	SortField  ToDoSortField
+	Descending bool
+	Page       ToDoPageRequest
+}
+
+func (GetToDoListEvent) GetTopic() string {
+	return ToDoEventTopic
+}
+
+func (GetToDoListEvent) GetName() string {
+	return "GetToDoListEvent"
+}
+
 type RemoveCategoriesFromToDoEvent struct {
-	ToDoID     uuid.UUID
-	Categories []uuid.UUID
+	ToDoID          uuid.UUID
+	Categories      []uuid.UUID
+	ExpectedVersion *int64
 }
 
 func (RemoveCategoriesFromToDoEvent) GetTopic() string {
@@ -76,8 +277,9 @@ func (RemoveCategoriesFromToDoEvent) GetName() string {
 }
 
 type AddCategoriesFromToDoEvent struct {
-	ToDoID     uuid.UUID
-	Categories []uuid.UUID
+	ToDoID          uuid.UUID
+	Categories      []uuid.UUID
+	ExpectedVersion *int64
 }
 
 func (AddCategoriesFromToDoEvent) GetTopic() string {
@@ -87,3 +289,16 @@ func (AddCategoriesFromToDoEvent) GetTopic() string {
 func (AddCategoriesFromToDoEvent) GetName() string {
 	return "AddCategoriesFromToDoEvent"
 }
+
+type SearchToDoEvent struct {
+	Query string
+	Limit int
+}
+
+func (SearchToDoEvent) GetTopic() string {
+	return ToDoEventTopic
+}
+
+func (SearchToDoEvent) GetName() string {
+	return "SearchToDoEvent"
+}
@@ -0,0 +1,31 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// ToDoItem is an entry of the checklist of a ToDo. Items are ordered by Position, starting from 0.
+type ToDoItem struct {
+	ID        uuid.UUID
+	ToDoID    uuid.UUID
+	Title     string
+	Done      bool
+	Position  int
+	CreatedAt *time.Time
+	UpdatedAt *time.Time
+}
+
+// ToDoProgress returns the percentage of done items, rounded down. ToDos without items have made no progress.
+func ToDoProgress(items []ToDoItem) int {
+	if len(items) == 0 {
+		return 0
+	}
+	done := 0
+	for _, item := range items {
+		if item.Done {
+			done++
+		}
+	}
+	return done * 100 / len(items)
+}
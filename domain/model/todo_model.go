@@ -11,5 +11,142 @@ type ToDo struct {
 	Description string
 	CreatedAt   *time.Time
 	UpdatedAt   *time.Time
-	Categories  []Category
+	Status      ToDoStatus
+	Priority    ToDoPriority
+	DueAt       *time.Time
+	// CompletedAt is only set while the ToDo is done
+	CompletedAt *time.Time
+	// Recurrence schedules the next occurrence of the ToDo once it is done. Occurrences after the first one belong to
+	// the series named by SeriesID, Occurrence numbering them from 0.
+	Recurrence *RecurrenceRule
+	SeriesID   *uuid.UUID
+	Occurrence int
+	// Version is incremented by every change of the ToDo, its categories included
+	Version    int64
+	Categories []Category
+	Items      []ToDoItem
+}
+
+type ToDoStatus string
+
+const (
+	OpenToDoStatus       ToDoStatus = "open"
+	InProgressToDoStatus ToDoStatus = "in-progress"
+	DoneToDoStatus       ToDoStatus = "done"
+	CancelledToDoStatus  ToDoStatus = "cancelled"
+)
+
+// toDoStatusTransitions lists the statuses each status can change to. Finished ToDos must be reopened before being
+// worked on again.
+var toDoStatusTransitions = map[ToDoStatus][]ToDoStatus{
+	OpenToDoStatus:       {InProgressToDoStatus, DoneToDoStatus, CancelledToDoStatus},
+	InProgressToDoStatus: {OpenToDoStatus, DoneToDoStatus, CancelledToDoStatus},
+	DoneToDoStatus:       {OpenToDoStatus},
+	CancelledToDoStatus:  {OpenToDoStatus},
+}
+
+func (status ToDoStatus) CanChangeTo(target ToDoStatus) bool {
+	for _, allowed := range toDoStatusTransitions[status] {
+		if allowed == target {
+			return true
+		}
+	}
+	return false
+}
+
+// IsFinished tells whether the ToDo needs no more work, in which case it can be neither overdue nor rescheduled.
+func (status ToDoStatus) IsFinished() bool {
+	return status == DoneToDoStatus || status == CancelledToDoStatus
+}
+
+// ToDoStatusesChangingTo returns the statuses which can change to the target status.
+func ToDoStatusesChangingTo(target ToDoStatus) []ToDoStatus {
+	statuses := make([]ToDoStatus, 0)
+	for _, status := range []ToDoStatus{OpenToDoStatus, InProgressToDoStatus, DoneToDoStatus, CancelledToDoStatus} {
+		if status.CanChangeTo(target) {
+			statuses = append(statuses, status)
+		}
+	}
+	return statuses
+}
+
+// UnfinishedToDoStatuses are the statuses of ToDos which still need work.
+var UnfinishedToDoStatuses = []ToDoStatus{OpenToDoStatus, InProgressToDoStatus}
+
+// ToDoStatusChange moves a ToDo to another status. CompletedAt is only given for ToDos becoming done.
+type ToDoStatusChange struct {
+	ID              uuid.UUID
+	Status          ToDoStatus
+	CompletedAt     *time.Time
+	ExpectedVersion *int64
+}
+
+type ToDoPriority string
+
+const (
+	LowToDoPriority    ToDoPriority = "low"
+	MediumToDoPriority ToDoPriority = "medium"
+	HighToDoPriority   ToDoPriority = "high"
+)
+
+func (priority ToDoPriority) IsValid() bool {
+	return priority == LowToDoPriority || priority == MediumToDoPriority || priority == HighToDoPriority
+}
+
+// ToDoField names a field of a ToDo which can be changed by an update.
+type ToDoField string
+
+const (
+	ToDoTitleField       ToDoField = "title"
+	ToDoDescriptionField ToDoField = "description"
+	ToDoPriorityField    ToDoField = "priority"
+	ToDoRecurrenceField  ToDoField = "recurrence"
+)
+
+type ToDoSortField string
+
+const (
+	SortToDoByCreatedAt ToDoSortField = "created_at"
+	// SortToDoByUpdatedAt sorts ToDos which were never updated by their creation date
+	SortToDoByUpdatedAt ToDoSortField = "updated_at"
+	SortToDoByTitle     ToDoSortField = "title"
+)
+
+// ToDoFilter narrows a ToDo list, nil or empty filters are not applied. Ranges include their start and exclude their
+// end, and Title matches ToDos whose title contains it regardless of case. Overdue keeps the unfinished ToDos whose
+// due date is past, and DueWithin the unfinished ToDos due within the given duration from now.
+type ToDoFilter struct {
+	CategoryID  *uuid.UUID
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+	UpdatedFrom *time.Time
+	UpdatedTo   *time.Time
+	Title       string
+	Status      *ToDoStatus
+	Priority    *ToDoPriority
+	Overdue     bool
+	DueWithin   *time.Duration
+}
+
+// ToDoPageRequest selects a page either by Cursor, as returned with a previous page, or by Offset.
+type ToDoPageRequest struct {
+	Limit  int
+	Offset int
+	Cursor string
+}
+
+type ToDoPage struct {
+	ToDos          []ToDo
+	HasNext        bool
+	HasPrevious    bool
+	NextCursor     string
+	PreviousCursor string
+}
+
+// ToDoSearchHit is a ToDo matching a text search. Highlights wrap the matched words in <mark> tags.
+type ToDoSearchHit struct {
+	ToDo           ToDo
+	Rank           float64
+	TitleHighlight string
+	Snippet        string
 }
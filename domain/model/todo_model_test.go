@@ -0,0 +1,66 @@
+package model
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestToDoStatusCanChangeTo(t *testing.T) {
+	statuses := []ToDoStatus{OpenToDoStatus, InProgressToDoStatus, DoneToDoStatus, CancelledToDoStatus}
+	allowed := map[ToDoStatus]map[ToDoStatus]bool{
+		OpenToDoStatus:       {InProgressToDoStatus: true, DoneToDoStatus: true, CancelledToDoStatus: true},
+		InProgressToDoStatus: {OpenToDoStatus: true, DoneToDoStatus: true, CancelledToDoStatus: true},
+		DoneToDoStatus:       {OpenToDoStatus: true},
+		CancelledToDoStatus:  {OpenToDoStatus: true},
+	}
+	for _, from := range statuses {
+		for _, to := range statuses {
+			t.Run(string(from)+" to "+string(to), func(t *testing.T) {
+				if actual := from.CanChangeTo(to); actual != allowed[from][to] {
+					t.Errorf("expected %s to be allowed to change to %s: %t, got %t", from, to, allowed[from][to], actual)
+				}
+			})
+		}
+	}
+	if ToDoStatus("unknown").CanChangeTo(OpenToDoStatus) || OpenToDoStatus.CanChangeTo("unknown") {
+		t.Error("expected unknown statuses to change neither from nor to any status")
+	}
+}
+
+func TestToDoStatusesChangingTo(t *testing.T) {
+	tests := []struct {
+		target   ToDoStatus
+		expected []ToDoStatus
+	}{
+		{target: OpenToDoStatus, expected: []ToDoStatus{InProgressToDoStatus, DoneToDoStatus, CancelledToDoStatus}},
+		{target: InProgressToDoStatus, expected: []ToDoStatus{OpenToDoStatus}},
+		{target: DoneToDoStatus, expected: []ToDoStatus{OpenToDoStatus, InProgressToDoStatus}},
+		{target: CancelledToDoStatus, expected: []ToDoStatus{OpenToDoStatus, InProgressToDoStatus}},
+	}
+	for _, test := range tests {
+		t.Run(string(test.target), func(t *testing.T) {
+			if actual := ToDoStatusesChangingTo(test.target); !reflect.DeepEqual(actual, test.expected) {
+				t.Errorf("expected %v, got %v", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestToDoStatusIsFinished(t *testing.T) {
+	tests := map[ToDoStatus]bool{
+		OpenToDoStatus:       false,
+		InProgressToDoStatus: false,
+		DoneToDoStatus:       true,
+		CancelledToDoStatus:  true,
+	}
+	for status, expected := range tests {
+		if actual := status.IsFinished(); actual != expected {
+			t.Errorf("expected %s to be finished: %t, got %t", status, expected, actual)
+		}
+	}
+	for _, status := range UnfinishedToDoStatuses {
+		if status.IsFinished() {
+			t.Errorf("expected the unfinished status %s not to be finished", status)
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// ToDoReminder notifies about a ToDo some time before it is due. A reminder is sent once for every due date of the
+// ToDo, so rescheduling the ToDo arms it again.
+type ToDoReminder struct {
+	ID        uuid.UUID
+	ToDoID    uuid.UUID
+	BeforeDue time.Duration
+	CreatedAt time.Time
+	// SentDueAt is the due date the reminder was last sent for
+	SentDueAt *time.Time
+	SentAt    *time.Time
+}
+
+// RemindAt returns when the reminder is sent for the given due date, nil when the ToDo is not due.
+func (reminder ToDoReminder) RemindAt(dueAt *time.Time) *time.Time {
+	if dueAt == nil {
+		return nil
+	}
+	remindAt := dueAt.Add(-reminder.BeforeDue)
+	return &remindAt
+}
+
+// IsSent tells whether the reminder was already sent for the given due date.
+func (reminder ToDoReminder) IsSent(dueAt *time.Time) bool {
+	return dueAt != nil && reminder.SentDueAt != nil && reminder.SentDueAt.Equal(*dueAt)
+}
+
+// UpcomingToDoReminder is a reminder of an unfinished ToDo which was not sent yet for the current due date of the ToDo.
+type UpcomingToDoReminder struct {
+	ID       uuid.UUID
+	ToDoID   uuid.UUID
+	Title    string
+	DueAt    time.Time
+	RemindAt time.Time
+}
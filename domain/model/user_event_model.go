@@ -1,6 +1,9 @@
 package model
 
-import "github.com/google/uuid"
+import (
+	"event-bus-demo/infrastructure/event_sourcing"
+	"github.com/google/uuid"
+)
 
 const UserEventTopic = "USERS"
 
@@ -13,7 +16,7 @@ func (GetUserByIDEvent) GetTopic() string {
 }
 
 func (GetUserByIDEvent) GetName() string {
-	return "CreateUserEvent"
+	return "GetUserByIDEvent"
 }
 
 type CreateUserEvent struct {
@@ -40,7 +43,12 @@ func (UpdateUserPasswordEvent) GetTopic() string {
 }
 
 func (UpdateUserPasswordEvent) GetName() string {
-	return "CreateUserEvent"
+	return "UpdateUserPasswordEvent"
+}
+
+// GetPriority lets a password change, which may be revoking a leaked password, overtake the other events of the bus.
+func (UpdateUserPasswordEvent) GetPriority() event_sourcing.Priority {
+	return event_sourcing.HighPriority
 }
 
 type DeleteUserEvent struct {
@@ -52,5 +60,10 @@ func (DeleteUserEvent) GetTopic() string {
 }
 
 func (DeleteUserEvent) GetName() string {
-	return "CreateUserEvent"
+	return "DeleteUserEvent"
+}
+
+// GetPriority lets the deletion of a user, which revokes its access, overtake the other events of the bus.
+func (DeleteUserEvent) GetPriority() event_sourcing.Priority {
+	return event_sourcing.HighPriority
 }
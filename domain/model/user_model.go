@@ -6,6 +6,11 @@ import (
 	"github.com/google/uuid"
 )
 
+const (
+	UserRole  = "USER"
+	AdminRole = "ADMIN"
+)
+
 type User struct {
 	ID       uuid.UUID
 	Username string
@@ -13,11 +18,11 @@ type User struct {
 	Role     string
 }
 
-func (u User) GetPassword() string {
+func (u *User) GetPassword() string {
 	return u.password
 }
 
-func (u User) SetNonHashedPassword(password string) error.InfrastructureError {
+func (u *User) SetNonHashedPassword(password string) error.InfrastructureError {
 	password, err := util.HashPassword(password)
 	if err != nil {
 		return error.NewHashingError("error while hashing password")
@@ -26,6 +31,10 @@ func (u User) SetNonHashedPassword(password string) error.InfrastructureError {
 	return nil
 }
 
-func (u User) SetHashedPassword(hashedPassword string) {
+func (u *User) SetHashedPassword(hashedPassword string) {
 	u.password = hashedPassword
 }
+
+func (u *User) IsPassword(password string) bool {
+	return util.CheckPassword(u.password, password)
+}
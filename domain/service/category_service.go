@@ -1,69 +1,81 @@
 package service
 
 import (
+	"context"
 	"event-bus-demo/application/dto"
 	"event-bus-demo/domain/error"
 	"event-bus-demo/domain/mapper"
 	"event-bus-demo/domain/model"
 	"event-bus-demo/infrastructure/database/service"
+	errorInfrastructure "event-bus-demo/infrastructure/error"
+	"event-bus-demo/infrastructure/logging"
+	"fmt"
 	"github.com/google/uuid"
 	"go.uber.org/zap"
 )
 
 type CategoryReadService interface {
-	GetCategories() (dto.GetCategoriesResponse, error.DomainError)
-	GetCategoryById(event model.GetCategoryByIDEvent) (dto.GetCategoryResponse, error.DomainError)
-	GetCategoriesByIds([]uuid.UUID) (dto.GetCategoriesResponse, error.DomainError)
+	GetCategories(ctx context.Context) (dto.GetCategoriesResponse, error.DomainError)
+	GetCategoryById(ctx context.Context, event model.GetCategoryByIDEvent) (dto.GetCategoryResponse, error.DomainError)
+	GetCategoriesByIds(ctx context.Context, categoriesID []uuid.UUID) (dto.GetCategoriesResponse, error.DomainError)
+	CheckCategoryDeletion(ctx context.Context, event model.DeleteCategoryEvent) error.DomainError
+	CheckCategoryVersion(ctx context.Context, ID uuid.UUID, expectedVersion *int64) error.DomainError
 }
 
 type CategoryWriteService interface {
-	AddUser(event model.CreateCategoryEvent) error.DomainError
-	UpdateUser(event model.UpdateCategoryNameEvent) error.DomainError
-	DeleteUser(event model.DeleteCategoryEvent) error.DomainError
+	AddUser(ctx context.Context, event model.CreateCategoryEvent) error.DomainError
+	UpdateUser(ctx context.Context, event model.UpdateCategoryNameEvent) error.DomainError
+	DeleteUser(ctx context.Context, event model.DeleteCategoryEvent) error.DomainError
 }
 
 type categoryReadService struct {
 	logger                  *zap.Logger
 	categoryDatabaseService service.CategoryDatabaseService
+	deletionPolicy          model.CategoryDeletionPolicy
 	domainAdvice            error.DomainAdvice
 }
 
 type categoryWriteService struct {
 	logger                  *zap.Logger
 	categoryDatabaseService service.CategoryDatabaseService
+	deletionPolicy          model.CategoryDeletionPolicy
 	domainAdvice            error.DomainAdvice
 }
 
-func NewCategoryReadService(categoryDatabaseService service.CategoryDatabaseService, domainAdvice error.DomainAdvice, logger *zap.Logger) CategoryReadService {
+func NewCategoryReadService(categoryDatabaseService service.CategoryDatabaseService, deletionPolicy model.CategoryDeletionPolicy, domainAdvice error.DomainAdvice, logger *zap.Logger) CategoryReadService {
 	return &categoryReadService{
 		logger:                  logger,
 		categoryDatabaseService: categoryDatabaseService,
+		deletionPolicy:          deletionPolicy,
 		domainAdvice:            domainAdvice,
 	}
 }
 
-func NewCategoryWriteService(categoryDatabaseService service.CategoryDatabaseService, domainAdvice error.DomainAdvice, logger *zap.Logger) CategoryWriteService {
+func NewCategoryWriteService(categoryDatabaseService service.CategoryDatabaseService, deletionPolicy model.CategoryDeletionPolicy, domainAdvice error.DomainAdvice, logger *zap.Logger) CategoryWriteService {
 	return &categoryWriteService{
 		logger:                  logger,
 		categoryDatabaseService: categoryDatabaseService,
+		deletionPolicy:          deletionPolicy,
 		domainAdvice:            domainAdvice,
 	}
 }
 
-func (service *categoryReadService) GetCategories() (dto.GetCategoriesResponse, error.DomainError) {
-	categories, err := service.categoryDatabaseService.GetAllCategories()
+func (service *categoryReadService) GetCategories(ctx context.Context) (dto.GetCategoriesResponse, error.DomainError) {
+	logging.FromContext(ctx, service.logger).Debug("retrieving category list")
+	categories, err := service.categoryDatabaseService.GetAllCategories(ctx)
 	return mapper.NewGetCategoriesResponseFromDomainModelList(categories), service.domainAdvice.TranslateError(err)
 }
 
-func (service *categoryReadService) GetCategoryById(event model.GetCategoryByIDEvent) (dto.GetCategoryResponse, error.DomainError) {
-	category, err := service.categoryDatabaseService.GetCategory(event.ID)
+func (service *categoryReadService) GetCategoryById(ctx context.Context, event model.GetCategoryByIDEvent) (dto.GetCategoryResponse, error.DomainError) {
+	logging.FromContext(ctx, service.logger).Debug("retrieving category", zap.Stringer("id", event.ID))
+	category, err := service.categoryDatabaseService.GetCategory(ctx, event.ID)
 	return mapper.NewGetCategoryResponseFromDomainModel(category), service.domainAdvice.TranslateError(err)
 }
 
-func (service *categoryReadService) GetCategoriesByIds(categoriesID []uuid.UUID) (dto.GetCategoriesResponse, error.DomainError) {
+func (service *categoryReadService) GetCategoriesByIds(ctx context.Context, categoriesID []uuid.UUID) (dto.GetCategoriesResponse, error.DomainError) {
 	response := make([]dto.GetCategoryResponse, 0)
 	for _, categoryID := range categoriesID {
-		foundCategory, err := service.GetCategoryById(model.GetCategoryByIDEvent{ID: categoryID})
+		foundCategory, err := service.GetCategoryById(ctx, model.GetCategoryByIDEvent{ID: categoryID})
 		if err != nil {
 			return dto.GetCategoriesResponse{}, err
 		}
@@ -74,25 +86,78 @@ func (service *categoryReadService) GetCategoriesByIds(categoriesID []uuid.UUID)
 	}, nil
 }
 
-func (service *categoryWriteService) AddUser(event model.CreateCategoryEvent) error.DomainError {
+// CheckCategoryDeletion tells whether the category can be deleted under the deletion policy, so deletions which would
+// be refused are reported to the caller instead of failing once the event is handled.
+func (service *categoryReadService) CheckCategoryDeletion(ctx context.Context, event model.DeleteCategoryEvent) error.DomainError {
+	if _, err := service.categoryDatabaseService.GetCategory(ctx, event.ID); err != nil {
+		return service.domainAdvice.TranslateError(err)
+	}
+	switch service.deletionPolicy.Mode {
+	case model.RestrictCategoryDeletion:
+		if count, err := service.categoryDatabaseService.CountCategoryToDos(ctx, event.ID); err != nil {
+			return service.domainAdvice.TranslateError(err)
+		} else if count > 0 {
+			return error.NewConflictError(fmt.Sprintf("category with ID %s still has %d ToDos", event.ID, count))
+		}
+	case model.ReassignCategoryDeletion:
+		if event.ID == service.deletionPolicy.DefaultCategoryID {
+			return error.NewConflictError("the default category cannot be deleted")
+		} else if _, err := service.categoryDatabaseService.GetCategory(ctx, service.deletionPolicy.DefaultCategoryID); err != nil {
+			return error.NewConflictError(fmt.Sprintf("default category with ID %s not found", service.deletionPolicy.DefaultCategoryID))
+		}
+	}
+	return nil
+}
+
+// CheckCategoryVersion tells whether the category is still at the expected version, so stale changes are refused
+// before their event is published. Nothing is checked without an expected version.
+func (service *categoryReadService) CheckCategoryVersion(ctx context.Context, ID uuid.UUID, expectedVersion *int64) error.DomainError {
+	if expectedVersion == nil {
+		return nil
+	}
+	category, err := service.categoryDatabaseService.GetCategory(ctx, ID)
+	if err != nil {
+		return service.domainAdvice.TranslateError(err)
+	} else if category.Version != *expectedVersion {
+		return error.NewVersionMismatchError(fmt.Sprintf("category item with ID %s is at version %d, not %d", ID, category.Version, *expectedVersion))
+	}
+	return nil
+}
+
+func (service *categoryWriteService) AddUser(ctx context.Context, event model.CreateCategoryEvent) error.DomainError {
+	logging.FromContext(ctx, service.logger).Debug("creating category", zap.Stringer("id", event.ID))
 	category := model.Category{
 		ID:   event.ID,
 		Name: event.Name,
 	}
-	err := service.categoryDatabaseService.CreateCategory(category)
+	err := service.categoryDatabaseService.CreateCategory(ctx, category)
 	return service.domainAdvice.TranslateError(err)
 }
 
-func (service *categoryWriteService) UpdateUser(event model.UpdateCategoryNameEvent) error.DomainError {
+func (service *categoryWriteService) UpdateUser(ctx context.Context, event model.UpdateCategoryNameEvent) error.DomainError {
+	logging.FromContext(ctx, service.logger).Debug("updating category name", zap.Stringer("id", event.ID))
 	category := model.Category{
 		ID:   event.ID,
 		Name: event.Name,
 	}
-	err := service.categoryDatabaseService.UpdateCategory(category)
+	err := service.categoryDatabaseService.UpdateCategory(ctx, category, event.ExpectedVersion)
 	return service.domainAdvice.TranslateError(err)
 }
 
-func (service *categoryWriteService) DeleteUser(event model.DeleteCategoryEvent) error.DomainError {
-	err := service.categoryDatabaseService.DeleteCategory(event.ID)
+func (service *categoryWriteService) DeleteUser(ctx context.Context, event model.DeleteCategoryEvent) error.DomainError {
+	logging.FromContext(ctx, service.logger).Debug("deleting category", zap.Stringer("id", event.ID),
+		zap.String("policy", string(service.deletionPolicy.Mode)))
+	var err errorInfrastructure.InfrastructureError
+	switch service.deletionPolicy.Mode {
+	case model.UnlinkCategoryDeletion:
+		err = service.categoryDatabaseService.DeleteCategoryUnlinkingToDos(ctx, event.ID, event.ExpectedVersion)
+	case model.ReassignCategoryDeletion:
+		if event.ID == service.deletionPolicy.DefaultCategoryID {
+			return error.NewConflictError("the default category cannot be deleted")
+		}
+		err = service.categoryDatabaseService.DeleteCategoryReassigningToDos(ctx, event.ID, service.deletionPolicy.DefaultCategoryID, event.ExpectedVersion)
+	default:
+		err = service.categoryDatabaseService.DeleteCategory(ctx, event.ID, event.ExpectedVersion)
+	}
 	return service.domainAdvice.TranslateError(err)
 }
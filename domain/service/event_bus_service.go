@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"event-bus-demo/application/dto"
+	"event-bus-demo/domain/error"
+	"event-bus-demo/domain/mapper"
+	"event-bus-demo/infrastructure/event_sourcing"
+	"event-bus-demo/infrastructure/logging"
+	"event-bus-demo/infrastructure/resilience"
+	"go.uber.org/zap"
+	"time"
+)
+
+const defaultDrainTimeout = 30 * time.Second
+
+type EventBusAdminService interface {
+	GetEventBus(ctx context.Context) dto.GetEventBusResponse
+	PauseTopic(ctx context.Context, topic string) error.DomainError
+	ResumeTopic(ctx context.Context, topic string) error.DomainError
+	DrainTopic(ctx context.Context, topic string, request dto.DrainTopicRequest) error.DomainError
+	SetMaxWorkers(ctx context.Context, request dto.UpdateEventBusWorkersRequest) error.DomainError
+	PublishEvent(ctx context.Context, request dto.PublishEventRequest) error.DomainError
+}
+
+type eventBusAdminService struct {
+	logger          *zap.Logger
+	eventBus        event_sourcing.EventBus
+	circuitBreakers []resilience.CircuitBreaker
+	domainAdvice    error.DomainAdvice
+}
+
+func NewEventBusAdminService(eventBus event_sourcing.EventBus, circuitBreakers []resilience.CircuitBreaker, advice error.DomainAdvice, logger *zap.Logger) EventBusAdminService {
+	return &eventBusAdminService{
+		logger:          logger,
+		eventBus:        eventBus,
+		circuitBreakers: circuitBreakers,
+		domainAdvice:    advice,
+	}
+}
+
+func (service *eventBusAdminService) GetEventBus(ctx context.Context) dto.GetEventBusResponse {
+	logging.FromContext(ctx, service.logger).Debug("retrieving event bus status")
+	snapshots := make([]resilience.CircuitBreakerSnapshot, 0)
+	for _, breaker := range service.circuitBreakers {
+		snapshots = append(snapshots, breaker.Snapshot())
+	}
+	return mapper.NewGetEventBusResponse(service.eventBus.Describe(), service.eventBus.Stats(), snapshots)
+}
+
+func (service *eventBusAdminService) PauseTopic(ctx context.Context, topic string) error.DomainError {
+	logging.FromContext(ctx, service.logger).Debug("pausing event bus topic", zap.String("topic", topic))
+	return service.domainAdvice.TranslateError(service.eventBus.PauseTopic(topic))
+}
+
+func (service *eventBusAdminService) ResumeTopic(ctx context.Context, topic string) error.DomainError {
+	logging.FromContext(ctx, service.logger).Debug("resuming event bus topic", zap.String("topic", topic))
+	return service.domainAdvice.TranslateError(service.eventBus.ResumeTopic(topic))
+}
+
+func (service *eventBusAdminService) DrainTopic(ctx context.Context, topic string, request dto.DrainTopicRequest) error.DomainError {
+	logging.FromContext(ctx, service.logger).Debug("draining event bus topic", zap.String("topic", topic))
+	timeout := defaultDrainTimeout
+	if request.TimeoutSeconds > 0 {
+		timeout = time.Duration(request.TimeoutSeconds) * time.Second
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	return service.domainAdvice.TranslateError(service.eventBus.DrainTopic(ctx, topic))
+}
+
+func (service *eventBusAdminService) SetMaxWorkers(ctx context.Context, request dto.UpdateEventBusWorkersRequest) error.DomainError {
+	logging.FromContext(ctx, service.logger).Debug("changing event bus max workers", zap.Int("max_workers", request.MaxWorkers))
+	return service.domainAdvice.TranslateError(service.eventBus.SetMaxWorkers(request.MaxWorkers))
+}
+
+func (service *eventBusAdminService) PublishEvent(ctx context.Context, request dto.PublishEventRequest) error.DomainError {
+	logging.FromContext(ctx, service.logger).Info("publishing event from admin API", zap.String("event", request.Name))
+	event, err := service.eventBus.NewEvent(request.Name, request.Payload)
+	if err != nil {
+		return service.domainAdvice.TranslateError(err)
+	}
+	service.eventBus.Publish(ctx, event)
+	return nil
+}
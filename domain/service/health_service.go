@@ -0,0 +1,48 @@
+package service
+
+import (
+	"context"
+	"event-bus-demo/application/dto"
+	"event-bus-demo/domain/mapper"
+	"event-bus-demo/infrastructure/health"
+	"event-bus-demo/infrastructure/logging"
+	"go.uber.org/zap"
+	"time"
+)
+
+type HealthService interface {
+	GetLiveness(ctx context.Context) dto.HealthResponse
+	GetReadiness(ctx context.Context) dto.HealthResponse
+}
+
+type healthService struct {
+	logger          *zap.Logger
+	timeout         time.Duration
+	readinessChecks []health.Checker
+}
+
+func NewHealthService(timeout time.Duration, readinessChecks []health.Checker, logger *zap.Logger) HealthService {
+	return &healthService{
+		logger:          logger,
+		timeout:         timeout,
+		readinessChecks: readinessChecks,
+	}
+}
+
+// GetLiveness only tells that the process is able to serve requests, dependencies are checked by GetReadiness so an
+// unavailable database does not get the application restarted.
+func (service *healthService) GetLiveness(context.Context) dto.HealthResponse {
+	return dto.HealthResponse{
+		Status: string(health.UpStatus),
+	}
+}
+
+func (service *healthService) GetReadiness(ctx context.Context) dto.HealthResponse {
+	ctx, cancel := context.WithTimeout(ctx, service.timeout)
+	defer cancel()
+	report := health.RunChecks(ctx, service.readinessChecks...)
+	if report.Status != health.UpStatus {
+		logging.FromContext(ctx, service.logger).Warn("application is not ready", zap.Any("checks", report.Checks))
+	}
+	return mapper.NewHealthResponse(report)
+}
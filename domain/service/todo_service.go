@@ -1,34 +1,62 @@
 package service
 
 import (
+	"context"
 	"event-bus-demo/application/dto"
 	"event-bus-demo/domain/error"
 	"event-bus-demo/domain/mapper"
 	"event-bus-demo/domain/model"
 	"event-bus-demo/infrastructure/database/service"
+	"event-bus-demo/infrastructure/logging"
+	"event-bus-demo/infrastructure/search"
 	"event-bus-demo/infrastructure/util"
+	"fmt"
 	"github.com/google/uuid"
 	"go.uber.org/zap"
 	"time"
 )
 
 type ToDoReadService interface {
-	GetAllToDo() (dto.GetAllToDoResponse, error.DomainError)
-	GetToDo(event model.GetToDoEvent) (dto.GetToDoResponse, error.DomainError)
-	IsToDoAlreadyInCategories(ID uuid.UUID, categories []uuid.UUID) (bool, error.DomainError)
+	GetToDoList(ctx context.Context, event model.GetToDoListEvent) (dto.GetAllToDoResponse, error.DomainError)
+	GetToDo(ctx context.Context, event model.GetToDoEvent) (dto.GetToDoResponse, error.DomainError)
+	SearchToDo(ctx context.Context, event model.SearchToDoEvent) (dto.SearchToDoResponse, error.DomainError)
+	IsToDoAlreadyInCategories(ctx context.Context, ID uuid.UUID, categories []uuid.UUID) (bool, error.DomainError)
+	CheckToDoVersion(ctx context.Context, ID uuid.UUID, expectedVersion *int64) error.DomainError
+	CheckToDoStatusChange(ctx context.Context, ID uuid.UUID, status model.ToDoStatus, expectedVersion *int64) error.DomainError
+	CheckToDoReschedule(ctx context.Context, ID uuid.UUID, expectedVersion *int64) error.DomainError
+	GetToDoItems(ctx context.Context, event model.GetToDoEvent) (dto.GetToDoItemsResponse, error.DomainError)
+	GetToDoOccurrences(ctx context.Context, event model.GetToDoEvent, limit int) (dto.GetToDoOccurrencesResponse, error.DomainError)
+	CheckToDoItem(ctx context.Context, toDoID uuid.UUID, ID uuid.UUID) error.DomainError
+	CheckToDoItemsOrder(ctx context.Context, toDoID uuid.UUID, items []uuid.UUID) error.DomainError
+	GetToDoReminders(ctx context.Context, event model.GetToDoEvent) (dto.GetToDoRemindersResponse, error.DomainError)
+	CheckNewToDoReminder(ctx context.Context, toDoID uuid.UUID, beforeDue time.Duration) error.DomainError
+	CheckToDoReminder(ctx context.Context, toDoID uuid.UUID, ID uuid.UUID) error.DomainError
 }
 
 type ToDoWriteService interface {
-	AddToDo(event model.CreateToDoEvent) error.DomainError
-	UpdateToDo(event model.UpdateToDoEvent) error.DomainError
-	DeleteToDo(event model.DeleteToDoEvent) error.DomainError
-	AddCategoriesIntoToDo(event model.AddCategoriesFromToDoEvent) error.DomainError
-	RemoveCategoriesFromToDo(event model.RemoveCategoriesFromToDoEvent) error.DomainError
+	AddToDo(ctx context.Context, event model.CreateToDoEvent) error.DomainError
+	UpdateToDo(ctx context.Context, event model.UpdateToDoEvent) error.DomainError
+	DeleteToDo(ctx context.Context, event model.DeleteToDoEvent) error.DomainError
+	AddCategoriesIntoToDo(ctx context.Context, event model.AddCategoriesFromToDoEvent) error.DomainError
+	RemoveCategoriesFromToDo(ctx context.Context, event model.RemoveCategoriesFromToDoEvent) error.DomainError
+	CompleteToDo(ctx context.Context, event model.CompleteToDoEvent) error.DomainError
+	ReopenToDo(ctx context.Context, event model.ReopenToDoEvent) error.DomainError
+	StartToDo(ctx context.Context, event model.StartToDoEvent) error.DomainError
+	CancelToDo(ctx context.Context, event model.CancelToDoEvent) error.DomainError
+	RescheduleToDo(ctx context.Context, event model.RescheduleToDoEvent) error.DomainError
+	AddToDoItem(ctx context.Context, event model.CreateToDoItemEvent) error.DomainError
+	UpdateToDoItem(ctx context.Context, event model.UpdateToDoItemEvent) error.DomainError
+	DeleteToDoItem(ctx context.Context, event model.DeleteToDoItemEvent) error.DomainError
+	ReorderToDoItems(ctx context.Context, event model.ReorderToDoItemsEvent) error.DomainError
+	AddToDoReminder(ctx context.Context, event model.CreateToDoReminderEvent) error.DomainError
+	DeleteToDoReminder(ctx context.Context, event model.DeleteToDoReminderEvent) error.DomainError
+	SendToDoReminder(ctx context.Context, event model.ToDoReminderDueEvent) error.DomainError
 }
 
 type toDoReadService struct {
 	logger              *zap.Logger
 	toDoDatabaseService service.ToDoDatabaseService
+	toDoSearchIndex     search.ToDoSearchIndex
 	domainAdvice        error.DomainAdvice
 }
 
@@ -38,9 +66,10 @@ type toDoWriteService struct {
 	domainAdvice        error.DomainAdvice
 }
 
-func NewToDoReadService(toDoDatabaseService service.ToDoDatabaseService, domainAdvice error.DomainAdvice, logger *zap.Logger) ToDoReadService {
+func NewToDoReadService(toDoDatabaseService service.ToDoDatabaseService, toDoSearchIndex search.ToDoSearchIndex, domainAdvice error.DomainAdvice, logger *zap.Logger) ToDoReadService {
 	return &toDoReadService{
 		toDoDatabaseService: toDoDatabaseService,
+		toDoSearchIndex:     toDoSearchIndex,
 		logger:              logger,
 		domainAdvice:        domainAdvice,
 	}
@@ -54,18 +83,28 @@ func NewToDoWriteService(toDoDatabaseService service.ToDoDatabaseService, domain
 	}
 }
 
-func (service *toDoReadService) GetAllToDo() (dto.GetAllToDoResponse, error.DomainError) {
-	toDoList, err := service.toDoDatabaseService.GetAllToDoList()
-	return mapper.NewGetAllToDoResponseFromDomainModel(toDoList), service.domainAdvice.TranslateError(err)
+func (service *toDoReadService) GetToDoList(ctx context.Context, event model.GetToDoListEvent) (dto.GetAllToDoResponse, error.DomainError) {
+	logging.FromContext(ctx, service.logger).Debug("retrieving toDo list", zap.String("sort", string(event.SortField)),
+		zap.Int("limit", event.Page.Limit))
+	page, err := service.toDoDatabaseService.GetToDoPage(ctx, event)
+	return mapper.NewGetAllToDoResponseFromDomainModel(page), service.domainAdvice.TranslateError(err)
 }
 
-func (service *toDoReadService) GetToDo(event model.GetToDoEvent) (dto.GetToDoResponse, error.DomainError) {
-	toDo, err := service.toDoDatabaseService.GetToDo(event.ID)
+func (service *toDoReadService) GetToDo(ctx context.Context, event model.GetToDoEvent) (dto.GetToDoResponse, error.DomainError) {
+	logging.FromContext(ctx, service.logger).Debug("retrieving toDo", zap.Stringer("id", event.ID))
+	toDo, err := service.toDoDatabaseService.GetToDo(ctx, event.ID)
 	return mapper.NewGetToDoResponseFromDomainModel(toDo), service.domainAdvice.TranslateError(err)
 }
 
-func (service *toDoReadService) IsToDoAlreadyInCategories(ID uuid.UUID, categories []uuid.UUID) (bool, error.DomainError) {
-	toDo, err := service.toDoDatabaseService.GetToDo(ID)
+func (service *toDoReadService) SearchToDo(ctx context.Context, event model.SearchToDoEvent) (dto.SearchToDoResponse, error.DomainError) {
+	logging.FromContext(ctx, service.logger).Debug("searching toDo", zap.String("query", event.Query),
+		zap.Int("limit", event.Limit))
+	hits, err := service.toDoSearchIndex.Search(ctx, event.Query, event.Limit)
+	return mapper.NewSearchToDoResponseFromDomainModelList(hits), service.domainAdvice.TranslateError(err)
+}
+
+func (service *toDoReadService) IsToDoAlreadyInCategories(ctx context.Context, ID uuid.UUID, categories []uuid.UUID) (bool, error.DomainError) {
+	toDo, err := service.toDoDatabaseService.GetToDo(ctx, ID)
 	if err != nil {
 		return true, service.domainAdvice.TranslateError(err)
 	}
@@ -77,7 +116,163 @@ func (service *toDoReadService) IsToDoAlreadyInCategories(ID uuid.UUID, categori
 	return false, nil
 }
 
-func (service *toDoWriteService) AddToDo(event model.CreateToDoEvent) error.DomainError {
+// CheckToDoVersion tells whether the ToDo is still at the expected version, so stale changes are refused before their
+// event is published. Nothing is checked without an expected version.
+func (service *toDoReadService) CheckToDoVersion(ctx context.Context, ID uuid.UUID, expectedVersion *int64) error.DomainError {
+	if expectedVersion == nil {
+		return nil
+	}
+	toDo, err := service.toDoDatabaseService.GetToDo(ctx, ID)
+	if err != nil {
+		return service.domainAdvice.TranslateError(err)
+	} else if toDo.Version != *expectedVersion {
+		return error.NewVersionMismatchError(fmt.Sprintf("toDo item with ID %s is at version %d, not %d", ID, toDo.Version, *expectedVersion))
+	}
+	return nil
+}
+
+// CheckToDoStatusChange tells whether the ToDo can move to the given status, so forbidden transitions are refused
+// before their event is published.
+func (service *toDoReadService) CheckToDoStatusChange(ctx context.Context, ID uuid.UUID, status model.ToDoStatus, expectedVersion *int64) error.DomainError {
+	toDo, err := service.getToDoAtVersion(ctx, ID, expectedVersion)
+	if err != nil {
+		return err
+	} else if !toDo.Status.CanChangeTo(status) {
+		return error.NewConflictError(fmt.Sprintf("toDo item with ID %s is %s and cannot become %s", ID, toDo.Status, status))
+	}
+	return nil
+}
+
+// CheckToDoReschedule tells whether the due date of the ToDo can change, which is refused once it is finished.
+func (service *toDoReadService) CheckToDoReschedule(ctx context.Context, ID uuid.UUID, expectedVersion *int64) error.DomainError {
+	toDo, err := service.getToDoAtVersion(ctx, ID, expectedVersion)
+	if err != nil {
+		return err
+	} else if toDo.Status.IsFinished() {
+		return error.NewConflictError(fmt.Sprintf("toDo item with ID %s is %s and cannot be rescheduled", ID, toDo.Status))
+	}
+	return nil
+}
+
+func (service *toDoReadService) GetToDoItems(ctx context.Context, event model.GetToDoEvent) (dto.GetToDoItemsResponse, error.DomainError) {
+	logging.FromContext(ctx, service.logger).Debug("retrieving toDo items", zap.Stringer("id", event.ID))
+	toDo, err := service.toDoDatabaseService.GetToDo(ctx, event.ID)
+	return mapper.NewGetToDoItemsResponseFromDomainModelList(toDo.Items), service.domainAdvice.TranslateError(err)
+}
+
+// GetToDoOccurrences previews the due dates of the next occurrences of a recurring ToDo, scheduled from its due date or
+// from now when it has none. ToDos which do not recur have no occurrences.
+func (service *toDoReadService) GetToDoOccurrences(ctx context.Context, event model.GetToDoEvent, limit int) (dto.GetToDoOccurrencesResponse, error.DomainError) {
+	logging.FromContext(ctx, service.logger).Debug("retrieving toDo occurrences", zap.Stringer("id", event.ID),
+		zap.Int("limit", limit))
+	toDo, err := service.toDoDatabaseService.GetToDo(ctx, event.ID)
+	if err != nil {
+		return dto.GetToDoOccurrencesResponse{}, service.domainAdvice.TranslateError(err)
+	}
+	occurrences := make([]time.Time, 0)
+	if toDo.Recurrence != nil {
+		anchor := time.Now()
+		if toDo.DueAt != nil {
+			anchor = *toDo.DueAt
+		}
+		occurrences = toDo.Recurrence.Upcoming(anchor, toDo.Occurrence, limit)
+	}
+	return mapper.NewGetToDoOccurrencesResponse(toDo.Recurrence, occurrences), nil
+}
+
+// CheckToDoItem tells whether the item belongs to the checklist of the ToDo.
+func (service *toDoReadService) CheckToDoItem(ctx context.Context, toDoID uuid.UUID, ID uuid.UUID) error.DomainError {
+	toDo, err := service.toDoDatabaseService.GetToDo(ctx, toDoID)
+	if err != nil {
+		return service.domainAdvice.TranslateError(err)
+	}
+	for _, item := range toDo.Items {
+		if item.ID == ID {
+			return nil
+		}
+	}
+	return error.NewItemNotFoundError(fmt.Sprintf("item with ID %s of toDo item with ID %s not found", ID, toDoID))
+}
+
+// CheckToDoItemsOrder tells whether the items list every item of the checklist of the ToDo once.
+func (service *toDoReadService) CheckToDoItemsOrder(ctx context.Context, toDoID uuid.UUID, items []uuid.UUID) error.DomainError {
+	toDo, err := service.toDoDatabaseService.GetToDo(ctx, toDoID)
+	if err != nil {
+		return service.domainAdvice.TranslateError(err)
+	}
+	currentIDs := make([]uuid.UUID, 0, len(toDo.Items))
+	for _, item := range toDo.Items {
+		currentIDs = append(currentIDs, item.ID)
+	}
+	if !util.IsPermutation(currentIDs, items) {
+		return error.NewInvalidArgumentError(fmt.Sprintf("items of toDo item with ID %s must be listed once each", toDoID))
+	}
+	return nil
+}
+
+func (service *toDoReadService) GetToDoReminders(ctx context.Context, event model.GetToDoEvent) (dto.GetToDoRemindersResponse, error.DomainError) {
+	logging.FromContext(ctx, service.logger).Debug("retrieving toDo reminders", zap.Stringer("id", event.ID))
+	toDo, err := service.toDoDatabaseService.GetToDo(ctx, event.ID)
+	if err != nil {
+		return dto.GetToDoRemindersResponse{}, service.domainAdvice.TranslateError(err)
+	}
+	reminders, err := service.toDoDatabaseService.GetToDoReminders(ctx, event.ID)
+	return mapper.NewGetToDoRemindersResponseFromDomainModelList(reminders, toDo.DueAt), service.domainAdvice.TranslateError(err)
+}
+
+// CheckNewToDoReminder tells whether a reminder can be added to the ToDo, which has only one reminder for a given time
+// before its due date.
+func (service *toDoReadService) CheckNewToDoReminder(ctx context.Context, toDoID uuid.UUID, beforeDue time.Duration) error.DomainError {
+	reminders, err := service.getToDoReminders(ctx, toDoID)
+	if err != nil {
+		return err
+	}
+	for _, reminder := range reminders {
+		if reminder.BeforeDue == beforeDue {
+			return error.NewConflictError(fmt.Sprintf("toDo item with ID %s already has a reminder %s before its due date", toDoID, beforeDue))
+		}
+	}
+	return nil
+}
+
+// CheckToDoReminder tells whether the reminder belongs to the ToDo.
+func (service *toDoReadService) CheckToDoReminder(ctx context.Context, toDoID uuid.UUID, ID uuid.UUID) error.DomainError {
+	reminders, err := service.getToDoReminders(ctx, toDoID)
+	if err != nil {
+		return err
+	}
+	for _, reminder := range reminders {
+		if reminder.ID == ID {
+			return nil
+		}
+	}
+	return error.NewItemNotFoundError(fmt.Sprintf("reminder with ID %s of toDo item with ID %s not found", ID, toDoID))
+}
+
+// getToDoReminders returns the reminders of the ToDo, failing when the ToDo does not exist.
+func (service *toDoReadService) getToDoReminders(ctx context.Context, toDoID uuid.UUID) ([]model.ToDoReminder, error.DomainError) {
+	if _, err := service.toDoDatabaseService.GetToDo(ctx, toDoID); err != nil {
+		return nil, service.domainAdvice.TranslateError(err)
+	}
+	reminders, err := service.toDoDatabaseService.GetToDoReminders(ctx, toDoID)
+	if err != nil {
+		return nil, service.domainAdvice.TranslateError(err)
+	}
+	return reminders, nil
+}
+
+func (service *toDoReadService) getToDoAtVersion(ctx context.Context, ID uuid.UUID, expectedVersion *int64) (model.ToDo, error.DomainError) {
+	toDo, err := service.toDoDatabaseService.GetToDo(ctx, ID)
+	if err != nil {
+		return model.ToDo{}, service.domainAdvice.TranslateError(err)
+	} else if expectedVersion != nil && toDo.Version != *expectedVersion {
+		return model.ToDo{}, error.NewVersionMismatchError(fmt.Sprintf("toDo item with ID %s is at version %d, not %d", ID, toDo.Version, *expectedVersion))
+	}
+	return toDo, nil
+}
+
+func (service *toDoWriteService) AddToDo(ctx context.Context, event model.CreateToDoEvent) error.DomainError {
+	logging.FromContext(ctx, service.logger).Debug("creating toDo", zap.Stringer("id", event.ID))
 	categories := make([]model.Category, 0)
 	for _, categoryId := range event.Categories {
 		categories = append(categories, model.Category{
@@ -89,35 +284,137 @@ func (service *toDoWriteService) AddToDo(event model.CreateToDoEvent) error.Doma
 		Title:       event.Title,
 		Description: event.Description,
 		CreatedAt:   &event.CreatedAt,
+		Status:      model.OpenToDoStatus,
+		Priority:    event.Priority,
+		DueAt:       event.DueAt,
+		Recurrence:  event.Recurrence,
+		SeriesID:    event.SeriesID,
+		Occurrence:  event.Occurrence,
 		Categories:  categories,
 	}
-	err := service.toDoDatabaseService.CreateToDo(toDo)
+	err := service.toDoDatabaseService.CreateToDo(ctx, toDo)
 	return service.domainAdvice.TranslateError(err)
 }
 
-func (service *toDoWriteService) UpdateToDo(event model.UpdateToDoEvent) error.DomainError {
-	updatedAt := time.Now()
-	toDo := model.ToDo{
-		ID:          event.ID,
-		Title:       event.Title,
-		Description: event.Description,
-		UpdatedAt:   &updatedAt,
-	}
-	err := service.toDoDatabaseService.UpdateToDo(toDo)
+func (service *toDoWriteService) UpdateToDo(ctx context.Context, event model.UpdateToDoEvent) error.DomainError {
+	logging.FromContext(ctx, service.logger).Debug("updating toDo", zap.Stringer("id", event.ID),
+		zap.Any("fields", event.Fields))
+	err := service.toDoDatabaseService.UpdateToDo(ctx, event)
+	return service.domainAdvice.TranslateError(err)
+}
+
+func (service *toDoWriteService) DeleteToDo(ctx context.Context, event model.DeleteToDoEvent) error.DomainError {
+	logging.FromContext(ctx, service.logger).Debug("deleting toDo", zap.Stringer("id", event.ID))
+	err := service.toDoDatabaseService.DeleteToDo(ctx, event.ID, event.ExpectedVersion)
+	return service.domainAdvice.TranslateError(err)
+}
+
+func (service *toDoWriteService) AddCategoriesIntoToDo(ctx context.Context, event model.AddCategoriesFromToDoEvent) error.DomainError {
+	logging.FromContext(ctx, service.logger).Debug("adding categories into toDo", zap.Stringer("id", event.ToDoID))
+	err := service.toDoDatabaseService.AddCategoriesIntoToDo(ctx, event)
+	return service.domainAdvice.TranslateError(err)
+}
+
+func (service *toDoWriteService) RemoveCategoriesFromToDo(ctx context.Context, event model.RemoveCategoriesFromToDoEvent) error.DomainError {
+	logging.FromContext(ctx, service.logger).Debug("removing categories from toDo", zap.Stringer("id", event.ToDoID))
+	err := service.toDoDatabaseService.RemoveCategoriesFromToDo(ctx, event)
+	return service.domainAdvice.TranslateError(err)
+}
+
+func (service *toDoWriteService) CompleteToDo(ctx context.Context, event model.CompleteToDoEvent) error.DomainError {
+	logging.FromContext(ctx, service.logger).Debug("completing toDo", zap.Stringer("id", event.ID))
+	return service.changeToDoStatus(ctx, model.ToDoStatusChange{
+		ID:              event.ID,
+		Status:          model.DoneToDoStatus,
+		CompletedAt:     &event.CompletedAt,
+		ExpectedVersion: event.ExpectedVersion,
+	})
+}
+
+func (service *toDoWriteService) ReopenToDo(ctx context.Context, event model.ReopenToDoEvent) error.DomainError {
+	logging.FromContext(ctx, service.logger).Debug("reopening toDo", zap.Stringer("id", event.ID))
+	return service.changeToDoStatus(ctx, model.ToDoStatusChange{
+		ID:              event.ID,
+		Status:          model.OpenToDoStatus,
+		ExpectedVersion: event.ExpectedVersion,
+	})
+}
+
+func (service *toDoWriteService) StartToDo(ctx context.Context, event model.StartToDoEvent) error.DomainError {
+	logging.FromContext(ctx, service.logger).Debug("starting toDo", zap.Stringer("id", event.ID))
+	return service.changeToDoStatus(ctx, model.ToDoStatusChange{
+		ID:              event.ID,
+		Status:          model.InProgressToDoStatus,
+		ExpectedVersion: event.ExpectedVersion,
+	})
+}
+
+func (service *toDoWriteService) CancelToDo(ctx context.Context, event model.CancelToDoEvent) error.DomainError {
+	logging.FromContext(ctx, service.logger).Debug("cancelling toDo", zap.Stringer("id", event.ID))
+	return service.changeToDoStatus(ctx, model.ToDoStatusChange{
+		ID:              event.ID,
+		Status:          model.CancelledToDoStatus,
+		ExpectedVersion: event.ExpectedVersion,
+	})
+}
+
+func (service *toDoWriteService) changeToDoStatus(ctx context.Context, change model.ToDoStatusChange) error.DomainError {
+	err := service.toDoDatabaseService.ChangeToDoStatus(ctx, change)
+	return service.domainAdvice.TranslateError(err)
+}
+
+func (service *toDoWriteService) RescheduleToDo(ctx context.Context, event model.RescheduleToDoEvent) error.DomainError {
+	logging.FromContext(ctx, service.logger).Debug("rescheduling toDo", zap.Stringer("id", event.ID))
+	err := service.toDoDatabaseService.RescheduleToDo(ctx, event)
+	return service.domainAdvice.TranslateError(err)
+}
+
+func (service *toDoWriteService) AddToDoItem(ctx context.Context, event model.CreateToDoItemEvent) error.DomainError {
+	logging.FromContext(ctx, service.logger).Debug("adding item into toDo", zap.Stringer("id", event.ToDoID),
+		zap.Stringer("item", event.ID))
+	err := service.toDoDatabaseService.CreateToDoItem(ctx, event)
+	return service.domainAdvice.TranslateError(err)
+}
+
+func (service *toDoWriteService) UpdateToDoItem(ctx context.Context, event model.UpdateToDoItemEvent) error.DomainError {
+	logging.FromContext(ctx, service.logger).Debug("updating toDo item", zap.Stringer("id", event.ToDoID),
+		zap.Stringer("item", event.ID))
+	err := service.toDoDatabaseService.UpdateToDoItem(ctx, event)
+	return service.domainAdvice.TranslateError(err)
+}
+
+func (service *toDoWriteService) DeleteToDoItem(ctx context.Context, event model.DeleteToDoItemEvent) error.DomainError {
+	logging.FromContext(ctx, service.logger).Debug("deleting toDo item", zap.Stringer("id", event.ToDoID),
+		zap.Stringer("item", event.ID))
+	err := service.toDoDatabaseService.DeleteToDoItem(ctx, event)
+	return service.domainAdvice.TranslateError(err)
+}
+
+func (service *toDoWriteService) ReorderToDoItems(ctx context.Context, event model.ReorderToDoItemsEvent) error.DomainError {
+	logging.FromContext(ctx, service.logger).Debug("reordering toDo items", zap.Stringer("id", event.ToDoID))
+	err := service.toDoDatabaseService.ReorderToDoItems(ctx, event)
 	return service.domainAdvice.TranslateError(err)
 }
 
-func (service *toDoWriteService) DeleteToDo(event model.DeleteToDoEvent) error.DomainError {
-	err := service.toDoDatabaseService.DeleteToDo(event.ID)
+func (service *toDoWriteService) AddToDoReminder(ctx context.Context, event model.CreateToDoReminderEvent) error.DomainError {
+	logging.FromContext(ctx, service.logger).Debug("adding reminder into toDo", zap.Stringer("id", event.ToDoID),
+		zap.Stringer("reminder", event.ID), zap.Duration("beforeDue", event.BeforeDue))
+	err := service.toDoDatabaseService.CreateToDoReminder(ctx, event)
 	return service.domainAdvice.TranslateError(err)
 }
 
-func (service *toDoWriteService) AddCategoriesIntoToDo(event model.AddCategoriesFromToDoEvent) error.DomainError {
-	err := service.toDoDatabaseService.AddCategoriesIntoToDo(event)
+func (service *toDoWriteService) DeleteToDoReminder(ctx context.Context, event model.DeleteToDoReminderEvent) error.DomainError {
+	logging.FromContext(ctx, service.logger).Debug("deleting toDo reminder", zap.Stringer("id", event.ToDoID),
+		zap.Stringer("reminder", event.ID))
+	err := service.toDoDatabaseService.DeleteToDoReminder(ctx, event)
 	return service.domainAdvice.TranslateError(err)
 }
 
-func (service *toDoWriteService) RemoveCategoriesFromToDo(event model.RemoveCategoriesFromToDoEvent) error.DomainError {
-	err := service.toDoDatabaseService.RemoveCategoriesFromToDo(event)
+// SendToDoReminder marks a due reminder as sent, leaving its delivery to the subscribers of the reminder topic. Marking
+// fails with a conflict when the reminder was already sent for the due date, which keeps it from being delivered twice.
+func (service *toDoWriteService) SendToDoReminder(ctx context.Context, event model.ToDoReminderDueEvent) error.DomainError {
+	logging.FromContext(ctx, service.logger).Debug("sending toDo reminder", zap.Stringer("id", event.ToDoID),
+		zap.Stringer("reminder", event.ID), zap.Time("dueAt", event.DueAt))
+	err := service.toDoDatabaseService.MarkToDoReminderSent(ctx, event)
 	return service.domainAdvice.TranslateError(err)
 }
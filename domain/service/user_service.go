@@ -1,22 +1,31 @@
 package service
 
 import (
+	"context"
 	"event-bus-demo/application/dto"
 	"event-bus-demo/domain/error"
 	"event-bus-demo/domain/mapper"
 	"event-bus-demo/domain/model"
 	"event-bus-demo/infrastructure/database/service"
+	errorInfrastructure "event-bus-demo/infrastructure/error"
+	"event-bus-demo/infrastructure/logging"
 	"go.uber.org/zap"
 )
 
+// unknownUserPasswordHash is compared with the password sent for an unknown username, so that rejecting it takes as
+// long as rejecting a wrong password and the response time does not tell which usernames exist. It is a bcrypt hash of
+// the default cost of a random password.
+const unknownUserPasswordHash = "$2a$10$7h8sMPxn6iKxyeP.ZnWs7e1oNPiVKJZ3X72336SaTYBX5PMnDfO.S"
+
 type UserReadService interface {
-	GetUserByID(event model.GetUserByIDEvent) (dto.GetUserResponse, error.DomainError)
+	GetUserByID(ctx context.Context, event model.GetUserByIDEvent) (dto.GetUserResponse, error.DomainError)
+	AuthenticateUser(ctx context.Context, username, password string) (dto.GetUserResponse, error.DomainError)
 }
 
 type UserWriteService interface {
-	AddUser(event model.CreateUserEvent) error.DomainError
-	UpdateUserPassword(event model.UpdateUserPasswordEvent) error.DomainError
-	DeleteUser(event model.DeleteUserEvent) error.DomainError
+	AddUser(ctx context.Context, event model.CreateUserEvent) error.DomainError
+	UpdateUserPassword(ctx context.Context, event model.UpdateUserPasswordEvent) error.DomainError
+	DeleteUser(ctx context.Context, event model.DeleteUserEvent) error.DomainError
 }
 
 type userReadService struct {
@@ -47,41 +56,60 @@ func NewUserWriteService(databaseService service.UserDatabaseService, advice err
 	}
 }
 
-func (service *userReadService) GetUserByID(event model.GetUserByIDEvent) (dto.GetUserResponse, error.DomainError) {
-	user, err := service.userDatabaseService.GetUser(event.ID)
+func (service *userReadService) GetUserByID(ctx context.Context, event model.GetUserByIDEvent) (dto.GetUserResponse, error.DomainError) {
+	logging.FromContext(ctx, service.logger).Debug("retrieving user", zap.Stringer("id", event.ID))
+	user, err := service.userDatabaseService.GetUser(ctx, event.ID)
 	if err != nil {
 		return dto.GetUserResponse{}, service.domainAdvice.TranslateError(err)
 	}
 	return mapper.NewGetUserResponseFromDomainModel(user), nil
 }
 
-func (service *userWriteService) AddUser(event model.CreateUserEvent) error.DomainError {
+func (service *userReadService) AuthenticateUser(ctx context.Context, username, password string) (dto.GetUserResponse, error.DomainError) {
+	user, err := service.userDatabaseService.GetUserByUsername(ctx, username)
+	if err != nil && err.GetCode() != errorInfrastructure.ItemNotFound {
+		return dto.GetUserResponse{}, service.domainAdvice.TranslateError(err)
+	} else if err != nil {
+		user.SetHashedPassword(unknownUserPasswordHash)
+	}
+	if !user.IsPassword(password) || err != nil {
+		logging.FromContext(ctx, service.logger).Info("rejected user credentials", zap.String("username", username))
+		return dto.GetUserResponse{}, error.NewInvalidCredentialsError("invalid username or password")
+	}
+	return mapper.NewGetUserResponseFromDomainModel(user), nil
+}
+
+func (service *userWriteService) AddUser(ctx context.Context, event model.CreateUserEvent) error.DomainError {
+	logging.FromContext(ctx, service.logger).Debug("creating user", zap.Stringer("id", event.ID))
 	user := model.User{
 		ID:       event.ID,
 		Username: event.Username,
+		Role:     model.UserRole,
 	}
 	if err := user.SetNonHashedPassword(event.Password); err != nil {
 		return service.domainAdvice.TranslateError(err)
-	} else if err := service.userDatabaseService.CreateUser(user); err != nil {
+	} else if err := service.userDatabaseService.CreateUser(ctx, user); err != nil {
 		return service.domainAdvice.TranslateError(err)
 	}
 	return nil
 }
 
-func (service *userWriteService) UpdateUserPassword(event model.UpdateUserPasswordEvent) error.DomainError {
+func (service *userWriteService) UpdateUserPassword(ctx context.Context, event model.UpdateUserPasswordEvent) error.DomainError {
+	logging.FromContext(ctx, service.logger).Debug("updating user password", zap.Stringer("id", event.ID))
 	user := model.User{
 		ID: event.ID,
 	}
 	if err := user.SetNonHashedPassword(event.Password); err != nil {
 		return service.domainAdvice.TranslateError(err)
-	} else if err := service.userDatabaseService.UpdateUserPassword(user); err != nil {
+	} else if err := service.userDatabaseService.UpdateUserPassword(ctx, user); err != nil {
 		return service.domainAdvice.TranslateError(err)
 	}
 	return nil
 }
 
-func (service *userWriteService) DeleteUser(event model.DeleteUserEvent) error.DomainError {
-	err := service.userDatabaseService.DeleteUser(event.ID)
+func (service *userWriteService) DeleteUser(ctx context.Context, event model.DeleteUserEvent) error.DomainError {
+	logging.FromContext(ctx, service.logger).Debug("deleting user", zap.Stringer("id", event.ID))
+	err := service.userDatabaseService.DeleteUser(ctx, event.ID)
 	if err != nil {
 		return service.domainAdvice.TranslateError(err)
 	}
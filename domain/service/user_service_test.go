@@ -0,0 +1,63 @@
+package service
+
+import (
+	"context"
+	domainError "event-bus-demo/domain/error"
+	"event-bus-demo/domain/model"
+	"event-bus-demo/infrastructure/database/service"
+	errorInfrastructure "event-bus-demo/infrastructure/error"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+	"testing"
+	"time"
+)
+
+// fakeUserDatabaseService holds a single user. Only the methods used to authenticate users are implemented.
+type fakeUserDatabaseService struct {
+	service.UserDatabaseService
+	user model.User
+}
+
+func (dbService *fakeUserDatabaseService) GetUserByUsername(_ context.Context, username string) (model.User,
+	errorInfrastructure.InfrastructureError) {
+	if username != dbService.user.Username {
+		return model.User{}, errorInfrastructure.NewItemNotFoundError("user not found")
+	}
+	return dbService.user, nil
+}
+
+func TestAuthenticateUserComparesPasswordOfUnknownUsers(t *testing.T) {
+	if cost, err := bcrypt.Cost([]byte(unknownUserPasswordHash)); err != nil || cost != bcrypt.DefaultCost {
+		t.Fatalf("expected a bcrypt hash of the default cost for unknown users, got cost %d and error %v", cost, err)
+	}
+	user := model.User{Username: "alice", Role: model.UserRole}
+	if err := user.SetNonHashedPassword("secret"); err != nil {
+		t.Fatalf("unexpected error %s", err.Error())
+	}
+	readService := NewUserReadService(&fakeUserDatabaseService{user: user}, domainError.NewDomainAdvice(), zap.NewNop())
+	tests := []struct {
+		name     string
+		username string
+		password string
+	}{
+		{name: "wrong password", username: "alice", password: "guess"},
+		{name: "unknown user", username: "bob", password: "secret"},
+		{name: "unknown user without password", username: "bob"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			start := time.Now()
+			_, err := readService.AuthenticateUser(context.Background(), test.username, test.password)
+			if err == nil || err.GetCode() != domainError.InvalidCredentials {
+				t.Fatalf("expected invalid credentials, got %v", err)
+			}
+			// Rejecting a user without comparing a password takes microseconds, a bcrypt comparison milliseconds
+			if elapsed := time.Since(start); elapsed < time.Millisecond {
+				t.Errorf("expected the password to be compared with a bcrypt hash, took %s", elapsed)
+			}
+		})
+	}
+	if _, err := readService.AuthenticateUser(context.Background(), "alice", "secret"); err != nil {
+		t.Errorf("unexpected error %s", err.Error())
+	}
+}
@@ -4,4 +4,7 @@ type Arguments struct {
 	ResourceRoot                string
 	ConfigFilePrefix            string
 	ActiveConfigurationProfiles []string
+	EventGraphFormat            string
+	// Command holds the positional arguments following the flags, e.g. migrate up
+	Command []string
 }
@@ -10,16 +10,20 @@ import (
 const resourceRootHint = "path where resources are stored."
 const configPrefixHint = "prefix of yaml configuration file."
 const activeProfilesHint = "profiles to be loaded from configuration comma separated. Priority given by order, having the first the less priority and the last the most priority."
+const eventGraphHint = "if set, prints the event bus wiring in the given format (mermaid or dot) and exits."
 
 func ParseInputArguments() Arguments {
 	resourceRootFlag := flag.String(constants.ResourceRootFlag, fmt.Sprintf(".%c", constants.ResourceRootDefaultValue), resourceRootHint)
 	configPrefixFlag := flag.String(constants.ConfigPrefixFlag, constants.ConfigPrefixDefaultValue, configPrefixHint)
 	activeProfilesFlag := flag.String(constants.ActiveProfilesFlag, constants.ActiveProfilesDefaultValue, activeProfilesHint)
+	eventGraphFlag := flag.String(constants.EventGraphFlag, constants.EventGraphDefaultValue, eventGraphHint)
 	flag.Parse()
 	activeProfiles := strings.Split(*activeProfilesFlag, ",")
 	return Arguments{
 		*resourceRootFlag,
 		*configPrefixFlag,
 		activeProfiles,
+		*eventGraphFlag,
+		flag.Args(),
 	}
 }
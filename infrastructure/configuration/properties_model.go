@@ -1,29 +1,122 @@
 package configuration
 
 type ApplicationConfiguration struct {
-	Gin   *GinConfiguration   `mapstructure:"gin" validate:"required"`
-	Event *EventConfiguration `mapstructure:"event" validate:"required"`
-	Rdbms *RdbmsConfiguration `mapstructure:"rdbms" validate:"required"`
+	Gin      *GinConfiguration      `mapstructure:"gin" validate:"required"`
+	Event    *EventConfiguration    `mapstructure:"event" validate:"required"`
+	Rdbms    *RdbmsConfiguration    `mapstructure:"rdbms" validate:"required"`
+	Metrics  *MetricsConfiguration  `mapstructure:"metrics" validate:"required"`
+	Tracing  *TracingConfiguration  `mapstructure:"tracing" validate:"required"`
+	Health   *HealthConfiguration   `mapstructure:"health" validate:"required"`
+	Category *CategoryConfiguration `mapstructure:"category" validate:"required"`
+	Search   *SearchConfiguration   `mapstructure:"search" validate:"required"`
+	Reminder *ReminderConfiguration `mapstructure:"reminder" validate:"required"`
 }
 
 type EventConfiguration struct {
-	ChannelBufferSize *int `mapstructure:"channel-buffer-size" validate:"required,min=1"`
-	MaxWorkers        *int `mapstructure:"max-workers" validate:"required"`
+	ChannelBufferSize *int                          `mapstructure:"channel-buffer-size" validate:"required,min=1"`
+	MaxWorkers        *int                          `mapstructure:"max-workers" validate:"required,min=1"`
+	HandlerTimeout    *string                       `mapstructure:"handler-timeout" validate:"required"`
+	Topics            map[string]TopicConfiguration `mapstructure:"topics" validate:"dive"`
+	Priorities        *PrioritiesConfiguration      `mapstructure:"priorities" validate:"required"`
+	CircuitBreaker    *CircuitBreakerConfiguration  `mapstructure:"circuit-breaker" validate:"required"`
+}
+
+type PrioritiesConfiguration struct {
+	StarvationThreshold *int              `mapstructure:"starvation-threshold" validate:"required,min=1"`
+	Events              map[string]string `mapstructure:"events" validate:"dive,oneof=high normal low"`
+}
+
+type TopicConfiguration struct {
+	MaxWorkers     *int    `mapstructure:"max-workers" validate:"required,min=1"`
+	Weight         *int    `mapstructure:"weight" validate:"required,min=1"`
+	HandlerTimeout *string `mapstructure:"handler-timeout"`
+}
+
+type CategoryConfiguration struct {
+	DeletionPolicy *string `mapstructure:"deletion-policy" validate:"required,oneof=restrict cascade-unlink reassign"`
+	// DefaultCategoryID is the category receiving the ToDos of deleted categories under the reassign policy
+	DefaultCategoryID *string `mapstructure:"default-category-id" validate:"omitempty,uuid"`
+}
+
+type SearchConfiguration struct {
+	// Backend is postgres to search the ToDo table itself, or memory to search an index kept by the application
+	Backend *string `mapstructure:"backend" validate:"required,oneof=postgres memory"`
+}
+
+type ReminderConfiguration struct {
+	// Enabled runs the scheduler sending due reminders, reminders can be managed either way
+	Enabled      *bool                           `mapstructure:"enabled" validate:"required"`
+	PollInterval *string                         `mapstructure:"poll-interval" validate:"required"`
+	BatchSize    *int                            `mapstructure:"batch-size" validate:"required,min=1"`
+	Notifiers    *ReminderNotifiersConfiguration `mapstructure:"notifiers" validate:"required"`
+}
+
+type ReminderNotifiersConfiguration struct {
+	Log     *LogNotifierConfiguration     `mapstructure:"log" validate:"required"`
+	Webhook *WebhookNotifierConfiguration `mapstructure:"webhook" validate:"required"`
+	SMTP    *SMTPNotifierConfiguration    `mapstructure:"smtp" validate:"required"`
+}
+
+type LogNotifierConfiguration struct {
+	Enabled *bool `mapstructure:"enabled" validate:"required"`
+}
+
+type WebhookNotifierConfiguration struct {
+	Enabled *bool   `mapstructure:"enabled" validate:"required"`
+	URL     *string `mapstructure:"url" validate:"omitempty,url"`
+	Timeout *string `mapstructure:"timeout" validate:"required"`
+}
+
+type SMTPNotifierConfiguration struct {
+	Enabled *bool   `mapstructure:"enabled" validate:"required"`
+	Host    *string `mapstructure:"host" validate:"required"`
+	Port    *int    `mapstructure:"port" validate:"required,min=1,max=65535"`
+	// Username enables authentication, which the local mail server used while developing does not need
+	Username *string  `mapstructure:"username"`
+	Password *string  `mapstructure:"password"`
+	From     *string  `mapstructure:"from" validate:"required,email"`
+	To       []string `mapstructure:"to" validate:"dive,email"`
+	Timeout  *string  `mapstructure:"timeout" validate:"required"`
 }
 
 type GinConfiguration struct {
-	Environment *string `mapstructure:"environment" validate:"required,oneof=dev qa stg ocu prod"`
-	Port        *int    `mapstructure:"port" validate:"required"`
+	Environment *string                 `mapstructure:"environment" validate:"required,oneof=dev qa stg ocu prod"`
+	Port        *int                    `mapstructure:"port" validate:"required"`
+	AccessLog   *AccessLogConfiguration `mapstructure:"access-log" validate:"required"`
+}
+
+type AccessLogConfiguration struct {
+	SampleRate    *float64 `mapstructure:"sample-rate" validate:"required,min=0,max=1"`
+	ExcludedPaths []string `mapstructure:"excluded-paths"`
 }
 
 type RdbmsConfiguration struct {
-	Driver   *string                 `mapstructure:"driver" validate:"required"`
-	Host     *string                 `mapstructure:"host" validate:"required"`
-	Port     *string                 `mapstructure:"port" validate:"required"`
-	Database *string                 `mapstructure:"database" validate:"required"`
-	User     *string                 `mapstructure:"user" validate:"required"`
-	Password *string                 `mapstructure:"password" validate:"required"`
-	Pool     *RdbmsPoolConfiguration `mapstructure:"pool" validate:"required"`
+	Driver          *string                         `mapstructure:"driver" validate:"required"`
+	Host            *string                         `mapstructure:"host" validate:"required"`
+	Port            *string                         `mapstructure:"port" validate:"required"`
+	Database        *string                         `mapstructure:"database" validate:"required"`
+	User            *string                         `mapstructure:"user" validate:"required"`
+	Password        *string                         `mapstructure:"password" validate:"required"`
+	SSLMode         *string                         `mapstructure:"sslmode" validate:"required,oneof=disable allow prefer require verify-ca verify-full"`
+	ConnectTimeout  *string                         `mapstructure:"connect-timeout" validate:"required"`
+	ApplicationName *string                         `mapstructure:"application-name" validate:"required"`
+	Pool            *RdbmsPoolConfiguration         `mapstructure:"pool" validate:"required"`
+	StartupProbe    *RdbmsStartupProbeConfiguration `mapstructure:"startup-probe" validate:"required"`
+	Migrations      *RdbmsMigrationsConfiguration   `mapstructure:"migrations" validate:"required"`
+	// CircuitBreaker protects the opening of database transactions, its mode is ignored
+	CircuitBreaker *CircuitBreakerConfiguration `mapstructure:"circuit-breaker" validate:"required"`
+}
+
+type RdbmsStartupProbeConfiguration struct {
+	Attempts          *int     `mapstructure:"attempts" validate:"required,min=1"`
+	InitialBackoff    *string  `mapstructure:"initial-backoff" validate:"required"`
+	MaxBackoff        *string  `mapstructure:"max-backoff" validate:"required"`
+	BackoffMultiplier *float64 `mapstructure:"backoff-multiplier" validate:"required,min=1"`
+	Deadline          *string  `mapstructure:"deadline" validate:"required"`
+}
+
+type RdbmsMigrationsConfiguration struct {
+	RunOnStartup *bool `mapstructure:"run-on-startup" validate:"required"`
 }
 
 type RdbmsPoolConfiguration struct {
@@ -31,3 +124,31 @@ type RdbmsPoolConfiguration struct {
 	MaxOpenConnections    *int    `mapstructure:"max-open-connections" validate:"required"`
 	MaxConnectionLifetime *string `mapstructure:"max-connection-lifetime" validate:"required"`
 }
+
+type CircuitBreakerConfiguration struct {
+	Enabled          *bool   `mapstructure:"enabled" validate:"required"`
+	FailureThreshold *int    `mapstructure:"failure-threshold" validate:"required,min=1"`
+	OpenTimeout      *string `mapstructure:"open-timeout" validate:"required"`
+	HalfOpenProbes   *int    `mapstructure:"half-open-probes" validate:"required,min=1"`
+	Mode             *string `mapstructure:"mode" validate:"omitempty,oneof=hold fail-fast"`
+}
+
+type MetricsConfiguration struct {
+	Enabled *bool   `mapstructure:"enabled" validate:"required"`
+	Path    *string `mapstructure:"path" validate:"required,startswith=/"`
+}
+
+type TracingConfiguration struct {
+	Enabled     *bool    `mapstructure:"enabled" validate:"required"`
+	ServiceName *string  `mapstructure:"service-name" validate:"required"`
+	Exporter    *string  `mapstructure:"exporter" validate:"required,oneof=otlp stdout file"`
+	Endpoint    *string  `mapstructure:"endpoint" validate:"required_if=Exporter otlp"`
+	Insecure    *bool    `mapstructure:"insecure"`
+	FilePath    *string  `mapstructure:"file-path" validate:"required_if=Exporter file"`
+	SampleRatio *float64 `mapstructure:"sample-ratio" validate:"required,min=0,max=1"`
+}
+
+type HealthConfiguration struct {
+	Timeout                  *string  `mapstructure:"timeout" validate:"required"`
+	QueueSaturationThreshold *float64 `mapstructure:"queue-saturation-threshold" validate:"required,gt=0,max=1"`
+}
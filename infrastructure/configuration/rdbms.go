@@ -1,22 +1,36 @@
 package configuration
 
 import (
+	"context"
 	"database/sql"
 	infrastructure "event-bus-demo/infrastructure/error"
 	"fmt"
 	_ "github.com/lib/pq"
+	"go.uber.org/zap"
+	"math"
+	"net"
+	"net/url"
+	"strconv"
 	"time"
 )
 
 func BuildDatabase(configuration RdbmsConfiguration) (*sql.DB, infrastructure.InfrastructureError) {
-	connectionUrl := fmt.Sprintf("%s://%s:%s@%s:%s/%s?sslmode=disable",
-		*configuration.Driver,
-		*configuration.User,
-		*configuration.Password,
-		*configuration.Host,
-		*configuration.Port,
-		*configuration.Database)
-	db, err := sql.Open(*configuration.Driver, connectionUrl)
+	connectTimeout, err := time.ParseDuration(*configuration.ConnectTimeout)
+	if err != nil {
+		return nil, infrastructure.NewParseFileError(err.Error())
+	}
+	parameters := url.Values{}
+	parameters.Set("sslmode", *configuration.SSLMode)
+	parameters.Set("connect_timeout", strconv.Itoa(int(math.Ceil(connectTimeout.Seconds()))))
+	parameters.Set("application_name", *configuration.ApplicationName)
+	connectionUrl := url.URL{
+		Scheme:   *configuration.Driver,
+		User:     url.UserPassword(*configuration.User, *configuration.Password),
+		Host:     net.JoinHostPort(*configuration.Host, *configuration.Port),
+		Path:     *configuration.Database,
+		RawQuery: parameters.Encode(),
+	}
+	db, err := sql.Open(*configuration.Driver, connectionUrl.String())
 	if err != nil {
 		return nil, infrastructure.NewSQLError(err.Error())
 	}
@@ -29,3 +43,41 @@ func BuildDatabase(configuration RdbmsConfiguration) (*sql.DB, infrastructure.In
 	db.SetConnMaxLifetime(maxConnectionLifetime)
 	return db, nil
 }
+
+// WaitForDatabase pings the database until it answers, so the application does not start serving requests before its
+// database is reachable. Attempts are spaced with an exponential backoff and the wait is given up once every attempt
+// failed or the deadline is reached.
+func WaitForDatabase(db *sql.DB, configuration RdbmsStartupProbeConfiguration, logger *zap.Logger) infrastructure.InfrastructureError {
+	backoff, err := time.ParseDuration(*configuration.InitialBackoff)
+	if err != nil {
+		return infrastructure.NewParseFileError(err.Error())
+	}
+	maxBackoff, err := time.ParseDuration(*configuration.MaxBackoff)
+	if err != nil {
+		return infrastructure.NewParseFileError(err.Error())
+	}
+	deadline, err := time.ParseDuration(*configuration.Deadline)
+	if err != nil {
+		return infrastructure.NewParseFileError(err.Error())
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), deadline)
+	defer cancel()
+	for attempt := 1; ; attempt++ {
+		err = db.PingContext(ctx)
+		if err == nil {
+			logger.Info("database is reachable", zap.Int("attempt", attempt))
+			return nil
+		} else if attempt >= *configuration.Attempts {
+			return infrastructure.NewSQLError(fmt.Sprintf("database not reachable after %d attempts: %s", attempt, err.Error()))
+		}
+		logger.Warn("database not reachable yet, retrying", zap.Int("attempt", attempt),
+			zap.Duration("backoff", backoff), zap.String("error", err.Error()))
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return infrastructure.NewSQLError(fmt.Sprintf("database not reachable within %s after %d attempts: %s",
+				deadline, attempt, err.Error()))
+		}
+		backoff = time.Duration(math.Min(float64(backoff)**configuration.BackoffMultiplier, float64(maxBackoff)))
+	}
+}
@@ -8,10 +8,12 @@ const (
 	ResourceRootFlag   = "resource-root"
 	ConfigPrefixFlag   = "config-prefix"
 	ActiveProfilesFlag = "active-profiles"
+	EventGraphFlag     = "event-graph"
 )
 
 const (
 	ResourceRootDefaultValue   = os.PathSeparator
 	ConfigPrefixDefaultValue   = "application"
 	ActiveProfilesDefaultValue = "default"
+	EventGraphDefaultValue     = ""
 )
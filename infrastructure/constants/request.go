@@ -0,0 +1,6 @@
+package constants
+
+const (
+	RequestIDHeader      = "X-Request-ID"
+	RequestIDMetadataKey = "request-id"
+)
@@ -0,0 +1,7 @@
+package constants
+
+const (
+	OTLPTracingExporter   = "otlp"
+	StdoutTracingExporter = "stdout"
+	FileTracingExporter   = "file"
+)
@@ -0,0 +1,129 @@
+// Package databasetest provides a scripted database/sql driver, so tests run statements without a database server.
+package databasetest
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// Database is a connector whose connections hand every statement to Exec or Query and record it. The functions are
+// called one at a time, so they can change the state of the test without locking it. A nil function fails the
+// statements it would run. Transactions are accepted, committing and rolling back nothing.
+type Database struct {
+	Exec       func(query string, args []driver.NamedValue) (driver.Result, error)
+	Query      func(query string, args []driver.NamedValue) (*Rows, error)
+	mutex      sync.Mutex
+	statements []string
+}
+
+// Open returns a pool of connections to the database, to be closed by the test.
+func (database *Database) Open() *sql.DB {
+	return sql.OpenDB(database)
+}
+
+// Statements returns the statements run so far, in order.
+func (database *Database) Statements() []string {
+	database.mutex.Lock()
+	defer database.mutex.Unlock()
+	return append([]string(nil), database.statements...)
+}
+
+func (database *Database) Connect(context.Context) (driver.Conn, error) {
+	return &conn{database: database}, nil
+}
+
+func (database *Database) Driver() driver.Driver {
+	return nil
+}
+
+func (database *Database) exec(query string, args []driver.NamedValue) (driver.Result, error) {
+	database.mutex.Lock()
+	defer database.mutex.Unlock()
+	database.statements = append(database.statements, query)
+	if database.Exec == nil {
+		return nil, fmt.Errorf("unexpected statement %s", query)
+	}
+	return database.Exec(query, args)
+}
+
+func (database *Database) query(query string, args []driver.NamedValue) (driver.Rows, error) {
+	database.mutex.Lock()
+	defer database.mutex.Unlock()
+	database.statements = append(database.statements, query)
+	if database.Query == nil {
+		return nil, fmt.Errorf("unexpected query %s", query)
+	}
+	rows, err := database.Query(query, args)
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+type conn struct {
+	database *Database
+}
+
+func (conn *conn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("prepared statements are not supported")
+}
+
+func (conn *conn) Close() error {
+	return nil
+}
+
+func (conn *conn) Begin() (driver.Tx, error) {
+	return conn, nil
+}
+
+func (conn *conn) Commit() error {
+	return nil
+}
+
+func (conn *conn) Rollback() error {
+	return nil
+}
+
+func (conn *conn) ExecContext(_ context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	return conn.database.exec(query, args)
+}
+
+func (conn *conn) QueryContext(_ context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	return conn.database.query(query, args)
+}
+
+// Rows is the result of a query. Without column names, the columns are named after their position in the first row,
+// as database/sql only needs their number to scan the rows.
+type Rows struct {
+	ColumnNames []string
+	Values      [][]driver.Value
+}
+
+func (rows *Rows) Columns() []string {
+	if rows.ColumnNames != nil || len(rows.Values) == 0 {
+		return rows.ColumnNames
+	}
+	columns := make([]string, len(rows.Values[0]))
+	for i := range columns {
+		columns[i] = fmt.Sprintf("column%d", i)
+	}
+	return columns
+}
+
+func (rows *Rows) Close() error {
+	return nil
+}
+
+func (rows *Rows) Next(dest []driver.Value) error {
+	if len(rows.Values) == 0 {
+		return io.EOF
+	}
+	copy(dest, rows.Values[0])
+	rows.Values = rows.Values[1:]
+	return nil
+}
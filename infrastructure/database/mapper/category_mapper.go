@@ -4,6 +4,7 @@ import (
 	domainModel "event-bus-demo/domain/model"
 	dbModel "event-bus-demo/infrastructure/database/model"
 	"event-bus-demo/infrastructure/database/sqlc"
+	"github.com/google/uuid"
 )
 
 func NewCategoryEntityFromCategoryModel(model domainModel.Category) dbModel.CategoryEntity {
@@ -23,8 +24,9 @@ func NewCategoryEntityListFromCategoryListModel(model []domainModel.Category) []
 
 func NewCategoryEntityFromSQLModel(sqlModel sqlc.Category) dbModel.CategoryEntity {
 	return dbModel.CategoryEntity{
-		ID:   sqlModel.ID,
-		Name: sqlModel.Name,
+		ID:      sqlModel.ID,
+		Name:    sqlModel.Name,
+		Version: sqlModel.Version,
 	}
 }
 
@@ -37,10 +39,23 @@ func NewCategoryEntityListFromSQLModelList(sqlModelList []sqlc.Category) []dbMod
 	return entities
 }
 
+// NewCategoryEntitiesByToDoIDFromSQLModelList groups the categories of several ToDos by the ID of their ToDo.
+func NewCategoryEntitiesByToDoIDFromSQLModelList(sqlModelList []sqlc.GetToDoListCategoriesRow) map[uuid.UUID][]dbModel.CategoryEntity {
+	entities := make(map[uuid.UUID][]dbModel.CategoryEntity)
+	for _, sqlModel := range sqlModelList {
+		entities[sqlModel.TodoID] = append(entities[sqlModel.TodoID], dbModel.CategoryEntity{
+			ID:   sqlModel.ID,
+			Name: sqlModel.Name,
+		})
+	}
+	return entities
+}
+
 func NewCategoryFromEntity(entity dbModel.CategoryEntity) domainModel.Category {
 	return domainModel.Category{
-		ID:   entity.ID,
-		Name: entity.Name,
+		ID:      entity.ID,
+		Name:    entity.Name,
+		Version: entity.Version,
 	}
 }
 
@@ -0,0 +1,102 @@
+package mapper
+
+import (
+	"database/sql"
+	domainModel "event-bus-demo/domain/model"
+	dbModel "event-bus-demo/infrastructure/database/model"
+	"event-bus-demo/infrastructure/database/sqlc"
+	"github.com/google/uuid"
+	"time"
+)
+
+func NewToDoItemEntityFromSQLModel(sqlModel sqlc.TodoItem) dbModel.ToDoItemEntity {
+	createdAt := sqlModel.CreatedAt.Local()
+	return dbModel.ToDoItemEntity{
+		ID:        sqlModel.ID,
+		ToDoID:    sqlModel.TodoID,
+		Title:     sqlModel.Title,
+		Done:      sqlModel.Done,
+		Position:  int(sqlModel.Position),
+		CreatedAt: &createdAt,
+		UpdatedAt: newTimePointer(sqlModel.UpdatedAt),
+	}
+}
+
+func NewToDoItemEntityListFromSQLModelList(sqlModelList []sqlc.TodoItem) []dbModel.ToDoItemEntity {
+	entities := make([]dbModel.ToDoItemEntity, 0)
+	for _, sqlModel := range sqlModelList {
+		entities = append(entities, NewToDoItemEntityFromSQLModel(sqlModel))
+	}
+	return entities
+}
+
+// NewToDoItemEntitiesByToDoIDFromSQLModelList groups the checklist items of several ToDos by the ID of their ToDo,
+// keeping the order of each checklist.
+func NewToDoItemEntitiesByToDoIDFromSQLModelList(sqlModelList []sqlc.TodoItem) map[uuid.UUID][]dbModel.ToDoItemEntity {
+	entities := make(map[uuid.UUID][]dbModel.ToDoItemEntity)
+	for _, sqlModel := range sqlModelList {
+		entities[sqlModel.TodoID] = append(entities[sqlModel.TodoID], NewToDoItemEntityFromSQLModel(sqlModel))
+	}
+	return entities
+}
+
+func NewToDoItemEntityFromEvent(event domainModel.CreateToDoItemEvent) dbModel.ToDoItemEntity {
+	return dbModel.ToDoItemEntity{
+		ID:        event.ID,
+		ToDoID:    event.ToDoID,
+		Title:     event.Title,
+		CreatedAt: &event.CreatedAt,
+	}
+}
+
+func NewCreateToDoItemParamsFromEntity(item dbModel.ToDoItemEntity) sqlc.CreateToDoItemParams {
+	return sqlc.CreateToDoItemParams{
+		ID:        item.ID,
+		TodoID:    item.ToDoID,
+		Title:     item.Title,
+		Position:  int32(item.Position),
+		CreatedAt: *item.CreatedAt,
+	}
+}
+
+func NewToDoItemUpdateEntityFromEvent(event domainModel.UpdateToDoItemEvent) dbModel.ToDoItemUpdateEntity {
+	return dbModel.ToDoItemUpdateEntity{
+		ID:     event.ID,
+		ToDoID: event.ToDoID,
+		Title:  event.Title,
+		Done:   event.Done,
+	}
+}
+
+func NewUpdateToDoItemParamsFromEntity(update dbModel.ToDoItemUpdateEntity) sqlc.UpdateToDoItemParams {
+	params := sqlc.UpdateToDoItemParams{
+		Title:     NewNullString(update.Title),
+		UpdatedAt: sql.NullTime{Time: time.Now(), Valid: true},
+		ID:        update.ID,
+		TodoID:    update.ToDoID,
+	}
+	if update.Done != nil {
+		params.Done = sql.NullBool{Bool: *update.Done, Valid: true}
+	}
+	return params
+}
+
+func NewToDoItemFromEntity(entity dbModel.ToDoItemEntity) domainModel.ToDoItem {
+	return domainModel.ToDoItem{
+		ID:        entity.ID,
+		ToDoID:    entity.ToDoID,
+		Title:     entity.Title,
+		Done:      entity.Done,
+		Position:  entity.Position,
+		CreatedAt: entity.CreatedAt,
+		UpdatedAt: entity.UpdatedAt,
+	}
+}
+
+func NewToDoItemListFromEntityList(entities []dbModel.ToDoItemEntity) []domainModel.ToDoItem {
+	models := make([]domainModel.ToDoItem, 0)
+	for _, entity := range entities {
+		models = append(models, NewToDoItemFromEntity(entity))
+	}
+	return models
+}
@@ -1,9 +1,12 @@
 package mapper
 
 import (
+	"database/sql"
 	domainModel "event-bus-demo/domain/model"
 	dbModel "event-bus-demo/infrastructure/database/model"
 	"event-bus-demo/infrastructure/database/sqlc"
+	"github.com/google/uuid"
+	"strings"
 	"time"
 )
 
@@ -14,6 +17,13 @@ func NewToDoEntityFromToDoModel(model domainModel.ToDo) dbModel.ToDoEntity {
 		Description: model.Description,
 		CreatedAt:   model.CreatedAt,
 		UpdatedAt:   model.UpdatedAt,
+		Status:      string(model.Status),
+		Priority:    string(model.Priority),
+		DueAt:       model.DueAt,
+		CompletedAt: model.CompletedAt,
+		Recurrence:  newRecurrenceString(model.Recurrence),
+		SeriesID:    model.SeriesID,
+		Occurrence:  model.Occurrence,
 		Categories:  NewCategoryEntityListFromCategoryListModel(model.Categories),
 	}
 }
@@ -31,18 +41,278 @@ func NewToDoEntityFromSQLModel(sqlModel sqlc.Todo) dbModel.ToDoEntity {
 		Description: sqlModel.Description,
 		CreatedAt:   &createdAt,
 		UpdatedAt:   updatedAt,
+		Status:      sqlModel.Status,
+		Priority:    sqlModel.Priority,
+		DueAt:       newTimePointer(sqlModel.DueAt),
+		CompletedAt: newTimePointer(sqlModel.CompletedAt),
+		Recurrence:  newStringPointer(sqlModel.Recurrence),
+		SeriesID:    newUUIDPointer(sqlModel.SeriesID),
+		Occurrence:  int(sqlModel.Occurrence),
+		Version:     sqlModel.Version,
 	}
 }
 
-func NewToDoEntityListFromSQLModelList(sqlModelList []sqlc.Todo) []dbModel.ToDoEntity {
-	entities := make([]dbModel.ToDoEntity, 0)
+// NewToDoUpdateEntityFromEvent keeps the fields changed by the event, so the others are left as stored.
+func NewToDoUpdateEntityFromEvent(event domainModel.UpdateToDoEvent) dbModel.ToDoUpdateEntity {
+	update := dbModel.ToDoUpdateEntity{
+		ID:              event.ID,
+		ExpectedVersion: event.ExpectedVersion,
+	}
+	for _, field := range event.Fields {
+		switch field {
+		case domainModel.ToDoTitleField:
+			update.Title = &event.Title
+		case domainModel.ToDoDescriptionField:
+			update.Description = &event.Description
+		case domainModel.ToDoPriorityField:
+			priority := string(event.Priority)
+			update.Priority = &priority
+		case domainModel.ToDoRecurrenceField:
+			update.SetRecurrence = true
+			update.Recurrence = newRecurrenceString(event.Recurrence)
+		}
+	}
+	return update
+}
+
+func NewUpdateToDoInformationParamsFromEntity(update dbModel.ToDoUpdateEntity) sqlc.UpdateToDoInformationParams {
+	return sqlc.UpdateToDoInformationParams{
+		Title:           NewNullString(update.Title),
+		Description:     NewNullString(update.Description),
+		Priority:        NewNullString(update.Priority),
+		SetRecurrence:   update.SetRecurrence,
+		Recurrence:      NewNullString(update.Recurrence),
+		UpdatedAt:       sql.NullTime{Time: time.Now(), Valid: true},
+		ID:              update.ID,
+		ExpectedVersion: NewExpectedVersionParam(update.ExpectedVersion),
+	}
+}
+
+func NewToDoStatusChangeEntityFromModel(change domainModel.ToDoStatusChange) dbModel.ToDoStatusChangeEntity {
+	return dbModel.ToDoStatusChangeEntity{
+		ID:              change.ID,
+		Status:          string(change.Status),
+		CompletedAt:     change.CompletedAt,
+		FromStatuses:    newStatusList(domainModel.ToDoStatusesChangingTo(change.Status)),
+		ExpectedVersion: change.ExpectedVersion,
+	}
+}
+
+func NewChangeToDoStatusParamsFromEntity(change dbModel.ToDoStatusChangeEntity) sqlc.ChangeToDoStatusParams {
+	return sqlc.ChangeToDoStatusParams{
+		Status:          change.Status,
+		CompletedAt:     NewNullTime(change.CompletedAt),
+		UpdatedAt:       sql.NullTime{Time: time.Now(), Valid: true},
+		ID:              change.ID,
+		FromStatuses:    change.FromStatuses,
+		ExpectedVersion: NewExpectedVersionParam(change.ExpectedVersion),
+	}
+}
+
+func NewToDoRescheduleEntityFromEvent(event domainModel.RescheduleToDoEvent) dbModel.ToDoRescheduleEntity {
+	return dbModel.ToDoRescheduleEntity{
+		ID:              event.ID,
+		DueAt:           event.DueAt,
+		FromStatuses:    newStatusList(domainModel.UnfinishedToDoStatuses),
+		ExpectedVersion: event.ExpectedVersion,
+	}
+}
+
+func NewRescheduleToDoParamsFromEntity(reschedule dbModel.ToDoRescheduleEntity) sqlc.RescheduleToDoParams {
+	return sqlc.RescheduleToDoParams{
+		DueAt:           NewNullTime(reschedule.DueAt),
+		UpdatedAt:       sql.NullTime{Time: time.Now(), Valid: true},
+		ID:              reschedule.ID,
+		FromStatuses:    reschedule.FromStatuses,
+		ExpectedVersion: NewExpectedVersionParam(reschedule.ExpectedVersion),
+	}
+}
+
+func newStatusList(statuses []domainModel.ToDoStatus) []string {
+	list := make([]string, 0, len(statuses))
+	for _, status := range statuses {
+		list = append(list, string(status))
+	}
+	return list
+}
+
+// titleFilterEscaper escapes the LIKE wildcards of title filters, so they match as plain substrings.
+var titleFilterEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+func NewToDoSearchEntityFromEvent(event domainModel.GetToDoListEvent) dbModel.ToDoSearchEntity {
+	search := dbModel.ToDoSearchEntity{
+		CategoryID:  event.Filter.CategoryID,
+		CreatedFrom: event.Filter.CreatedFrom,
+		CreatedTo:   event.Filter.CreatedTo,
+		UpdatedFrom: event.Filter.UpdatedFrom,
+		UpdatedTo:   event.Filter.UpdatedTo,
+		SortField:   string(event.SortField),
+		Descending:  event.Descending,
+		Limit:       event.Page.Limit,
+		Offset:      event.Page.Offset,
+	}
+	if event.Filter.Title != "" {
+		search.Title = &event.Filter.Title
+	}
+	if event.Filter.Status != nil {
+		status := string(*event.Filter.Status)
+		search.Status = &status
+	}
+	if event.Filter.Priority != nil {
+		priority := string(*event.Filter.Priority)
+		search.Priority = &priority
+	}
+	now := time.Now()
+	if event.Filter.Overdue {
+		search.DueTo = &now
+		search.Unfinished = true
+	}
+	if event.Filter.DueWithin != nil {
+		dueTo := now.Add(*event.Filter.DueWithin)
+		search.DueFrom, search.DueTo = &now, &dueTo
+		search.Unfinished = true
+	}
+	return search
+}
+
+func NewSearchToDoListParamsFromEntity(search dbModel.ToDoSearchEntity) sqlc.SearchToDoListParams {
+	params := sqlc.SearchToDoListParams{
+		SortField:   search.SortField,
+		CreatedFrom: NewNullTime(search.CreatedFrom),
+		CreatedTo:   NewNullTime(search.CreatedTo),
+		UpdatedFrom: NewNullTime(search.UpdatedFrom),
+		UpdatedTo:   NewNullTime(search.UpdatedTo),
+		Status:      NewNullString(search.Status),
+		Priority:    NewNullString(search.Priority),
+		DueFrom:     NewNullTime(search.DueFrom),
+		DueTo:       NewNullTime(search.DueTo),
+		Unfinished:  search.Unfinished,
+		Descending:  search.Descending,
+		PageLimit:   int32(search.Limit),
+		PageOffset:  int32(search.Offset),
+	}
+	if search.CategoryID != nil {
+		params.CategoryID = uuid.NullUUID{UUID: *search.CategoryID, Valid: true}
+	}
+	if search.Title != nil {
+		params.Title = sql.NullString{String: titleFilterEscaper.Replace(*search.Title), Valid: true}
+	}
+	if search.After != nil {
+		params.CursorID = uuid.NullUUID{UUID: search.After.ID, Valid: true}
+		params.CursorKey = sql.NullString{String: search.After.SortKey, Valid: true}
+	}
+	return params
+}
+
+func NewToDoSearchResultEntityListFromSQLModelList(sqlModelList []sqlc.SearchToDoListRow) []dbModel.ToDoSearchResultEntity {
+	entities := make([]dbModel.ToDoSearchResultEntity, 0)
 	for _, sqlModel := range sqlModelList {
-		entity := NewToDoEntityFromSQLModel(sqlModel)
-		entities = append(entities, entity)
+		entities = append(entities, dbModel.ToDoSearchResultEntity{
+			ToDo: NewToDoEntityFromSQLModel(sqlc.Todo{
+				ID:          sqlModel.ID,
+				Title:       sqlModel.Title,
+				Description: sqlModel.Description,
+				CreatedAt:   sqlModel.CreatedAt,
+				UpdatedAt:   sqlModel.UpdatedAt,
+				Version:     sqlModel.Version,
+				Status:      sqlModel.Status,
+				Priority:    sqlModel.Priority,
+				DueAt:       sqlModel.DueAt,
+				CompletedAt: sqlModel.CompletedAt,
+				Recurrence:  sqlModel.Recurrence,
+				SeriesID:    sqlModel.SeriesID,
+				Occurrence:  sqlModel.Occurrence,
+			}),
+			SortKey: sqlModel.SortKey,
+		})
 	}
 	return entities
 }
 
+func NewToDoTextSearchResultEntityListFromSQLModelList(sqlModelList []sqlc.SearchToDoTextRow) []dbModel.ToDoTextSearchResultEntity {
+	entities := make([]dbModel.ToDoTextSearchResultEntity, 0)
+	for _, sqlModel := range sqlModelList {
+		entities = append(entities, dbModel.ToDoTextSearchResultEntity{
+			ToDo: NewToDoEntityFromSQLModel(sqlc.Todo{
+				ID:          sqlModel.ID,
+				Title:       sqlModel.Title,
+				Description: sqlModel.Description,
+				CreatedAt:   sqlModel.CreatedAt,
+				UpdatedAt:   sqlModel.UpdatedAt,
+				Version:     sqlModel.Version,
+				Status:      sqlModel.Status,
+				Priority:    sqlModel.Priority,
+				DueAt:       sqlModel.DueAt,
+				CompletedAt: sqlModel.CompletedAt,
+				Recurrence:  sqlModel.Recurrence,
+				SeriesID:    sqlModel.SeriesID,
+				Occurrence:  sqlModel.Occurrence,
+			}),
+			Rank:           float64(sqlModel.Rank),
+			TitleHighlight: sqlModel.TitleHighlight,
+			Snippet:        sqlModel.Snippet,
+		})
+	}
+	return entities
+}
+
+func NewToDoSearchHitListFromEntityList(entities []dbModel.ToDoTextSearchResultEntity) []domainModel.ToDoSearchHit {
+	hits := make([]domainModel.ToDoSearchHit, 0)
+	for _, entity := range entities {
+		hits = append(hits, domainModel.ToDoSearchHit{
+			ToDo:           NewToDoFromEntity(entity.ToDo),
+			Rank:           entity.Rank,
+			TitleHighlight: entity.TitleHighlight,
+			Snippet:        entity.Snippet,
+		})
+	}
+	return hits
+}
+
+func NewNullString(value *string) sql.NullString {
+	if value == nil {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: *value, Valid: true}
+}
+
+func newStringPointer(value sql.NullString) *string {
+	if !value.Valid {
+		return nil
+	}
+	return &value.String
+}
+
+func newUUIDPointer(value uuid.NullUUID) *uuid.UUID {
+	if !value.Valid {
+		return nil
+	}
+	return &value.UUID
+}
+
+func newTimePointer(value sql.NullTime) *time.Time {
+	if !value.Valid {
+		return nil
+	}
+	return &value.Time
+}
+
+func NewNullTime(value *time.Time) sql.NullTime {
+	if value == nil {
+		return sql.NullTime{}
+	}
+	return sql.NullTime{Time: *value, Valid: true}
+}
+
+// NewExpectedVersionParam returns the version a conditional statement expects, null when the statement must apply
+// whatever the current version.
+func NewExpectedVersionParam(expectedVersion *int64) sql.NullInt64 {
+	if expectedVersion == nil {
+		return sql.NullInt64{}
+	}
+	return sql.NullInt64{Int64: *expectedVersion, Valid: true}
+}
+
 func NewToDoFromEntity(entity dbModel.ToDoEntity) domainModel.ToDo {
 	return domainModel.ToDo{
 		ID:          entity.ID,
@@ -50,8 +320,38 @@ func NewToDoFromEntity(entity dbModel.ToDoEntity) domainModel.ToDo {
 		Description: entity.Description,
 		CreatedAt:   entity.CreatedAt,
 		UpdatedAt:   entity.UpdatedAt,
+		Status:      domainModel.ToDoStatus(entity.Status),
+		Priority:    domainModel.ToDoPriority(entity.Priority),
+		DueAt:       entity.DueAt,
+		CompletedAt: entity.CompletedAt,
+		Recurrence:  newRecurrenceRule(entity.Recurrence),
+		SeriesID:    entity.SeriesID,
+		Occurrence:  entity.Occurrence,
+		Version:     entity.Version,
 		Categories:  NewCategoriesListFromEntity(entity.Categories),
+		Items:       NewToDoItemListFromEntityList(entity.Items),
+	}
+}
+
+func newRecurrenceString(rule *domainModel.RecurrenceRule) *string {
+	if rule == nil {
+		return nil
 	}
+	value := rule.String()
+	return &value
+}
+
+// newRecurrenceRule reads a stored recurrence rule. Rules are stored as written by newRecurrenceString, so a rule which
+// cannot be read anymore is left out rather than failing the whole ToDo.
+func newRecurrenceRule(value *string) *domainModel.RecurrenceRule {
+	if value == nil {
+		return nil
+	}
+	rule, err := domainModel.ParseRecurrenceRule(*value)
+	if err != nil {
+		return nil
+	}
+	return &rule
 }
 
 func NewToDoListFromEntityList(entities []dbModel.ToDoEntity) []domainModel.ToDo {
@@ -61,3 +361,10 @@ func NewToDoListFromEntityList(entities []dbModel.ToDoEntity) []domainModel.ToDo
 	}
 	return models
 }
+
+func NewNullUUID(value *uuid.UUID) uuid.NullUUID {
+	if value == nil {
+		return uuid.NullUUID{}
+	}
+	return uuid.NullUUID{UUID: *value, Valid: true}
+}
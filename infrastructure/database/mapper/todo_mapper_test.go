@@ -0,0 +1,83 @@
+package mapper
+
+import (
+	domainModel "event-bus-demo/domain/model"
+	dbModel "event-bus-demo/infrastructure/database/model"
+	"testing"
+	"time"
+)
+
+func TestNewSearchToDoListParamsFromEntityEscapesTitleWildcards(t *testing.T) {
+	tests := []struct {
+		title    string
+		expected string
+	}{
+		{title: "Buy milk", expected: "Buy milk"},
+		{title: "100%", expected: `100\%`},
+		{title: "snake_case", expected: `snake\_case`},
+		{title: `C:\temp`, expected: `C:\\temp`},
+		{title: `\%_`, expected: `\\\%\_`},
+	}
+	for _, test := range tests {
+		t.Run(test.title, func(t *testing.T) {
+			title := test.title
+			params := NewSearchToDoListParamsFromEntity(dbModel.ToDoSearchEntity{Title: &title})
+			if !params.Title.Valid || params.Title.String != test.expected {
+				t.Errorf("expected title filter %q, got %q", test.expected, params.Title.String)
+			}
+		})
+	}
+	if params := NewSearchToDoListParamsFromEntity(dbModel.ToDoSearchEntity{}); params.Title.Valid {
+		t.Errorf("expected no title filter, got %q", params.Title.String)
+	}
+}
+
+func TestNewToDoSearchEntityFromEventFiltersDueDates(t *testing.T) {
+	dueWithin := 48 * time.Hour
+	tests := []struct {
+		name             string
+		filter           domainModel.ToDoFilter
+		expectedDueFrom  *time.Duration
+		expectedDueTo    *time.Duration
+		expectUnfinished bool
+	}{
+		{name: "no filter"},
+		{name: "overdue", filter: domainModel.ToDoFilter{Overdue: true}, expectedDueTo: new(time.Duration),
+			expectUnfinished: true},
+		{name: "due within", filter: domainModel.ToDoFilter{DueWithin: &dueWithin},
+			expectedDueFrom: new(time.Duration), expectedDueTo: &dueWithin, expectUnfinished: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			before := time.Now()
+			search := NewToDoSearchEntityFromEvent(domainModel.GetToDoListEvent{Filter: test.filter})
+			after := time.Now()
+			checkDueBound(t, "from", search.DueFrom, test.expectedDueFrom, before, after)
+			checkDueBound(t, "to", search.DueTo, test.expectedDueTo, before, after)
+			if search.Unfinished != test.expectUnfinished {
+				t.Errorf("expected the unfinished filter to be %t, got %t", test.expectUnfinished, search.Unfinished)
+			}
+			params := NewSearchToDoListParamsFromEntity(search)
+			if params.Unfinished != test.expectUnfinished || params.DueFrom.Valid != (test.expectedDueFrom != nil) ||
+				params.DueTo.Valid != (test.expectedDueTo != nil) {
+				t.Errorf("expected the due date filters to be passed to the query, got %+v", params)
+			}
+		})
+	}
+}
+
+// checkDueBound checks that the bound is the given offset from the time the filter was mapped, or missing if nil.
+func checkDueBound(t *testing.T, name string, bound *time.Time, offset *time.Duration, before, after time.Time) {
+	t.Helper()
+	if offset == nil {
+		if bound != nil {
+			t.Errorf("expected no due %s bound, got %s", name, bound)
+		}
+		return
+	}
+	if bound == nil {
+		t.Fatalf("expected a due %s bound", name)
+	} else if bound.Before(before.Add(*offset)) || bound.After(after.Add(*offset)) {
+		t.Errorf("expected the due %s bound to be %s from now, got %s", name, *offset, bound)
+	}
+}
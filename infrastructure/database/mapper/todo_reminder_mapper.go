@@ -0,0 +1,93 @@
+package mapper
+
+import (
+	domainModel "event-bus-demo/domain/model"
+	dbModel "event-bus-demo/infrastructure/database/model"
+	"event-bus-demo/infrastructure/database/sqlc"
+	"time"
+)
+
+func NewToDoReminderEntityFromSQLModel(sqlModel sqlc.TodoReminder) dbModel.ToDoReminderEntity {
+	return dbModel.ToDoReminderEntity{
+		ID:        sqlModel.ID,
+		ToDoID:    sqlModel.TodoID,
+		BeforeDue: time.Duration(sqlModel.BeforeDueSeconds) * time.Second,
+		CreatedAt: sqlModel.CreatedAt,
+		SentDueAt: newTimePointer(sqlModel.SentDueAt),
+		SentAt:    newTimePointer(sqlModel.SentAt),
+	}
+}
+
+func NewToDoReminderEntityListFromSQLModelList(sqlModelList []sqlc.TodoReminder) []dbModel.ToDoReminderEntity {
+	entities := make([]dbModel.ToDoReminderEntity, 0)
+	for _, sqlModel := range sqlModelList {
+		entities = append(entities, NewToDoReminderEntityFromSQLModel(sqlModel))
+	}
+	return entities
+}
+
+func NewUpcomingToDoReminderEntityListFromSQLModelList(sqlModelList []sqlc.GetUpcomingToDoRemindersRow) []dbModel.UpcomingToDoReminderEntity {
+	entities := make([]dbModel.UpcomingToDoReminderEntity, 0)
+	for _, sqlModel := range sqlModelList {
+		entities = append(entities, dbModel.UpcomingToDoReminderEntity{
+			ID:       sqlModel.ID,
+			ToDoID:   sqlModel.TodoID,
+			Title:    sqlModel.Title,
+			DueAt:    sqlModel.DueAt,
+			RemindAt: sqlModel.RemindAt,
+		})
+	}
+	return entities
+}
+
+func NewToDoReminderEntityFromEvent(event domainModel.CreateToDoReminderEvent) dbModel.ToDoReminderEntity {
+	return dbModel.ToDoReminderEntity{
+		ID:        event.ID,
+		ToDoID:    event.ToDoID,
+		BeforeDue: event.BeforeDue,
+		CreatedAt: event.CreatedAt,
+	}
+}
+
+// NewCreateToDoReminderParamsFromEntity stores the delay of the reminder in whole seconds.
+func NewCreateToDoReminderParamsFromEntity(reminder dbModel.ToDoReminderEntity) sqlc.CreateToDoReminderParams {
+	return sqlc.CreateToDoReminderParams{
+		ID:               reminder.ID,
+		TodoID:           reminder.ToDoID,
+		BeforeDueSeconds: int64(reminder.BeforeDue / time.Second),
+		CreatedAt:        reminder.CreatedAt,
+	}
+}
+
+func NewToDoReminderFromEntity(entity dbModel.ToDoReminderEntity) domainModel.ToDoReminder {
+	return domainModel.ToDoReminder{
+		ID:        entity.ID,
+		ToDoID:    entity.ToDoID,
+		BeforeDue: entity.BeforeDue,
+		CreatedAt: entity.CreatedAt,
+		SentDueAt: entity.SentDueAt,
+		SentAt:    entity.SentAt,
+	}
+}
+
+func NewToDoReminderListFromEntityList(entities []dbModel.ToDoReminderEntity) []domainModel.ToDoReminder {
+	models := make([]domainModel.ToDoReminder, 0)
+	for _, entity := range entities {
+		models = append(models, NewToDoReminderFromEntity(entity))
+	}
+	return models
+}
+
+func NewUpcomingToDoReminderListFromEntityList(entities []dbModel.UpcomingToDoReminderEntity) []domainModel.UpcomingToDoReminder {
+	models := make([]domainModel.UpcomingToDoReminder, 0)
+	for _, entity := range entities {
+		models = append(models, domainModel.UpcomingToDoReminder{
+			ID:       entity.ID,
+			ToDoID:   entity.ToDoID,
+			Title:    entity.Title,
+			DueAt:    entity.DueAt,
+			RemindAt: entity.RemindAt,
+		})
+	}
+	return models
+}
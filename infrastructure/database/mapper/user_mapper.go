@@ -20,6 +20,7 @@ func NewUserEntityFromSQLModel(sqlModel sqlc.User) dbModel.UserEntity {
 		ID:       sqlModel.ID,
 		Username: sqlModel.Username,
 		Password: sqlModel.Password,
+		Role:     sqlModel.Role,
 	}
 }
 
@@ -27,6 +28,7 @@ func NewUserFromEntity(entity dbModel.UserEntity) domainModel.User {
 	user := domainModel.User{
 		ID:       entity.ID,
 		Username: entity.Username,
+		Role:     entity.Role,
 	}
 	user.SetHashedPassword(entity.Password)
 	return user
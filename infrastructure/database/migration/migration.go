@@ -0,0 +1,80 @@
+package migration
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	infrastructure "event-bus-demo/infrastructure/error"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+)
+
+// fileNamePattern matches migration files named <version>_<name>.<up|down>.sql, e.g. 0001_create_initial_schema.up.sql
+var fileNamePattern = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Checksum identifies the content of the up script, so changes made to a migration after it was applied are detected.
+func (migration Migration) Checksum() string {
+	sum := sha256.Sum256([]byte(migration.Up))
+	return hex.EncodeToString(sum[:])
+}
+
+// LoadMigrations reads the versioned migration files found at the root of fsys, sorted by version. Every migration
+// must have an up script, while its down script is optional.
+func LoadMigrations(fsys fs.FS) ([]Migration, infrastructure.InfrastructureError) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, infrastructure.NewParseFileError(err.Error())
+	}
+	migrations := make(map[int64]*Migration)
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		match := fileNamePattern.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, infrastructure.NewParseFileError(fmt.Sprintf("invalid migration file name %s", entry.Name()))
+		}
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil {
+			return nil, infrastructure.NewParseFileError(err.Error())
+		}
+		content, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, infrastructure.NewParseFileError(err.Error())
+		}
+		migration, ok := migrations[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: match[2]}
+			migrations[version] = migration
+		} else if migration.Name != match[2] {
+			return nil, infrastructure.NewParseFileError(fmt.Sprintf("migration version %d is used by %s and %s",
+				version, migration.Name, match[2]))
+		}
+		if match[3] == "up" {
+			migration.Up = string(content)
+		} else {
+			migration.Down = string(content)
+		}
+	}
+	result := make([]Migration, 0, len(migrations))
+	for _, migration := range migrations {
+		if migration.Up == "" {
+			return nil, infrastructure.NewParseFileError(fmt.Sprintf("migration %d_%s has no up script",
+				migration.Version, migration.Name))
+		}
+		result = append(result, *migration)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Version < result[j].Version
+	})
+	return result, nil
+}
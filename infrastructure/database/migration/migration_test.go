@@ -0,0 +1,120 @@
+package migration
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestLoadMigrationsSortsByVersion(t *testing.T) {
+	fsys := fstest.MapFS{
+		"0010_add_index.up.sql":             {Data: []byte("CREATE INDEX")},
+		"0002_create_table.down.sql":        {Data: []byte("DROP TABLE")},
+		"0002_create_table.up.sql":          {Data: []byte("CREATE TABLE")},
+		"0001_create_initial_schema.up.sql": {Data: []byte("CREATE SCHEMA")},
+		"nested/0003_ignored.up.sql":        {Data: []byte("ignored")},
+	}
+	migrations, err := LoadMigrations(fsys)
+	if err != nil {
+		t.Fatalf("unexpected error %s", err.Error())
+	}
+	expected := []Migration{
+		{Version: 1, Name: "create_initial_schema", Up: "CREATE SCHEMA"},
+		{Version: 2, Name: "create_table", Up: "CREATE TABLE", Down: "DROP TABLE"},
+		{Version: 10, Name: "add_index", Up: "CREATE INDEX"},
+	}
+	if !reflect.DeepEqual(migrations, expected) {
+		t.Errorf("expected %+v, got %+v", expected, migrations)
+	}
+}
+
+func TestLoadMigrationsRejectsInvalidFiles(t *testing.T) {
+	tests := []struct {
+		name     string
+		fsys     fstest.MapFS
+		expected string
+	}{
+		{
+			name:     "invalid name",
+			fsys:     fstest.MapFS{"create_table.sql": {Data: []byte("CREATE TABLE")}},
+			expected: "invalid migration file name create_table.sql",
+		},
+		{
+			name:     "unknown direction",
+			fsys:     fstest.MapFS{"0001_create_table.sideways.sql": {Data: []byte("CREATE TABLE")}},
+			expected: "invalid migration file name",
+		},
+		{
+			name:     "missing up script",
+			fsys:     fstest.MapFS{"0001_create_table.down.sql": {Data: []byte("DROP TABLE")}},
+			expected: "migration 1_create_table has no up script",
+		},
+		{
+			name: "empty up script",
+			fsys: fstest.MapFS{
+				"0001_create_table.up.sql":   {Data: []byte("")},
+				"0001_create_table.down.sql": {Data: []byte("DROP TABLE")},
+			},
+			expected: "has no up script",
+		},
+		{
+			name: "version used twice",
+			fsys: fstest.MapFS{
+				"0001_create_table.up.sql": {Data: []byte("CREATE TABLE")},
+				"0001_create_index.up.sql": {Data: []byte("CREATE INDEX")},
+			},
+			expected: "migration version 1 is used by",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			migrations, err := LoadMigrations(test.fsys)
+			if err == nil {
+				t.Fatalf("expected an error, got %+v", migrations)
+			} else if !strings.Contains(err.Error(), test.expected) {
+				t.Errorf("expected an error containing %q, got %q", test.expected, err.Error())
+			}
+		})
+	}
+}
+
+func TestMigrationChecksumCoversUpScriptOnly(t *testing.T) {
+	migration := Migration{Version: 1, Name: "create_table", Up: "CREATE TABLE A", Down: "DROP TABLE A"}
+	checksum := migration.Checksum()
+	if len(checksum) != 64 {
+		t.Fatalf("expected a hex encoded sha256, got %s", checksum)
+	}
+	unchanged := migration
+	unchanged.Down = "DROP TABLE IF EXISTS A"
+	unchanged.Name = "renamed"
+	if unchanged.Checksum() != checksum {
+		t.Error("expected the checksum not to depend on the down script nor the name")
+	}
+	modified := migration
+	modified.Up = "CREATE TABLE A "
+	if modified.Checksum() == checksum {
+		t.Error("expected any change of the up script to change the checksum")
+	}
+}
+
+// TestLoadMigrationsOfResources checks the migrations shipped with the application. sqlc reads the same directory as
+// its schema and skips the files ending in .down.sql, so every down script must follow the naming checked here.
+func TestLoadMigrationsOfResources(t *testing.T) {
+	migrations, err := LoadMigrations(os.DirFS("../../../resources/db/migrations"))
+	if err != nil {
+		t.Fatalf("unexpected error %s", err.Error())
+	}
+	for i, migration := range migrations {
+		if migration.Version != int64(i+1) {
+			t.Errorf("expected migration versions without gaps, got %d at position %d", migration.Version, i+1)
+		}
+		if strings.TrimSpace(migration.Down) == "" {
+			t.Errorf("expected migration %d_%s to have a down script", migration.Version, migration.Name)
+		}
+		if strings.Contains(strings.ToUpper(migration.Up), "DROP TABLE") {
+			t.Errorf("expected migration %d_%s not to drop tables in its up script", migration.Version, migration.Name)
+		}
+	}
+}
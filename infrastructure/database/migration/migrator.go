@@ -0,0 +1,225 @@
+package migration
+
+import (
+	"context"
+	"database/sql"
+	infrastructure "event-bus-demo/infrastructure/error"
+	"fmt"
+	"go.uber.org/zap"
+	"time"
+)
+
+// advisoryLockKey identifies the Postgres advisory lock held while migrating, so that several instances starting at
+// the same time do not apply the same migrations concurrently.
+const advisoryLockKey = 4_612_079_033
+
+const createMigrationsTable = `CREATE TABLE IF NOT EXISTS SCHEMA_MIGRATIONS (
+    VERSION BIGINT PRIMARY KEY,
+    NAME TEXT NOT NULL,
+    CHECKSUM TEXT NOT NULL,
+    APPLIED_AT TIMESTAMP NOT NULL
+)`
+
+type MigrationStatus struct {
+	Version   int64
+	Name      string
+	Applied   bool
+	AppliedAt *time.Time
+}
+
+type Migrator interface {
+	// Up applies every pending migration in version order.
+	Up(ctx context.Context) infrastructure.InfrastructureError
+	// Down reverts the given number of applied migrations, latest first.
+	Down(ctx context.Context, steps int) infrastructure.InfrastructureError
+	Status(ctx context.Context) ([]MigrationStatus, infrastructure.InfrastructureError)
+}
+
+type migrator struct {
+	db         *sql.DB
+	migrations []Migration
+	logger     *zap.Logger
+}
+
+type appliedMigration struct {
+	checksum  string
+	appliedAt time.Time
+}
+
+func NewMigrator(db *sql.DB, migrations []Migration, logger *zap.Logger) Migrator {
+	return &migrator{
+		db:         db,
+		migrations: migrations,
+		logger:     logger,
+	}
+}
+
+func (m *migrator) Up(ctx context.Context) infrastructure.InfrastructureError {
+	return m.withLock(ctx, func(conn *sql.Conn) infrastructure.InfrastructureError {
+		applied, err := m.verify(ctx, conn)
+		if err != nil {
+			return err
+		}
+		count := 0
+		for _, migration := range m.migrations {
+			if _, ok := applied[migration.Version]; ok {
+				continue
+			}
+			err := m.run(ctx, conn, migration, migration.Up,
+				`INSERT INTO SCHEMA_MIGRATIONS (VERSION, NAME, CHECKSUM, APPLIED_AT) VALUES ($1, $2, $3, $4)`,
+				migration.Version, migration.Name, migration.Checksum(), time.Now())
+			if err != nil {
+				return err
+			}
+			m.logger.Info("migration applied", zap.Int64("version", migration.Version), zap.String("name", migration.Name))
+			count++
+		}
+		m.logger.Info("database schema is up to date", zap.Int("applied_migrations", count))
+		return nil
+	})
+}
+
+func (m *migrator) Down(ctx context.Context, steps int) infrastructure.InfrastructureError {
+	if steps < 1 {
+		return infrastructure.NewInvalidArgumentError("steps must be greater than zero")
+	}
+	return m.withLock(ctx, func(conn *sql.Conn) infrastructure.InfrastructureError {
+		applied, err := m.verify(ctx, conn)
+		if err != nil {
+			return err
+		}
+		for i := len(m.migrations) - 1; i >= 0 && steps > 0; i-- {
+			migration := m.migrations[i]
+			if _, ok := applied[migration.Version]; !ok {
+				continue
+			} else if migration.Down == "" {
+				return infrastructure.NewInvalidArgumentError(fmt.Sprintf("migration %d_%s has no down script",
+					migration.Version, migration.Name))
+			}
+			err := m.run(ctx, conn, migration, migration.Down,
+				`DELETE FROM SCHEMA_MIGRATIONS WHERE VERSION = $1`, migration.Version)
+			if err != nil {
+				return err
+			}
+			m.logger.Info("migration reverted", zap.Int64("version", migration.Version), zap.String("name", migration.Name))
+			steps--
+		}
+		return nil
+	})
+}
+
+func (m *migrator) Status(ctx context.Context) ([]MigrationStatus, infrastructure.InfrastructureError) {
+	var statuses []MigrationStatus
+	err := m.withLock(ctx, func(conn *sql.Conn) infrastructure.InfrastructureError {
+		applied, err := m.readApplied(ctx, conn)
+		if err != nil {
+			return err
+		}
+		statuses = make([]MigrationStatus, 0, len(m.migrations))
+		for _, migration := range m.migrations {
+			status := MigrationStatus{Version: migration.Version, Name: migration.Name}
+			if appliedMigration, ok := applied[migration.Version]; ok {
+				status.Applied = true
+				status.AppliedAt = &appliedMigration.appliedAt
+			}
+			statuses = append(statuses, status)
+		}
+		return nil
+	})
+	return statuses, err
+}
+
+// withLock runs work holding the migration advisory lock, making sure the migrations table exists.
+func (m *migrator) withLock(ctx context.Context, work func(conn *sql.Conn) infrastructure.InfrastructureError) infrastructure.InfrastructureError {
+	return withAdvisoryLock(ctx, m.db, m.logger, func(conn *sql.Conn) infrastructure.InfrastructureError {
+		if _, err := conn.ExecContext(ctx, createMigrationsTable); err != nil {
+			return infrastructure.NewSQLError(err.Error())
+		}
+		return work(conn)
+	})
+}
+
+// withAdvisoryLock runs work on a single connection holding the migration advisory lock. Session level advisory locks
+// belong to the connection which acquired them, hence the dedicated connection instead of the pool.
+func withAdvisoryLock(ctx context.Context, db *sql.DB, logger *zap.Logger, work func(conn *sql.Conn) infrastructure.InfrastructureError) infrastructure.InfrastructureError {
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return infrastructure.NewSQLError(err.Error())
+	}
+	defer conn.Close()
+	if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_lock($1)`, advisoryLockKey); err != nil {
+		return infrastructure.NewSQLError(fmt.Sprintf("error while acquiring migration lock: %s", err.Error()))
+	}
+	defer func() {
+		if _, err := conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock($1)`, advisoryLockKey); err != nil {
+			logger.Error("error while releasing migration lock", zap.Error(err))
+		}
+	}()
+	return work(conn)
+}
+
+// verify makes sure that every applied migration is still known and unchanged, since editing a migration once applied
+// leaves databases with diverging schemas.
+func (m *migrator) verify(ctx context.Context, conn *sql.Conn) (map[int64]appliedMigration, infrastructure.InfrastructureError) {
+	applied, err := m.readApplied(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+	known := make(map[int64]Migration, len(m.migrations))
+	for _, migration := range m.migrations {
+		known[migration.Version] = migration
+	}
+	for version, appliedMigration := range applied {
+		migration, ok := known[version]
+		if !ok {
+			return nil, infrastructure.NewSQLError(fmt.Sprintf("applied migration %d is missing from the migration files", version))
+		} else if migration.Checksum() != appliedMigration.checksum {
+			return nil, infrastructure.NewSQLError(fmt.Sprintf("checksum mismatch for migration %d_%s, it was modified after being applied",
+				migration.Version, migration.Name))
+		}
+	}
+	return applied, nil
+}
+
+func (m *migrator) readApplied(ctx context.Context, conn *sql.Conn) (map[int64]appliedMigration, infrastructure.InfrastructureError) {
+	rows, err := conn.QueryContext(ctx, `SELECT VERSION, CHECKSUM, APPLIED_AT FROM SCHEMA_MIGRATIONS`)
+	if err != nil {
+		return nil, infrastructure.NewSQLError(err.Error())
+	}
+	defer rows.Close()
+	applied := make(map[int64]appliedMigration)
+	for rows.Next() {
+		var version int64
+		var migration appliedMigration
+		if err := rows.Scan(&version, &migration.checksum, &migration.appliedAt); err != nil {
+			return nil, infrastructure.NewSQLError(err.Error())
+		}
+		applied[version] = migration
+	}
+	if err := rows.Err(); err != nil {
+		return nil, infrastructure.NewSQLError(err.Error())
+	}
+	return applied, nil
+}
+
+// run executes a migration script and its bookkeeping statement in the same transaction, so a failing script leaves
+// neither the schema nor the migrations table changed.
+func (m *migrator) run(ctx context.Context, conn *sql.Conn, migration Migration, script string, bookkeeping string,
+	args ...interface{}) infrastructure.InfrastructureError {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return infrastructure.NewSQLError(err.Error())
+	}
+	if _, err := tx.ExecContext(ctx, script); err != nil {
+		_ = tx.Rollback()
+		return infrastructure.NewSQLError(fmt.Sprintf("migration %d_%s failed: %s", migration.Version, migration.Name, err.Error()))
+	}
+	if _, err := tx.ExecContext(ctx, bookkeeping, args...); err != nil {
+		_ = tx.Rollback()
+		return infrastructure.NewSQLError(err.Error())
+	}
+	if err := tx.Commit(); err != nil {
+		return infrastructure.NewSQLError(err.Error())
+	}
+	return nil
+}
@@ -0,0 +1,164 @@
+package migration
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"event-bus-demo/infrastructure/database/databasetest"
+	"go.uber.org/zap"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeDatabase stands in for Postgres: it keeps the rows of SCHEMA_MIGRATIONS, and the statements it runs are
+// recorded. Scripts containing FAIL return an error.
+type fakeDatabase struct {
+	databasetest.Database
+	applied map[int64]appliedMigration
+}
+
+func newFakeDatabase() *fakeDatabase {
+	database := &fakeDatabase{applied: make(map[int64]appliedMigration)}
+	database.Exec = database.exec
+	database.Query = database.query
+	return database
+}
+
+func (database *fakeDatabase) exec(query string, args []driver.NamedValue) (driver.Result, error) {
+	switch {
+	case strings.Contains(query, "FAIL"):
+		return nil, errors.New("syntax error")
+	case strings.HasPrefix(query, "INSERT INTO SCHEMA_MIGRATIONS"):
+		database.applied[args[0].Value.(int64)] = appliedMigration{
+			checksum:  args[2].Value.(string),
+			appliedAt: args[3].Value.(time.Time),
+		}
+	case strings.HasPrefix(query, "DELETE FROM SCHEMA_MIGRATIONS"):
+		delete(database.applied, args[0].Value.(int64))
+	}
+	return driver.RowsAffected(1), nil
+}
+
+func (database *fakeDatabase) query(string, []driver.NamedValue) (*databasetest.Rows, error) {
+	rows := &databasetest.Rows{ColumnNames: []string{"version", "checksum", "applied_at"}}
+	for version, migration := range database.applied {
+		rows.Values = append(rows.Values, []driver.Value{version, migration.checksum, migration.appliedAt})
+	}
+	return rows, nil
+}
+
+var testMigrations = []Migration{
+	{Version: 1, Name: "create_todos", Up: "CREATE TABLE TODOS", Down: "DROP TABLE TODOS"},
+	{Version: 2, Name: "create_categories", Up: "CREATE TABLE CATEGORIES", Down: "DROP TABLE CATEGORIES"},
+	{Version: 3, Name: "create_items", Up: "CREATE TABLE ITEMS", Down: "DROP TABLE ITEMS"},
+}
+
+func newTestMigrator(database *fakeDatabase, migrations []Migration) Migrator {
+	return NewMigrator(database.Open(), migrations, zap.NewNop())
+}
+
+// scripts returns the executed statements which are neither locking nor bookkeeping.
+func scripts(statements []string) []string {
+	result := make([]string, 0)
+	for _, statement := range statements {
+		if !strings.Contains(statement, "pg_advisory") && !strings.Contains(statement, "SCHEMA_MIGRATIONS") {
+			result = append(result, statement)
+		}
+	}
+	return result
+}
+
+func assertLockedSession(t *testing.T, statements []string) {
+	t.Helper()
+	if len(statements) < 2 || !strings.Contains(statements[0], "pg_advisory_lock") ||
+		!strings.Contains(statements[len(statements)-1], "pg_advisory_unlock") {
+		t.Errorf("expected the statements to run between acquiring and releasing the advisory lock, got %v", statements)
+	}
+}
+
+func TestMigratorUpAppliesPendingMigrationsInOrder(t *testing.T) {
+	database := newFakeDatabase()
+	database.applied[1] = appliedMigration{checksum: testMigrations[0].Checksum(), appliedAt: time.Now()}
+	if err := newTestMigrator(database, testMigrations).Up(context.Background()); err != nil {
+		t.Fatalf("unexpected error %s", err.Error())
+	}
+	statements := database.Statements()
+	assertLockedSession(t, statements)
+	expected := []string{"CREATE TABLE CATEGORIES", "CREATE TABLE ITEMS"}
+	if actual := scripts(statements); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected scripts %v, got %v", expected, actual)
+	}
+	for _, migration := range testMigrations {
+		if database.applied[migration.Version].checksum != migration.Checksum() {
+			t.Errorf("expected migration %d to be recorded with its checksum", migration.Version)
+		}
+	}
+}
+
+func TestMigratorUpRejectsDivergingSchema(t *testing.T) {
+	tests := []struct {
+		name     string
+		applied  map[int64]appliedMigration
+		expected string
+	}{
+		{
+			name:     "checksum mismatch",
+			applied:  map[int64]appliedMigration{1: {checksum: "modified", appliedAt: time.Now()}},
+			expected: "checksum mismatch for migration 1_create_todos",
+		},
+		{
+			name:     "unknown migration",
+			applied:  map[int64]appliedMigration{4: {checksum: "unknown", appliedAt: time.Now()}},
+			expected: "applied migration 4 is missing from the migration files",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			database := newFakeDatabase()
+			database.applied = test.applied
+			err := newTestMigrator(database, testMigrations).Up(context.Background())
+			if err == nil || !strings.Contains(err.Error(), test.expected) {
+				t.Fatalf("expected an error containing %q, got %v", test.expected, err)
+			}
+			statements := database.Statements()
+			assertLockedSession(t, statements)
+			if actual := scripts(statements); len(actual) != 0 {
+				t.Errorf("expected no migration to run, got %v", actual)
+			}
+		})
+	}
+}
+
+func TestMigratorUpStopsAtFailingMigration(t *testing.T) {
+	database := newFakeDatabase()
+	migrations := []Migration{testMigrations[0], {Version: 2, Name: "broken", Up: "FAIL"}, testMigrations[2]}
+	err := newTestMigrator(database, migrations).Up(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "migration 2_broken failed") {
+		t.Fatalf("expected the failing migration to be reported, got %v", err)
+	}
+	assertLockedSession(t, database.Statements())
+	if _, ok := database.applied[2]; ok || len(database.applied) != 1 {
+		t.Errorf("expected only the first migration to be recorded, got %v", database.applied)
+	}
+}
+
+func TestMigratorDownRevertsLatestFirst(t *testing.T) {
+	database := newFakeDatabase()
+	for _, migration := range testMigrations {
+		database.applied[migration.Version] = appliedMigration{checksum: migration.Checksum(), appliedAt: time.Now()}
+	}
+	if err := newTestMigrator(database, testMigrations).Down(context.Background(), 2); err != nil {
+		t.Fatalf("unexpected error %s", err.Error())
+	}
+	statements := database.Statements()
+	assertLockedSession(t, statements)
+	expected := []string{"DROP TABLE ITEMS", "DROP TABLE CATEGORIES"}
+	if actual := scripts(statements); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected scripts %v, got %v", expected, actual)
+	}
+	if _, ok := database.applied[1]; !ok || len(database.applied) != 1 {
+		t.Errorf("expected only the first migration to stay applied, got %v", database.applied)
+	}
+}
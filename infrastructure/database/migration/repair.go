@@ -0,0 +1,132 @@
+package migration
+
+import (
+	"context"
+	"database/sql"
+	infrastructure "event-bus-demo/infrastructure/error"
+	"go.uber.org/zap"
+)
+
+// integrityCheck describes rows breaking a foreign key constraint which was added as NOT VALID on top of existing data.
+type integrityCheck struct {
+	constraint string
+	table      string
+	count      string
+	repair     string
+}
+
+var integrityChecks = []integrityCheck{
+	{
+		constraint: "fk_todo_category_todo",
+		table:      "todo_category",
+		count:      `SELECT COUNT(*) FROM todo_category tc WHERE NOT EXISTS (SELECT 1 FROM todos t WHERE t.id = tc.todo_id)`,
+		repair:     `DELETE FROM todo_category tc WHERE NOT EXISTS (SELECT 1 FROM todos t WHERE t.id = tc.todo_id)`,
+	},
+	{
+		constraint: "fk_todo_category_category",
+		table:      "todo_category",
+		count:      `SELECT COUNT(*) FROM todo_category tc WHERE NOT EXISTS (SELECT 1 FROM categories c WHERE c.id = tc.category_id)`,
+		repair:     `DELETE FROM todo_category tc WHERE NOT EXISTS (SELECT 1 FROM categories c WHERE c.id = tc.category_id)`,
+	},
+}
+
+type IntegrityReport struct {
+	Constraint string
+	// OrphanedRows is the number of rows breaking the constraint, found by a check or removed by a repair
+	OrphanedRows int64
+	Validated    bool
+}
+
+type IntegrityRepair interface {
+	// Check reports the orphaned rows without changing anything.
+	Check(ctx context.Context) ([]IntegrityReport, infrastructure.InfrastructureError)
+	// Repair deletes the orphaned rows and validates the constraints, so they hold for existing rows too.
+	Repair(ctx context.Context) ([]IntegrityReport, infrastructure.InfrastructureError)
+}
+
+type integrityRepair struct {
+	db     *sql.DB
+	logger *zap.Logger
+}
+
+func NewIntegrityRepair(db *sql.DB, logger *zap.Logger) IntegrityRepair {
+	return &integrityRepair{
+		db:     db,
+		logger: logger,
+	}
+}
+
+func (repair *integrityRepair) Check(ctx context.Context) ([]IntegrityReport, infrastructure.InfrastructureError) {
+	reports := make([]IntegrityReport, 0, len(integrityChecks))
+	err := withAdvisoryLock(ctx, repair.db, repair.logger, func(conn *sql.Conn) infrastructure.InfrastructureError {
+		for _, check := range integrityChecks {
+			report := IntegrityReport{Constraint: check.constraint}
+			if err := conn.QueryRowContext(ctx, check.count).Scan(&report.OrphanedRows); err != nil {
+				return infrastructure.NewSQLError(err.Error())
+			}
+			validated, err := isConstraintValidated(ctx, conn, check.constraint)
+			if err != nil {
+				return err
+			}
+			report.Validated = validated
+			reports = append(reports, report)
+		}
+		return nil
+	})
+	return reports, err
+}
+
+func (repair *integrityRepair) Repair(ctx context.Context) ([]IntegrityReport, infrastructure.InfrastructureError) {
+	reports := make([]IntegrityReport, 0, len(integrityChecks))
+	err := withAdvisoryLock(ctx, repair.db, repair.logger, func(conn *sql.Conn) infrastructure.InfrastructureError {
+		for _, check := range integrityChecks {
+			report, err := repair.repairConstraint(ctx, conn, check)
+			if err != nil {
+				return err
+			}
+			repair.logger.Info("constraint repaired", zap.String("constraint", check.constraint),
+				zap.Int64("deleted_rows", report.OrphanedRows))
+			reports = append(reports, report)
+		}
+		return nil
+	})
+	return reports, err
+}
+
+// repairConstraint deletes the orphaned rows and validates the constraint in the same transaction, so no orphan can be
+// written in between.
+func (repair *integrityRepair) repairConstraint(ctx context.Context, conn *sql.Conn, check integrityCheck) (IntegrityReport, infrastructure.InfrastructureError) {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return IntegrityReport{}, infrastructure.NewSQLError(err.Error())
+	}
+	result, err := tx.ExecContext(ctx, check.repair)
+	if err != nil {
+		_ = tx.Rollback()
+		return IntegrityReport{}, infrastructure.NewSQLError(err.Error())
+	}
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		_ = tx.Rollback()
+		return IntegrityReport{}, infrastructure.NewSQLError(err.Error())
+	}
+	if _, err := tx.ExecContext(ctx, "ALTER TABLE "+check.table+" VALIDATE CONSTRAINT "+check.constraint); err != nil {
+		_ = tx.Rollback()
+		return IntegrityReport{}, infrastructure.NewSQLError(err.Error())
+	}
+	if err := tx.Commit(); err != nil {
+		return IntegrityReport{}, infrastructure.NewSQLError(err.Error())
+	}
+	return IntegrityReport{Constraint: check.constraint, OrphanedRows: deleted, Validated: true}, nil
+}
+
+func isConstraintValidated(ctx context.Context, conn *sql.Conn, constraint string) (bool, infrastructure.InfrastructureError) {
+	var validated bool
+	err := conn.QueryRowContext(ctx, `SELECT convalidated FROM pg_constraint WHERE conname = $1`, constraint).Scan(&validated)
+	if err == sql.ErrNoRows {
+		return false, infrastructure.NewItemNotFoundError("constraint " + constraint + " not found, run the migrations first")
+	} else if err != nil {
+		return false, infrastructure.NewSQLError(err.Error())
+	}
+	return validated, nil
+}
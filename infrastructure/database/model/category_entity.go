@@ -5,6 +5,7 @@ import (
 )
 
 type CategoryEntity struct {
-	ID   uuid.UUID
-	Name string
+	ID      uuid.UUID
+	Name    string
+	Version int64
 }
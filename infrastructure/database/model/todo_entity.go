@@ -11,5 +11,83 @@ type ToDoEntity struct {
 	Description string
 	CreatedAt   *time.Time
 	UpdatedAt   *time.Time
+	Status      string
+	Priority    string
+	DueAt       *time.Time
+	CompletedAt *time.Time
+	Recurrence  *string
+	SeriesID    *uuid.UUID
+	Occurrence  int
+	Version     int64
 	Categories  []CategoryEntity
+	Items       []ToDoItemEntity
+}
+
+// ToDoUpdateEntity holds the changes of a ToDo. Nil fields keep their stored value, except Recurrence which is removed
+// when SetRecurrence is true.
+type ToDoUpdateEntity struct {
+	ID              uuid.UUID
+	Title           *string
+	Description     *string
+	Priority        *string
+	SetRecurrence   bool
+	Recurrence      *string
+	ExpectedVersion *int64
+}
+
+// ToDoStatusChangeEntity changes the status of a ToDo, provided its current status is one of FromStatuses.
+type ToDoStatusChangeEntity struct {
+	ID              uuid.UUID
+	Status          string
+	CompletedAt     *time.Time
+	FromStatuses    []string
+	ExpectedVersion *int64
+}
+
+// ToDoRescheduleEntity changes the due date of a ToDo, provided its current status is one of FromStatuses.
+type ToDoRescheduleEntity struct {
+	ID              uuid.UUID
+	DueAt           *time.Time
+	FromStatuses    []string
+	ExpectedVersion *int64
+}
+
+// ToDoSearchEntity holds the filters, sorting and page of a ToDo search. Nil filters are not applied.
+type ToDoSearchEntity struct {
+	CategoryID  *uuid.UUID
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+	UpdatedFrom *time.Time
+	UpdatedTo   *time.Time
+	Title       *string
+	Status      *string
+	Priority    *string
+	DueFrom     *time.Time
+	DueTo       *time.Time
+	// Unfinished keeps the open and in progress ToDos only
+	Unfinished bool
+	SortField  string
+	Descending bool
+	// After returns the ToDos following the given position in the sort order, instead of skipping Offset ToDos
+	After  *ToDoCursorEntity
+	Limit  int
+	Offset int
+}
+
+// ToDoCursorEntity is a position in a sorted ToDo list, given by the sort key and ID of a ToDo.
+type ToDoCursorEntity struct {
+	SortKey string
+	ID      uuid.UUID
+}
+
+type ToDoSearchResultEntity struct {
+	ToDo    ToDoEntity
+	SortKey string
+}
+
+type ToDoTextSearchResultEntity struct {
+	ToDo           ToDoEntity
+	Rank           float64
+	TitleHighlight string
+	Snippet        string
 }
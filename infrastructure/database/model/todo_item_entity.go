@@ -0,0 +1,24 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+type ToDoItemEntity struct {
+	ID        uuid.UUID
+	ToDoID    uuid.UUID
+	Title     string
+	Done      bool
+	Position  int
+	CreatedAt *time.Time
+	UpdatedAt *time.Time
+}
+
+// ToDoItemUpdateEntity holds the changes of a checklist item. Nil fields keep their stored value.
+type ToDoItemUpdateEntity struct {
+	ID     uuid.UUID
+	ToDoID uuid.UUID
+	Title  *string
+	Done   *bool
+}
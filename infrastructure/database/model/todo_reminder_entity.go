@@ -0,0 +1,23 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+type ToDoReminderEntity struct {
+	ID        uuid.UUID
+	ToDoID    uuid.UUID
+	BeforeDue time.Duration
+	CreatedAt time.Time
+	SentDueAt *time.Time
+	SentAt    *time.Time
+}
+
+type UpcomingToDoReminderEntity struct {
+	ID       uuid.UUID
+	ToDoID   uuid.UUID
+	Title    string
+	DueAt    time.Time
+	RemindAt time.Time
+}
@@ -17,8 +17,12 @@ type CategoryRepository interface {
 	FindCategoriesList(ctx context.Context, queries *sqlc.Queries) ([]model.CategoryEntity, error.InfrastructureError)
 	FindCategoryByID(ctx context.Context, queries *sqlc.Queries, ID uuid.UUID) (model.CategoryEntity, error.InfrastructureError)
 	CreateCategory(ctx context.Context, queries *sqlc.Queries, entity model.CategoryEntity) error.InfrastructureError
-	DeleteCategoryByID(ctx context.Context, queries *sqlc.Queries, ID uuid.UUID) error.InfrastructureError
-	UpdateCategoryName(ctx context.Context, queries *sqlc.Queries, entity model.CategoryEntity) error.InfrastructureError
+	DeleteCategoryByID(ctx context.Context, queries *sqlc.Queries, ID uuid.UUID, expectedVersion *int64) error.InfrastructureError
+	UpdateCategoryName(ctx context.Context, queries *sqlc.Queries, entity model.CategoryEntity, expectedVersion *int64) error.InfrastructureError
+	CountCategoryToDos(ctx context.Context, queries *sqlc.Queries, ID uuid.UUID) (int64, error.InfrastructureError)
+	BumpCategoryToDoVersions(ctx context.Context, queries *sqlc.Queries, ID uuid.UUID) error.InfrastructureError
+	RemoveCategoryFromAllToDos(ctx context.Context, queries *sqlc.Queries, ID uuid.UUID) error.InfrastructureError
+	ReassignCategoryToDos(ctx context.Context, queries *sqlc.Queries, ID uuid.UUID, targetID uuid.UUID) error.InfrastructureError
 }
 
 type categoryRepository struct {
@@ -36,7 +40,7 @@ func NewCategoryRepository(logger *zap.Logger, db *sql.DB) CategoryRepository {
 func (repository *categoryRepository) FindCategoriesList(ctx context.Context, queries *sqlc.Queries) ([]model.CategoryEntity, error.InfrastructureError) {
 	categories, err := queries.GetCategoriesList(ctx)
 	if err != nil {
-		return nil, error.NewSQLError(err.Error())
+		return nil, newSQLError(ctx, repository.logger, err)
 	}
 	return mapper.NewCategoryEntityListFromSQLModelList(categories), nil
 }
@@ -47,20 +51,21 @@ func (repository *categoryRepository) FindCategoryByID(ctx context.Context, quer
 		if err.Error() == constants.NotFoundErrorMessage {
 			return model.CategoryEntity{}, error.NewItemNotFoundError(fmt.Sprintf("category item with ID %s not found", ID))
 		}
-		return model.CategoryEntity{}, error.NewSQLError(err.Error())
+		return model.CategoryEntity{}, newSQLError(ctx, repository.logger, err)
 	}
 	return mapper.NewCategoryEntityFromSQLModel(category), nil
 }
 
-func (repository *categoryRepository) UpdateCategoryName(ctx context.Context, queries *sqlc.Queries, entity model.CategoryEntity) error.InfrastructureError {
-	err := queries.UpdateCategoryName(ctx, sqlc.UpdateCategoryNameParams{
-		ID:   entity.ID,
-		Name: entity.Name,
+func (repository *categoryRepository) UpdateCategoryName(ctx context.Context, queries *sqlc.Queries, entity model.CategoryEntity, expectedVersion *int64) error.InfrastructureError {
+	updated, err := queries.UpdateCategoryName(ctx, sqlc.UpdateCategoryNameParams{
+		ID:              entity.ID,
+		Name:            entity.Name,
+		ExpectedVersion: mapper.NewExpectedVersionParam(expectedVersion),
 	})
 	if err != nil {
-		return error.NewSQLError(err.Error())
+		return newSQLError(ctx, repository.logger, err)
 	}
-	return nil
+	return repository.checkVersion(ctx, queries, entity.ID, expectedVersion, updated)
 }
 
 func (repository *categoryRepository) CreateCategory(ctx context.Context, queries *sqlc.Queries, entity model.CategoryEntity) error.InfrastructureError {
@@ -69,15 +74,70 @@ func (repository *categoryRepository) CreateCategory(ctx context.Context, querie
 		Name: entity.Name,
 	})
 	if err != nil {
-		return error.NewSQLError(err.Error())
+		return newSQLError(ctx, repository.logger, err)
+	}
+	return nil
+}
+
+func (repository *categoryRepository) DeleteCategoryByID(ctx context.Context, queries *sqlc.Queries, ID uuid.UUID, expectedVersion *int64) error.InfrastructureError {
+	deleted, err := queries.DeleteCategory(ctx, sqlc.DeleteCategoryParams{
+		ID:              ID,
+		ExpectedVersion: mapper.NewExpectedVersionParam(expectedVersion),
+	})
+	if err != nil {
+		return newSQLError(ctx, repository.logger, err)
+	}
+	return repository.checkVersion(ctx, queries, ID, expectedVersion, deleted)
+}
+
+// checkVersion tells why a statement expecting a version changed no row: either the category does not exist or it is
+// at another version. Statements without an expected version keep ignoring missing categories.
+func (repository *categoryRepository) checkVersion(ctx context.Context, queries *sqlc.Queries, ID uuid.UUID, expectedVersion *int64, affected int64) error.InfrastructureError {
+	if affected > 0 || expectedVersion == nil {
+		return nil
+	}
+	category, err := repository.FindCategoryByID(ctx, queries, ID)
+	if err != nil {
+		return err
+	}
+	return error.NewVersionMismatchError(fmt.Sprintf("category item with ID %s is at version %d, not %d", ID, category.Version, *expectedVersion))
+}
+
+func (repository *categoryRepository) CountCategoryToDos(ctx context.Context, queries *sqlc.Queries, ID uuid.UUID) (int64, error.InfrastructureError) {
+	count, err := queries.CountCategoryToDos(ctx, ID)
+	if err != nil {
+		return 0, newSQLError(ctx, repository.logger, err)
+	}
+	return count, nil
+}
+
+// BumpCategoryToDoVersions increments the version of every ToDo of the category, since its categories are part of the
+// ToDo.
+func (repository *categoryRepository) BumpCategoryToDoVersions(ctx context.Context, queries *sqlc.Queries, ID uuid.UUID) error.InfrastructureError {
+	err := queries.BumpCategoryToDoVersions(ctx, ID)
+	if err != nil {
+		return newSQLError(ctx, repository.logger, err)
 	}
 	return nil
 }
 
-func (repository *categoryRepository) DeleteCategoryByID(ctx context.Context, queries *sqlc.Queries, ID uuid.UUID) error.InfrastructureError {
-	err := queries.DeleteCategory(ctx, ID)
+func (repository *categoryRepository) RemoveCategoryFromAllToDos(ctx context.Context, queries *sqlc.Queries, ID uuid.UUID) error.InfrastructureError {
+	err := queries.RemoveCategoryFromAllToDos(ctx, ID)
+	if err != nil {
+		return newSQLError(ctx, repository.logger, err)
+	}
+	return nil
+}
+
+// ReassignCategoryToDos links every ToDo of the category to the target category, keeping the links to the category
+// itself so they can be removed afterwards.
+func (repository *categoryRepository) ReassignCategoryToDos(ctx context.Context, queries *sqlc.Queries, ID uuid.UUID, targetID uuid.UUID) error.InfrastructureError {
+	err := queries.ReassignCategoryToDos(ctx, sqlc.ReassignCategoryToDosParams{
+		TargetCategoryID: targetID,
+		CategoryID:       ID,
+	})
 	if err != nil {
-		return error.NewSQLError(err.Error())
+		return newSQLError(ctx, repository.logger, err)
 	}
 	return nil
 }
@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"context"
+	"event-bus-demo/infrastructure/database/sqlc"
+	"event-bus-demo/infrastructure/error"
+	"event-bus-demo/infrastructure/resilience"
+	"fmt"
+)
+
+type circuitBreakerTransactionalRepository struct {
+	TransactionalRepository
+	breaker resilience.CircuitBreaker
+}
+
+// NewCircuitBreakerTransactionalRepository stops opening transactions once the database keeps failing, returning a
+// CircuitOpen error instead of waiting for every connection attempt to fail.
+func NewCircuitBreakerTransactionalRepository(repository TransactionalRepository, breaker resilience.CircuitBreaker) TransactionalRepository {
+	return &circuitBreakerTransactionalRepository{
+		TransactionalRepository: repository,
+		breaker:                 breaker,
+	}
+}
+
+func (repo *circuitBreakerTransactionalRepository) CreateNewTransaction(ctx context.Context) (*sqlc.Queries, error.InfrastructureError) {
+	if !repo.breaker.Allow() {
+		return nil, error.NewCircuitOpenError(fmt.Sprintf("database unavailable, circuit breaker %s is open", repo.breaker.GetName()))
+	}
+	queries, err := repo.TransactionalRepository.CreateNewTransaction(ctx)
+	if err != nil && err.GetCode() == error.SQLError {
+		repo.breaker.Failure()
+	} else {
+		repo.breaker.Success()
+	}
+	return queries, err
+}
@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	infrastructure "event-bus-demo/infrastructure/error"
+	"event-bus-demo/infrastructure/logging"
+	"fmt"
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+)
+
+// foreignKeyViolation is the Postgres error code raised when a statement breaks a foreign key constraint.
+const foreignKeyViolation = "23503"
+
+func newSQLError(ctx context.Context, logger *zap.Logger, err error) infrastructure.InfrastructureError {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == foreignKeyViolation {
+		logging.FromContext(ctx, logger).Warn("sql query violates a foreign key", zap.String("constraint", pqErr.Constraint))
+		return infrastructure.NewConflictError(fmt.Sprintf("operation conflicts with related data: %s", pqErr.Detail))
+	}
+	logging.FromContext(ctx, logger).Error("error while executing sql query", zap.String("error", err.Error()))
+	return infrastructure.NewSQLError(err.Error())
+}
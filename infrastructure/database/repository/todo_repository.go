@@ -8,6 +8,7 @@ import (
 	"event-bus-demo/infrastructure/database/model"
 	"event-bus-demo/infrastructure/database/sqlc"
 	"event-bus-demo/infrastructure/error"
+	"event-bus-demo/infrastructure/util"
 	"fmt"
 	"github.com/google/uuid"
 	"go.uber.org/zap"
@@ -15,13 +16,26 @@ import (
 )
 
 type ToDoRepository interface {
-	FindToDoList(ctx context.Context, queries *sqlc.Queries) ([]model.ToDoEntity, error.InfrastructureError)
+	SearchToDoList(ctx context.Context, queries *sqlc.Queries, search model.ToDoSearchEntity) ([]model.ToDoSearchResultEntity, error.InfrastructureError)
+	SearchToDoText(ctx context.Context, queries *sqlc.Queries, query string, limit int) ([]model.ToDoTextSearchResultEntity, error.InfrastructureError)
 	FindToDoByID(ctx context.Context, queries *sqlc.Queries, ID uuid.UUID) (model.ToDoEntity, error.InfrastructureError)
 	CreateToDo(ctx context.Context, queries *sqlc.Queries, entity model.ToDoEntity) error.InfrastructureError
-	DeleteToDoByID(ctx context.Context, queries *sqlc.Queries, ID uuid.UUID) error.InfrastructureError
-	UpdateToDoInformation(ctx context.Context, queries *sqlc.Queries, entity model.ToDoEntity) error.InfrastructureError
+	DeleteToDoByID(ctx context.Context, queries *sqlc.Queries, ID uuid.UUID, expectedVersion *int64) error.InfrastructureError
+	UpdateToDoInformation(ctx context.Context, queries *sqlc.Queries, update model.ToDoUpdateEntity) error.InfrastructureError
+	BumpToDoVersion(ctx context.Context, queries *sqlc.Queries, ID uuid.UUID, expectedVersion *int64) error.InfrastructureError
+	ChangeToDoStatus(ctx context.Context, queries *sqlc.Queries, change model.ToDoStatusChangeEntity) error.InfrastructureError
+	RescheduleToDo(ctx context.Context, queries *sqlc.Queries, reschedule model.ToDoRescheduleEntity) error.InfrastructureError
 	DeleteToDoCategories(ctx context.Context, queries *sqlc.Queries, toDoID uuid.UUID, categoriesID []uuid.UUID) error.InfrastructureError
 	AddToDoCategories(ctx context.Context, queries *sqlc.Queries, toDoID uuid.UUID, categoriesID []uuid.UUID) error.InfrastructureError
+	CreateToDoItem(ctx context.Context, queries *sqlc.Queries, item model.ToDoItemEntity, position *int) error.InfrastructureError
+	UpdateToDoItem(ctx context.Context, queries *sqlc.Queries, update model.ToDoItemUpdateEntity) error.InfrastructureError
+	DeleteToDoItem(ctx context.Context, queries *sqlc.Queries, toDoID uuid.UUID, ID uuid.UUID) error.InfrastructureError
+	ReorderToDoItems(ctx context.Context, queries *sqlc.Queries, toDoID uuid.UUID, items []uuid.UUID) error.InfrastructureError
+	FindToDoReminders(ctx context.Context, queries *sqlc.Queries, toDoID uuid.UUID) ([]model.ToDoReminderEntity, error.InfrastructureError)
+	FindUpcomingToDoReminders(ctx context.Context, queries *sqlc.Queries, remindBefore time.Time, limit int) ([]model.UpcomingToDoReminderEntity, error.InfrastructureError)
+	CreateToDoReminder(ctx context.Context, queries *sqlc.Queries, reminder model.ToDoReminderEntity) error.InfrastructureError
+	DeleteToDoReminder(ctx context.Context, queries *sqlc.Queries, toDoID uuid.UUID, ID uuid.UUID) error.InfrastructureError
+	MarkToDoReminderSent(ctx context.Context, queries *sqlc.Queries, ID uuid.UUID, dueAt time.Time) error.InfrastructureError
 }
 
 type toDoRepository struct {
@@ -36,23 +50,50 @@ func NewToDoRepository(logger *zap.Logger, db *sql.DB) ToDoRepository {
 	}
 }
 
-func (repository *toDoRepository) FindToDoList(ctx context.Context, queries *sqlc.Queries) ([]model.ToDoEntity, error.InfrastructureError) {
-	toDoList, err := queries.GetToDoList(ctx)
+func (repository *toDoRepository) SearchToDoList(ctx context.Context, queries *sqlc.Queries, search model.ToDoSearchEntity) ([]model.ToDoSearchResultEntity, error.InfrastructureError) {
+	rows, err := queries.SearchToDoList(ctx, mapper.NewSearchToDoListParamsFromEntity(search))
 	if err != nil {
-		return nil, error.NewSQLError(err.Error())
+		return nil, newSQLError(ctx, repository.logger, err)
 	}
-	if toDoList == nil {
-		return make([]model.ToDoEntity, 0), nil
+	results := mapper.NewToDoSearchResultEntityListFromSQLModelList(rows)
+	toDoIDs := make([]uuid.UUID, 0, len(results))
+	for _, result := range results {
+		toDoIDs = append(toDoIDs, result.ToDo.ID)
 	}
-	entityList := mapper.NewToDoEntityListFromSQLModelList(toDoList)
-	for _, entity := range entityList {
-		categories, err := queries.GetToDoCategories(ctx, entity.ID)
-		if err != nil {
-			return nil, error.NewSQLError(err.Error())
+	// Categories and items of every ToDo are fetched at once, so searching takes three queries whatever the number of
+	// ToDos
+	categories, err := queries.GetToDoListCategories(ctx, toDoIDs)
+	if err != nil {
+		return nil, newSQLError(ctx, repository.logger, err)
+	}
+	items, err := queries.GetToDoListItems(ctx, toDoIDs)
+	if err != nil {
+		return nil, newSQLError(ctx, repository.logger, err)
+	}
+	categoriesByToDo := mapper.NewCategoryEntitiesByToDoIDFromSQLModelList(categories)
+	itemsByToDo := mapper.NewToDoItemEntitiesByToDoIDFromSQLModelList(items)
+	for i := range results {
+		results[i].ToDo.Categories = make([]model.CategoryEntity, 0)
+		if toDoCategories, ok := categoriesByToDo[results[i].ToDo.ID]; ok {
+			results[i].ToDo.Categories = toDoCategories
+		}
+		results[i].ToDo.Items = make([]model.ToDoItemEntity, 0)
+		if toDoItems, ok := itemsByToDo[results[i].ToDo.ID]; ok {
+			results[i].ToDo.Items = toDoItems
 		}
-		entity.Categories = mapper.NewCategoryEntityListFromSQLModelList(categories)
 	}
-	return entityList, nil
+	return results, nil
+}
+
+func (repository *toDoRepository) SearchToDoText(ctx context.Context, queries *sqlc.Queries, query string, limit int) ([]model.ToDoTextSearchResultEntity, error.InfrastructureError) {
+	rows, err := queries.SearchToDoText(ctx, sqlc.SearchToDoTextParams{
+		Query:     query,
+		PageLimit: int32(limit),
+	})
+	if err != nil {
+		return nil, newSQLError(ctx, repository.logger, err)
+	}
+	return mapper.NewToDoTextSearchResultEntityListFromSQLModelList(rows), nil
 }
 
 func (repository *toDoRepository) FindToDoByID(ctx context.Context, queries *sqlc.Queries, ID uuid.UUID) (model.ToDoEntity, error.InfrastructureError) {
@@ -61,14 +102,19 @@ func (repository *toDoRepository) FindToDoByID(ctx context.Context, queries *sql
 		if err.Error() == constants.NotFoundErrorMessage {
 			return model.ToDoEntity{}, error.NewItemNotFoundError(fmt.Sprintf("toDo item with ID %s not found", ID))
 		}
-		return model.ToDoEntity{}, error.NewSQLError(err.Error())
+		return model.ToDoEntity{}, newSQLError(ctx, repository.logger, err)
 	}
 	entity := mapper.NewToDoEntityFromSQLModel(toDo)
 	categories, err := queries.GetToDoCategories(ctx, entity.ID)
 	if err != nil {
-		return model.ToDoEntity{}, error.NewSQLError(err.Error())
+		return model.ToDoEntity{}, newSQLError(ctx, repository.logger, err)
 	}
 	entity.Categories = mapper.NewCategoryEntityListFromSQLModelList(categories)
+	items, err := queries.GetToDoItems(ctx, entity.ID)
+	if err != nil {
+		return model.ToDoEntity{}, newSQLError(ctx, repository.logger, err)
+	}
+	entity.Items = mapper.NewToDoItemEntityListFromSQLModelList(items)
 	return entity, nil
 }
 
@@ -78,6 +124,11 @@ func (repository *toDoRepository) CreateToDo(ctx context.Context, queries *sqlc.
 		Title:       entity.Title,
 		Description: entity.Description,
 		CreatedAt:   *entity.CreatedAt,
+		Priority:    entity.Priority,
+		DueAt:       mapper.NewNullTime(entity.DueAt),
+		Recurrence:  mapper.NewNullString(entity.Recurrence),
+		SeriesID:    mapper.NewNullUUID(entity.SeriesID),
+		Occurrence:  int32(entity.Occurrence),
 	})
 	for _, category := range entity.Categories {
 		err := queries.AddToDoCategory(ctx, sqlc.AddToDoCategoryParams{
@@ -85,41 +136,93 @@ func (repository *toDoRepository) CreateToDo(ctx context.Context, queries *sqlc.
 			CategoryID: category.ID,
 		})
 		if err != nil {
-			return error.NewSQLError(err.Error())
+			return newSQLError(ctx, repository.logger, err)
 		}
 	}
 	if err != nil {
-		return error.NewSQLError(err.Error())
+		return newSQLError(ctx, repository.logger, err)
 	}
 	return nil
 }
 
-func (repository *toDoRepository) DeleteToDoByID(ctx context.Context, queries *sqlc.Queries, ID uuid.UUID) error.InfrastructureError {
+func (repository *toDoRepository) DeleteToDoByID(ctx context.Context, queries *sqlc.Queries, ID uuid.UUID, expectedVersion *int64) error.InfrastructureError {
 	err := queries.RemoveAllCategoriesFromToDo(ctx, ID)
 	if err != nil {
-		return error.NewSQLError(err.Error())
+		return newSQLError(ctx, repository.logger, err)
 	}
-	err = queries.DeleteToDo(ctx, ID)
+	deleted, err := queries.DeleteToDo(ctx, sqlc.DeleteToDoParams{
+		ID:              ID,
+		ExpectedVersion: mapper.NewExpectedVersionParam(expectedVersion),
+	})
 	if err != nil {
-		return error.NewSQLError(err.Error())
+		return newSQLError(ctx, repository.logger, err)
 	}
-	return nil
+	return repository.checkVersion(ctx, queries, ID, expectedVersion, deleted)
 }
 
-func (repository *toDoRepository) UpdateToDoInformation(ctx context.Context, queries *sqlc.Queries, entity model.ToDoEntity) error.InfrastructureError {
-	err := queries.UpdateToDoInformation(ctx, sqlc.UpdateToDoInformationParams{
-		ID:          entity.ID,
-		Title:       entity.Title,
-		Description: entity.Description,
-		UpdatedAt: sql.NullTime{
-			Time:  time.Now(),
-			Valid: true,
-		},
+func (repository *toDoRepository) UpdateToDoInformation(ctx context.Context, queries *sqlc.Queries, update model.ToDoUpdateEntity) error.InfrastructureError {
+	updated, err := queries.UpdateToDoInformation(ctx, mapper.NewUpdateToDoInformationParamsFromEntity(update))
+	if err != nil {
+		return newSQLError(ctx, repository.logger, err)
+	}
+	return repository.checkVersion(ctx, queries, update.ID, update.ExpectedVersion, updated)
+}
+
+// BumpToDoVersion increments the version of a ToDo whose categories changed, since they are part of the ToDo.
+func (repository *toDoRepository) BumpToDoVersion(ctx context.Context, queries *sqlc.Queries, ID uuid.UUID, expectedVersion *int64) error.InfrastructureError {
+	updated, err := queries.BumpToDoVersion(ctx, sqlc.BumpToDoVersionParams{
+		ID:              ID,
+		ExpectedVersion: mapper.NewExpectedVersionParam(expectedVersion),
 	})
 	if err != nil {
-		return error.NewSQLError(err.Error())
+		return newSQLError(ctx, repository.logger, err)
 	}
-	return nil
+	return repository.checkVersion(ctx, queries, ID, expectedVersion, updated)
+}
+
+func (repository *toDoRepository) ChangeToDoStatus(ctx context.Context, queries *sqlc.Queries, change model.ToDoStatusChangeEntity) error.InfrastructureError {
+	updated, err := queries.ChangeToDoStatus(ctx, mapper.NewChangeToDoStatusParamsFromEntity(change))
+	if err != nil {
+		return newSQLError(ctx, repository.logger, err)
+	}
+	return repository.checkStatus(ctx, queries, change.ID, change.FromStatuses, change.ExpectedVersion, updated)
+}
+
+func (repository *toDoRepository) RescheduleToDo(ctx context.Context, queries *sqlc.Queries, reschedule model.ToDoRescheduleEntity) error.InfrastructureError {
+	updated, err := queries.RescheduleToDo(ctx, mapper.NewRescheduleToDoParamsFromEntity(reschedule))
+	if err != nil {
+		return newSQLError(ctx, repository.logger, err)
+	}
+	return repository.checkStatus(ctx, queries, reschedule.ID, reschedule.FromStatuses, reschedule.ExpectedVersion, updated)
+}
+
+// checkStatus tells why a statement allowed from some statuses only changed no row: the ToDo does not exist, is at
+// another version or is in a status the statement does not apply to.
+func (repository *toDoRepository) checkStatus(ctx context.Context, queries *sqlc.Queries, ID uuid.UUID, fromStatuses []string, expectedVersion *int64, affected int64) error.InfrastructureError {
+	if affected > 0 {
+		return nil
+	}
+	toDo, err := repository.FindToDoByID(ctx, queries, ID)
+	if err != nil {
+		return err
+	}
+	if expectedVersion != nil && toDo.Version != *expectedVersion {
+		return error.NewVersionMismatchError(fmt.Sprintf("toDo item with ID %s is at version %d, not %d", ID, toDo.Version, *expectedVersion))
+	}
+	return error.NewConflictError(fmt.Sprintf("toDo item with ID %s is %s, expected one of %v", ID, toDo.Status, fromStatuses))
+}
+
+// checkVersion tells why a statement expecting a version changed no row: either the ToDo does not exist or it is at
+// another version. Statements without an expected version keep ignoring missing ToDos.
+func (repository *toDoRepository) checkVersion(ctx context.Context, queries *sqlc.Queries, ID uuid.UUID, expectedVersion *int64, affected int64) error.InfrastructureError {
+	if affected > 0 || expectedVersion == nil {
+		return nil
+	}
+	toDo, err := repository.FindToDoByID(ctx, queries, ID)
+	if err != nil {
+		return err
+	}
+	return error.NewVersionMismatchError(fmt.Sprintf("toDo item with ID %s is at version %d, not %d", ID, toDo.Version, *expectedVersion))
 }
 
 func (repository *toDoRepository) DeleteToDoCategories(ctx context.Context, queries *sqlc.Queries, toDoID uuid.UUID, categories []uuid.UUID) error.InfrastructureError {
@@ -129,7 +232,7 @@ func (repository *toDoRepository) DeleteToDoCategories(ctx context.Context, quer
 			CategoryID: categoryID,
 		})
 		if err != nil {
-			return error.NewSQLError(err.Error())
+			return newSQLError(ctx, repository.logger, err)
 		}
 	}
 	return nil
@@ -142,8 +245,159 @@ func (repository *toDoRepository) AddToDoCategories(ctx context.Context, queries
 			CategoryID: categoryID,
 		})
 		if err != nil {
-			return error.NewSQLError(err.Error())
+			return newSQLError(ctx, repository.logger, err)
 		}
 	}
 	return nil
 }
+
+// CreateToDoItem inserts an item into the checklist of a ToDo, moving down the items from its position. Items without a
+// position, or positioned past the end of the checklist, are appended.
+func (repository *toDoRepository) CreateToDoItem(ctx context.Context, queries *sqlc.Queries, item model.ToDoItemEntity, position *int) error.InfrastructureError {
+	count, err := queries.CountToDoItems(ctx, item.ToDoID)
+	if err != nil {
+		return newSQLError(ctx, repository.logger, err)
+	}
+	item.Position = int(count)
+	if position != nil && *position < item.Position {
+		item.Position = *position
+		err = queries.ShiftToDoItems(ctx, sqlc.ShiftToDoItemsParams{
+			Shift:        1,
+			TodoID:       item.ToDoID,
+			FromPosition: int32(item.Position),
+		})
+		if err != nil {
+			return newSQLError(ctx, repository.logger, err)
+		}
+	}
+	if err = queries.CreateToDoItem(ctx, mapper.NewCreateToDoItemParamsFromEntity(item)); err != nil {
+		return newSQLError(ctx, repository.logger, err)
+	}
+	return nil
+}
+
+func (repository *toDoRepository) UpdateToDoItem(ctx context.Context, queries *sqlc.Queries, update model.ToDoItemUpdateEntity) error.InfrastructureError {
+	updated, err := queries.UpdateToDoItem(ctx, mapper.NewUpdateToDoItemParamsFromEntity(update))
+	if err != nil {
+		return newSQLError(ctx, repository.logger, err)
+	} else if updated == 0 {
+		return error.NewItemNotFoundError(fmt.Sprintf("item with ID %s of toDo item with ID %s not found", update.ID, update.ToDoID))
+	}
+	return nil
+}
+
+// DeleteToDoItem removes an item from the checklist of a ToDo, moving up the items which followed it.
+func (repository *toDoRepository) DeleteToDoItem(ctx context.Context, queries *sqlc.Queries, toDoID uuid.UUID, ID uuid.UUID) error.InfrastructureError {
+	position, err := queries.DeleteToDoItem(ctx, sqlc.DeleteToDoItemParams{
+		ID:     ID,
+		TodoID: toDoID,
+	})
+	if err != nil {
+		if err.Error() == constants.NotFoundErrorMessage {
+			return error.NewItemNotFoundError(fmt.Sprintf("item with ID %s of toDo item with ID %s not found", ID, toDoID))
+		}
+		return newSQLError(ctx, repository.logger, err)
+	}
+	err = queries.ShiftToDoItems(ctx, sqlc.ShiftToDoItemsParams{
+		Shift:        -1,
+		TodoID:       toDoID,
+		FromPosition: position + 1,
+	})
+	if err != nil {
+		return newSQLError(ctx, repository.logger, err)
+	}
+	return nil
+}
+
+// ReorderToDoItems sorts the checklist of a ToDo in the given order, which must list every item of the ToDo once.
+func (repository *toDoRepository) ReorderToDoItems(ctx context.Context, queries *sqlc.Queries, toDoID uuid.UUID, items []uuid.UUID) error.InfrastructureError {
+	current, err := queries.GetToDoItems(ctx, toDoID)
+	if err != nil {
+		return newSQLError(ctx, repository.logger, err)
+	}
+	currentIDs := make([]uuid.UUID, 0, len(current))
+	for _, item := range current {
+		currentIDs = append(currentIDs, item.ID)
+	}
+	if !util.IsPermutation(currentIDs, items) {
+		return error.NewInvalidArgumentError(fmt.Sprintf("items of toDo item with ID %s must be listed once each", toDoID))
+	}
+	err = queries.ReorderToDoItems(ctx, sqlc.ReorderToDoItemsParams{
+		ItemIds: items,
+		TodoID:  toDoID,
+	})
+	if err != nil {
+		return newSQLError(ctx, repository.logger, err)
+	}
+	return nil
+}
+
+func (repository *toDoRepository) FindToDoReminders(ctx context.Context, queries *sqlc.Queries, toDoID uuid.UUID) ([]model.ToDoReminderEntity, error.InfrastructureError) {
+	reminders, err := queries.GetToDoReminders(ctx, toDoID)
+	if err != nil {
+		return nil, newSQLError(ctx, repository.logger, err)
+	}
+	return mapper.NewToDoReminderEntityListFromSQLModelList(reminders), nil
+}
+
+// FindUpcomingToDoReminders returns the unsent reminders of unfinished ToDos to be sent before the given time, the
+// earliest first.
+func (repository *toDoRepository) FindUpcomingToDoReminders(ctx context.Context, queries *sqlc.Queries, remindBefore time.Time, limit int) ([]model.UpcomingToDoReminderEntity, error.InfrastructureError) {
+	reminders, err := queries.GetUpcomingToDoReminders(ctx, sqlc.GetUpcomingToDoRemindersParams{
+		RemindBefore: remindBefore,
+		PageLimit:    int32(limit),
+	})
+	if err != nil {
+		return nil, newSQLError(ctx, repository.logger, err)
+	}
+	return mapper.NewUpcomingToDoReminderEntityListFromSQLModelList(reminders), nil
+}
+
+// CreateToDoReminder adds a reminder to a ToDo, which can only have one reminder for a given time before its due date.
+func (repository *toDoRepository) CreateToDoReminder(ctx context.Context, queries *sqlc.Queries, reminder model.ToDoReminderEntity) error.InfrastructureError {
+	params := mapper.NewCreateToDoReminderParamsFromEntity(reminder)
+	current, err := queries.GetToDoReminders(ctx, reminder.ToDoID)
+	if err != nil {
+		return newSQLError(ctx, repository.logger, err)
+	}
+	for _, existing := range current {
+		if existing.BeforeDueSeconds == params.BeforeDueSeconds {
+			return error.NewConflictError(fmt.Sprintf("toDo item with ID %s already has a reminder %s before its due date",
+				reminder.ToDoID, time.Duration(params.BeforeDueSeconds)*time.Second))
+		}
+	}
+	if err = queries.CreateToDoReminder(ctx, params); err != nil {
+		return newSQLError(ctx, repository.logger, err)
+	}
+	return nil
+}
+
+func (repository *toDoRepository) DeleteToDoReminder(ctx context.Context, queries *sqlc.Queries, toDoID uuid.UUID, ID uuid.UUID) error.InfrastructureError {
+	deleted, err := queries.DeleteToDoReminder(ctx, sqlc.DeleteToDoReminderParams{
+		ID:     ID,
+		TodoID: toDoID,
+	})
+	if err != nil {
+		return newSQLError(ctx, repository.logger, err)
+	} else if deleted == 0 {
+		return error.NewItemNotFoundError(fmt.Sprintf("reminder with ID %s of toDo item with ID %s not found", ID, toDoID))
+	}
+	return nil
+}
+
+// MarkToDoReminderSent records that the reminder was sent for the given due date. Only one caller can mark a reminder
+// for a due date, and only while its ToDo is unfinished and still due then, the others getting a conflict.
+func (repository *toDoRepository) MarkToDoReminderSent(ctx context.Context, queries *sqlc.Queries, ID uuid.UUID, dueAt time.Time) error.InfrastructureError {
+	marked, err := queries.MarkToDoReminderSent(ctx, sqlc.MarkToDoReminderSentParams{
+		DueAt:  dueAt,
+		SentAt: time.Now(),
+		ID:     ID,
+	})
+	if err != nil {
+		return newSQLError(ctx, repository.logger, err)
+	} else if marked == 0 {
+		return error.NewConflictError(fmt.Sprintf("reminder with ID %s was already sent or is not due for %s anymore",
+			ID, dueAt.Format(time.RFC3339)))
+	}
+	return nil
+}
@@ -0,0 +1,165 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"event-bus-demo/infrastructure/database/databasetest"
+	"event-bus-demo/infrastructure/database/model"
+	"event-bus-demo/infrastructure/database/sqlc"
+	"fmt"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+const (
+	categoriesPerToDo = 2
+	itemsPerToDo      = 3
+)
+
+// newToDoListDatabase answers the sqlc queries of a ToDo list page with a fixed page of ToDos, each of them having
+// categoriesPerToDo categories and itemsPerToDo items.
+func newToDoListDatabase(pageSize int) *databasetest.Database {
+	toDoIDs := make([]uuid.UUID, 0, pageSize)
+	for i := 0; i < pageSize; i++ {
+		toDoIDs = append(toDoIDs, uuid.New())
+	}
+	createdAt := time.Date(2022, time.January, 1, 0, 0, 0, 0, time.UTC)
+	return &databasetest.Database{Query: func(query string, _ []driver.NamedValue) (*databasetest.Rows, error) {
+		rows := &databasetest.Rows{}
+		switch name := sqlcQueryName(query); name {
+		case "SearchToDoList":
+			for i, ID := range toDoIDs {
+				rows.Values = append(rows.Values, []driver.Value{ID.String(), fmt.Sprintf("ToDo %d", i), "", createdAt,
+					nil, int64(1), "OPEN", "MEDIUM", nil, nil, nil, nil, int64(0), fmt.Sprintf("%08d", i)})
+			}
+		case "GetToDoListCategories":
+			for _, ID := range toDoIDs {
+				for i := 0; i < categoriesPerToDo; i++ {
+					rows.Values = append(rows.Values, []driver.Value{ID.String(), uuid.NewString(),
+						fmt.Sprintf("Category %d", i)})
+				}
+			}
+		case "GetToDoListItems":
+			for _, ID := range toDoIDs {
+				for i := 0; i < itemsPerToDo; i++ {
+					rows.Values = append(rows.Values, []driver.Value{uuid.NewString(), ID.String(),
+						fmt.Sprintf("Item %d", i), false, int64(i), createdAt, nil})
+				}
+			}
+		default:
+			return nil, fmt.Errorf("unexpected query %s", name)
+		}
+		return rows, nil
+	}}
+}
+
+// queryCounter is the sqlc.DBTX of the tests, counting the queries run by sqlc query name.
+type queryCounter struct {
+	db     sqlc.DBTX
+	mutex  sync.Mutex
+	counts map[string]int
+}
+
+func newQueryCounter(db sqlc.DBTX) *queryCounter {
+	return &queryCounter{db: db, counts: make(map[string]int)}
+}
+
+func (counter *queryCounter) count(query string) {
+	counter.mutex.Lock()
+	counter.counts[sqlcQueryName(query)]++
+	counter.mutex.Unlock()
+}
+
+func (counter *queryCounter) reset() map[string]int {
+	counter.mutex.Lock()
+	defer counter.mutex.Unlock()
+	counts := counter.counts
+	counter.counts = make(map[string]int)
+	return counts
+}
+
+func (counter *queryCounter) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	counter.count(query)
+	return counter.db.ExecContext(ctx, query, args...)
+}
+
+func (counter *queryCounter) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	counter.count(query)
+	return counter.db.PrepareContext(ctx, query)
+}
+
+func (counter *queryCounter) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	counter.count(query)
+	return counter.db.QueryContext(ctx, query, args...)
+}
+
+func (counter *queryCounter) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	counter.count(query)
+	return counter.db.QueryRowContext(ctx, query, args...)
+}
+
+func newToDoListQueries(pageSize int) (*sqlc.Queries, *queryCounter, func()) {
+	db := newToDoListDatabase(pageSize).Open()
+	counter := newQueryCounter(db)
+	return sqlc.New(counter), counter, func() {
+		_ = db.Close()
+	}
+}
+
+var pageSizes = []int{1, 10, 100}
+
+func TestSearchToDoListQueryCountDoesNotGrowWithPageSize(t *testing.T) {
+	repository := NewToDoRepository(zap.NewNop(), nil)
+	expected := map[string]int{"SearchToDoList": 1, "GetToDoListCategories": 1, "GetToDoListItems": 1}
+	for _, pageSize := range pageSizes {
+		t.Run(fmt.Sprintf("%d ToDos", pageSize), func(t *testing.T) {
+			queries, counter, closeDB := newToDoListQueries(pageSize)
+			defer closeDB()
+			results, err := repository.SearchToDoList(context.Background(), queries,
+				model.ToDoSearchEntity{SortField: "created_at", Limit: pageSize})
+			if err != nil {
+				t.Fatalf("unexpected error %s", err.Error())
+			}
+			if counts := counter.reset(); !reflect.DeepEqual(counts, expected) {
+				t.Errorf("expected queries %v, got %v", expected, counts)
+			}
+			if len(results) != pageSize {
+				t.Fatalf("expected %d ToDos, got %d", pageSize, len(results))
+			}
+			for _, result := range results {
+				if len(result.ToDo.Categories) != categoriesPerToDo || len(result.ToDo.Items) != itemsPerToDo {
+					t.Errorf("expected ToDo %s to have %d categories and %d items, got %d and %d", result.ToDo.ID,
+						categoriesPerToDo, itemsPerToDo, len(result.ToDo.Categories), len(result.ToDo.Items))
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkSearchToDoList(b *testing.B) {
+	repository := NewToDoRepository(zap.NewNop(), nil)
+	for _, pageSize := range pageSizes {
+		b.Run(fmt.Sprintf("%d ToDos", pageSize), func(b *testing.B) {
+			queries, counter, closeDB := newToDoListQueries(pageSize)
+			defer closeDB()
+			search := model.ToDoSearchEntity{SortField: "created_at", Limit: pageSize}
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := repository.SearchToDoList(context.Background(), queries, search); err != nil {
+					b.Fatalf("unexpected error %s", err.Error())
+				}
+			}
+			b.StopTimer()
+			total := 0
+			for _, count := range counter.reset() {
+				total += count
+			}
+			b.ReportMetric(float64(total)/float64(b.N), "queries/op")
+		})
+	}
+}
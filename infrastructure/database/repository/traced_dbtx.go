@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"event-bus-demo/infrastructure/database/sqlc"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"go.opentelemetry.io/otel/trace"
+	"strings"
+)
+
+const sqlcQueryNamePrefix = "-- name: "
+
+var tracer = otel.Tracer("event-bus-demo/infrastructure/database/repository")
+
+// tracedDBTX wraps the connection used by sqlc queries so that every executed query is recorded as a span named after
+// the sqlc query it belongs to.
+type tracedDBTX struct {
+	db sqlc.DBTX
+}
+
+func newTracedDBTX(db sqlc.DBTX) sqlc.DBTX {
+	return &tracedDBTX{
+		db: db,
+	}
+}
+
+func (traced *tracedDBTX) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	ctx, span := traced.startSpan(ctx, query)
+	defer span.End()
+	result, err := traced.db.ExecContext(ctx, query, args...)
+	recordSpanError(span, err)
+	return result, err
+}
+
+func (traced *tracedDBTX) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	ctx, span := traced.startSpan(ctx, query)
+	defer span.End()
+	statement, err := traced.db.PrepareContext(ctx, query)
+	recordSpanError(span, err)
+	return statement, err
+}
+
+func (traced *tracedDBTX) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	ctx, span := traced.startSpan(ctx, query)
+	defer span.End()
+	rows, err := traced.db.QueryContext(ctx, query, args...)
+	recordSpanError(span, err)
+	return rows, err
+}
+
+func (traced *tracedDBTX) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	ctx, span := traced.startSpan(ctx, query)
+	defer span.End()
+	row := traced.db.QueryRowContext(ctx, query, args...)
+	recordSpanError(span, row.Err())
+	return row
+}
+
+func (traced *tracedDBTX) startSpan(ctx context.Context, query string) (context.Context, trace.Span) {
+	name := sqlcQueryName(query)
+	return tracer.Start(ctx, "sql "+name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.DBSystemPostgreSQL,
+			semconv.DBOperationKey.String(name),
+			semconv.DBStatementKey.String(query),
+		))
+}
+
+func sqlcQueryName(query string) string {
+	if !strings.HasPrefix(query, sqlcQueryNamePrefix) {
+		return "query"
+	}
+	header := strings.SplitN(strings.TrimPrefix(query, sqlcQueryNamePrefix), "\n", 2)[0]
+	return strings.Fields(header)[0]
+}
+
+func recordSpanError(span trace.Span, err error) {
+	if err != nil && err != sql.ErrNoRows {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+}
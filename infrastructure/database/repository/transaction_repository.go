@@ -6,6 +6,7 @@ import (
 	"event-bus-demo/infrastructure/database/sqlc"
 	"event-bus-demo/infrastructure/error"
 	"go.uber.org/zap"
+	"sync"
 )
 
 type TransactionalRepository interface {
@@ -17,6 +18,7 @@ type TransactionalRepository interface {
 type transactionalRepository struct {
 	db       *sql.DB
 	logger   *zap.Logger
+	mutex    sync.Mutex
 	registry map[*sqlc.Queries]*sql.Tx
 }
 
@@ -31,15 +33,17 @@ func NewTransactionalRepository(logger *zap.Logger, db *sql.DB) TransactionalRep
 func (repo *transactionalRepository) CreateNewTransaction(ctx context.Context) (*sqlc.Queries, error.InfrastructureError) {
 	tx, err := repo.db.BeginTx(ctx, nil)
 	if err != nil {
-		return nil, error.NewSQLError(err.Error())
+		return nil, newSQLError(ctx, repo.logger, err)
 	}
-	queries := sqlc.New(repo.db).WithTx(tx)
+	queries := sqlc.New(newTracedDBTX(tx))
+	repo.mutex.Lock()
 	repo.registry[queries] = tx
+	repo.mutex.Unlock()
 	return queries, nil
 }
 
 func (repo *transactionalRepository) CommitTransaction(queries *sqlc.Queries) error.InfrastructureError {
-	tx := repo.registry[queries]
+	tx := repo.takeTransaction(queries)
 	if tx == nil {
 		return error.NewSQLError("error while getting transaction. Requested transaction not found")
 	}
@@ -51,7 +55,7 @@ func (repo *transactionalRepository) CommitTransaction(queries *sqlc.Queries) er
 }
 
 func (repo *transactionalRepository) RollbackTransaction(queries *sqlc.Queries) error.InfrastructureError {
-	tx := repo.registry[queries]
+	tx := repo.takeTransaction(queries)
 	if tx == nil {
 		return error.NewSQLError("error while getting transaction. Requested transaction not found")
 	}
@@ -61,3 +65,13 @@ func (repo *transactionalRepository) RollbackTransaction(queries *sqlc.Queries)
 	}
 	return nil
 }
+
+// takeTransaction removes the transaction of the queries from the registry, as it is over once committed or rolled
+// back, even when that fails.
+func (repo *transactionalRepository) takeTransaction(queries *sqlc.Queries) *sql.Tx {
+	repo.mutex.Lock()
+	defer repo.mutex.Unlock()
+	tx := repo.registry[queries]
+	delete(repo.registry, queries)
+	return tx
+}
@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"context"
+	"event-bus-demo/infrastructure/database/databasetest"
+	"go.uber.org/zap"
+	"sync"
+	"testing"
+)
+
+func TestTransactionalRepositoryReleasesFinishedTransactions(t *testing.T) {
+	db := (&databasetest.Database{}).Open()
+	defer db.Close()
+	repo := NewTransactionalRepository(zap.NewNop(), db).(*transactionalRepository)
+	var group sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		group.Add(1)
+		go func(commit bool) {
+			defer group.Done()
+			queries, err := repo.CreateNewTransaction(context.Background())
+			if err != nil {
+				t.Errorf("unexpected error %s", err.Error())
+				return
+			}
+			if commit {
+				err = repo.CommitTransaction(queries)
+			} else {
+				err = repo.RollbackTransaction(queries)
+			}
+			if err != nil {
+				t.Errorf("unexpected error %s", err.Error())
+				return
+			}
+			if err = repo.CommitTransaction(queries); err == nil {
+				t.Error("expected a finished transaction not to be committed again")
+			}
+		}(i%2 == 0)
+	}
+	group.Wait()
+	if len(repo.registry) != 0 {
+		t.Errorf("expected the finished transactions to be released, got %d", len(repo.registry))
+	}
+}
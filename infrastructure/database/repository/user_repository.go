@@ -15,6 +15,7 @@ import (
 
 type UserRepository interface {
 	FindUserByID(ctx context.Context, queries *sqlc.Queries, ID uuid.UUID) (model.UserEntity, error.InfrastructureError)
+	FindUserByUsername(ctx context.Context, queries *sqlc.Queries, username string) (model.UserEntity, error.InfrastructureError)
 	CreateUser(ctx context.Context, queries *sqlc.Queries, entity model.UserEntity) error.InfrastructureError
 	DeleteUserByID(ctx context.Context, queries *sqlc.Queries, ID uuid.UUID) error.InfrastructureError
 	UpdateUserPassword(ctx context.Context, queries *sqlc.Queries, entity model.UserEntity) error.InfrastructureError
@@ -36,22 +37,34 @@ func (repo *userRepository) FindUserByID(ctx context.Context, queries *sqlc.Quer
 	user, err := queries.GetUserById(ctx, ID)
 	if err != nil {
 		if err.Error() == constants.NotFoundErrorMessage {
-			return model.UserEntity{}, error.NewItemNotFoundError(fmt.Sprintf("toDo item with ID %s not found", ID))
+			return model.UserEntity{}, error.NewItemNotFoundError(fmt.Sprintf("user with ID %s not found", ID))
 		}
-		return model.UserEntity{}, error.NewSQLError(err.Error())
+		return model.UserEntity{}, newSQLError(ctx, repo.logger, err)
 	}
 
 	return mapper.NewUserEntityFromSQLModel(user), nil
 }
 
+func (repo *userRepository) FindUserByUsername(ctx context.Context, queries *sqlc.Queries, username string) (model.UserEntity, error.InfrastructureError) {
+	user, err := queries.GetUserByUsername(ctx, username)
+	if err != nil {
+		if err.Error() == constants.NotFoundErrorMessage {
+			return model.UserEntity{}, error.NewItemNotFoundError(fmt.Sprintf("user with username %s not found", username))
+		}
+		return model.UserEntity{}, newSQLError(ctx, repo.logger, err)
+	}
+	return mapper.NewUserEntityFromSQLModel(user), nil
+}
+
 func (repo *userRepository) CreateUser(ctx context.Context, queries *sqlc.Queries, entity model.UserEntity) error.InfrastructureError {
 	err := queries.CreateUser(ctx, sqlc.CreateUserParams{
 		ID:       entity.ID,
 		Username: entity.Username,
 		Password: entity.Password,
+		Role:     entity.Role,
 	})
 	if err != nil {
-		return error.NewSQLError(err.Error())
+		return newSQLError(ctx, repo.logger, err)
 	}
 	return nil
 }
@@ -59,7 +72,7 @@ func (repo *userRepository) CreateUser(ctx context.Context, queries *sqlc.Querie
 func (repo *userRepository) DeleteUserByID(ctx context.Context, queries *sqlc.Queries, ID uuid.UUID) error.InfrastructureError {
 	err := queries.DeleteUser(ctx, ID)
 	if err != nil {
-		return error.NewSQLError(err.Error())
+		return newSQLError(ctx, repo.logger, err)
 	}
 	return nil
 }
@@ -70,7 +83,7 @@ func (repo *userRepository) UpdateUserPassword(ctx context.Context, queries *sql
 		Password: entity.Password,
 	})
 	if err != nil {
-		return error.NewSQLError(err.Error())
+		return newSQLError(ctx, repo.logger, err)
 	}
 	return nil
 }
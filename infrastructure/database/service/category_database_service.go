@@ -6,15 +6,19 @@ import (
 	"event-bus-demo/infrastructure/database/mapper"
 	"event-bus-demo/infrastructure/database/repository"
 	"event-bus-demo/infrastructure/error"
+	"fmt"
 	"github.com/google/uuid"
 )
 
 type CategoryDatabaseService interface {
-	GetAllCategories() ([]model.Category, error.InfrastructureError)
-	GetCategory(ID uuid.UUID) (model.Category, error.InfrastructureError)
-	CreateCategory(category model.Category) error.InfrastructureError
-	UpdateCategory(category model.Category) error.InfrastructureError
-	DeleteCategory(ID uuid.UUID) error.InfrastructureError
+	GetAllCategories(ctx context.Context) ([]model.Category, error.InfrastructureError)
+	GetCategory(ctx context.Context, ID uuid.UUID) (model.Category, error.InfrastructureError)
+	CreateCategory(ctx context.Context, category model.Category) error.InfrastructureError
+	UpdateCategory(ctx context.Context, category model.Category, expectedVersion *int64) error.InfrastructureError
+	DeleteCategory(ctx context.Context, ID uuid.UUID, expectedVersion *int64) error.InfrastructureError
+	DeleteCategoryUnlinkingToDos(ctx context.Context, ID uuid.UUID, expectedVersion *int64) error.InfrastructureError
+	DeleteCategoryReassigningToDos(ctx context.Context, ID uuid.UUID, targetID uuid.UUID, expectedVersion *int64) error.InfrastructureError
+	CountCategoryToDos(ctx context.Context, ID uuid.UUID) (int64, error.InfrastructureError)
 }
 
 type categoryDatabaseService struct {
@@ -29,10 +33,9 @@ func NewCategoryDatabaseService(transactionalRepository repository.Transactional
 	}
 }
 
-func (dbService *categoryDatabaseService) GetAllCategories() ([]model.Category, error.InfrastructureError) {
-	ctx := context.Background()
+func (dbService *categoryDatabaseService) GetAllCategories(ctx context.Context) ([]model.Category, error.InfrastructureError) {
 	if queries, err := dbService.transactionalRepository.CreateNewTransaction(ctx); err != nil {
-		return nil, error.NewSQLError(err.Error())
+		return nil, err
 	} else if entities, err := dbService.categoryRepository.FindCategoriesList(ctx, queries); err != nil {
 		_ = dbService.transactionalRepository.RollbackTransaction(queries)
 		return nil, err
@@ -43,10 +46,9 @@ func (dbService *categoryDatabaseService) GetAllCategories() ([]model.Category,
 	}
 }
 
-func (dbService *categoryDatabaseService) GetCategory(ID uuid.UUID) (model.Category, error.InfrastructureError) {
-	ctx := context.Background()
+func (dbService *categoryDatabaseService) GetCategory(ctx context.Context, ID uuid.UUID) (model.Category, error.InfrastructureError) {
 	if queries, err := dbService.transactionalRepository.CreateNewTransaction(ctx); err != nil {
-		return model.Category{}, error.NewSQLError(err.Error())
+		return model.Category{}, err
 	} else if entity, err := dbService.categoryRepository.FindCategoryByID(ctx, queries, ID); err != nil {
 		_ = dbService.transactionalRepository.RollbackTransaction(queries)
 		return model.Category{}, err
@@ -57,11 +59,10 @@ func (dbService *categoryDatabaseService) GetCategory(ID uuid.UUID) (model.Categ
 	}
 }
 
-func (dbService *categoryDatabaseService) CreateCategory(category model.Category) error.InfrastructureError {
+func (dbService *categoryDatabaseService) CreateCategory(ctx context.Context, category model.Category) error.InfrastructureError {
 	entity := mapper.NewCategoryEntityFromCategoryModel(category)
-	ctx := context.Background()
 	if queries, err := dbService.transactionalRepository.CreateNewTransaction(ctx); err != nil {
-		return error.NewSQLError(err.Error())
+		return err
 	} else if err := dbService.categoryRepository.CreateCategory(ctx, queries, entity); err != nil {
 		_ = dbService.transactionalRepository.RollbackTransaction(queries)
 		return err
@@ -71,12 +72,27 @@ func (dbService *categoryDatabaseService) CreateCategory(category model.Category
 	return nil
 }
 
-func (dbService *categoryDatabaseService) UpdateCategory(category model.Category) error.InfrastructureError {
+// UpdateCategory renames the category and increments the version of its ToDos, which embed its name.
+func (dbService *categoryDatabaseService) UpdateCategory(ctx context.Context, category model.Category, expectedVersion *int64) error.InfrastructureError {
 	entity := mapper.NewCategoryEntityFromCategoryModel(category)
-	ctx := context.Background()
 	if queries, err := dbService.transactionalRepository.CreateNewTransaction(ctx); err != nil {
+		return err
+	} else if err := dbService.categoryRepository.BumpCategoryToDoVersions(ctx, queries, category.ID); err != nil {
+		_ = dbService.transactionalRepository.RollbackTransaction(queries)
+		return err
+	} else if err := dbService.categoryRepository.UpdateCategoryName(ctx, queries, entity, expectedVersion); err != nil {
+		_ = dbService.transactionalRepository.RollbackTransaction(queries)
+		return err
+	} else if err = dbService.transactionalRepository.CommitTransaction(queries); err != nil {
 		return error.NewSQLError(err.Error())
-	} else if err := dbService.categoryRepository.UpdateCategoryName(ctx, queries, entity); err != nil {
+	}
+	return nil
+}
+
+func (dbService *categoryDatabaseService) DeleteCategory(ctx context.Context, ID uuid.UUID, expectedVersion *int64) error.InfrastructureError {
+	if queries, err := dbService.transactionalRepository.CreateNewTransaction(ctx); err != nil {
+		return err
+	} else if err := dbService.categoryRepository.DeleteCategoryByID(ctx, queries, ID, expectedVersion); err != nil {
 		_ = dbService.transactionalRepository.RollbackTransaction(queries)
 		return err
 	} else if err = dbService.transactionalRepository.CommitTransaction(queries); err != nil {
@@ -85,11 +101,47 @@ func (dbService *categoryDatabaseService) UpdateCategory(category model.Category
 	return nil
 }
 
-func (dbService *categoryDatabaseService) DeleteCategory(ID uuid.UUID) error.InfrastructureError {
-	ctx := context.Background()
+// DeleteCategoryUnlinkingToDos removes the category from every ToDo, incrementing their version, before deleting it,
+// in the same transaction.
+func (dbService *categoryDatabaseService) DeleteCategoryUnlinkingToDos(ctx context.Context, ID uuid.UUID, expectedVersion *int64) error.InfrastructureError {
 	if queries, err := dbService.transactionalRepository.CreateNewTransaction(ctx); err != nil {
+		return err
+	} else if err := dbService.categoryRepository.BumpCategoryToDoVersions(ctx, queries, ID); err != nil {
+		_ = dbService.transactionalRepository.RollbackTransaction(queries)
+		return err
+	} else if err := dbService.categoryRepository.RemoveCategoryFromAllToDos(ctx, queries, ID); err != nil {
+		_ = dbService.transactionalRepository.RollbackTransaction(queries)
+		return err
+	} else if err := dbService.categoryRepository.DeleteCategoryByID(ctx, queries, ID, expectedVersion); err != nil {
+		_ = dbService.transactionalRepository.RollbackTransaction(queries)
+		return err
+	} else if err = dbService.transactionalRepository.CommitTransaction(queries); err != nil {
 		return error.NewSQLError(err.Error())
-	} else if err := dbService.categoryRepository.DeleteCategoryByID(ctx, queries, ID); err != nil {
+	}
+	return nil
+}
+
+// DeleteCategoryReassigningToDos moves every ToDo of the category to the target category, incrementing their version,
+// before deleting it, in the same transaction.
+func (dbService *categoryDatabaseService) DeleteCategoryReassigningToDos(ctx context.Context, ID uuid.UUID, targetID uuid.UUID, expectedVersion *int64) error.InfrastructureError {
+	if queries, err := dbService.transactionalRepository.CreateNewTransaction(ctx); err != nil {
+		return err
+	} else if _, err := dbService.categoryRepository.FindCategoryByID(ctx, queries, targetID); err != nil {
+		_ = dbService.transactionalRepository.RollbackTransaction(queries)
+		if err.GetCode() == error.ItemNotFound {
+			return error.NewConflictError(fmt.Sprintf("default category with ID %s not found", targetID))
+		}
+		return err
+	} else if err := dbService.categoryRepository.BumpCategoryToDoVersions(ctx, queries, ID); err != nil {
+		_ = dbService.transactionalRepository.RollbackTransaction(queries)
+		return err
+	} else if err := dbService.categoryRepository.ReassignCategoryToDos(ctx, queries, ID, targetID); err != nil {
+		_ = dbService.transactionalRepository.RollbackTransaction(queries)
+		return err
+	} else if err := dbService.categoryRepository.RemoveCategoryFromAllToDos(ctx, queries, ID); err != nil {
+		_ = dbService.transactionalRepository.RollbackTransaction(queries)
+		return err
+	} else if err := dbService.categoryRepository.DeleteCategoryByID(ctx, queries, ID, expectedVersion); err != nil {
 		_ = dbService.transactionalRepository.RollbackTransaction(queries)
 		return err
 	} else if err = dbService.transactionalRepository.CommitTransaction(queries); err != nil {
@@ -97,3 +149,16 @@ func (dbService *categoryDatabaseService) DeleteCategory(ID uuid.UUID) error.Inf
 	}
 	return nil
 }
+
+func (dbService *categoryDatabaseService) CountCategoryToDos(ctx context.Context, ID uuid.UUID) (int64, error.InfrastructureError) {
+	if queries, err := dbService.transactionalRepository.CreateNewTransaction(ctx); err != nil {
+		return 0, err
+	} else if count, err := dbService.categoryRepository.CountCategoryToDos(ctx, queries, ID); err != nil {
+		_ = dbService.transactionalRepository.RollbackTransaction(queries)
+		return 0, err
+	} else if err = dbService.transactionalRepository.CommitTransaction(queries); err != nil {
+		return 0, error.NewSQLError(err.Error())
+	} else {
+		return count, nil
+	}
+}
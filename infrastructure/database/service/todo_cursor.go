@@ -0,0 +1,45 @@
+package service
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"event-bus-demo/domain/model"
+	dbModel "event-bus-demo/infrastructure/database/model"
+	"event-bus-demo/infrastructure/error"
+	"github.com/google/uuid"
+)
+
+// toDoCursor is the position a ToDo page starts from. It keeps the sorting it was made for, since a position in one
+// sort order means nothing in another, and whether it points backwards, towards the previous page.
+type toDoCursor struct {
+	SortKey    string              `json:"k"`
+	ID         uuid.UUID           `json:"i"`
+	SortField  model.ToDoSortField `json:"s"`
+	Descending bool                `json:"d"`
+	Backward   bool                `json:"b"`
+}
+
+func encodeToDoCursor(cursor toDoCursor) string {
+	content, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(content)
+}
+
+func decodeToDoCursor(value string, event model.GetToDoListEvent) (toDoCursor, error.InfrastructureError) {
+	var cursor toDoCursor
+	content, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return toDoCursor{}, error.NewInvalidArgumentError("invalid page cursor")
+	} else if err := json.Unmarshal(content, &cursor); err != nil {
+		return toDoCursor{}, error.NewInvalidArgumentError("invalid page cursor")
+	} else if cursor.SortField != event.SortField || cursor.Descending != event.Descending {
+		return toDoCursor{}, error.NewInvalidArgumentError("page cursor was made for another sorting")
+	}
+	return cursor, nil
+}
+
+func (cursor toDoCursor) toEntity() *dbModel.ToDoCursorEntity {
+	return &dbModel.ToDoCursorEntity{
+		SortKey: cursor.SortKey,
+		ID:      cursor.ID,
+	}
+}
@@ -0,0 +1,202 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"event-bus-demo/domain/model"
+	dbModel "event-bus-demo/infrastructure/database/model"
+	"event-bus-demo/infrastructure/database/repository"
+	"event-bus-demo/infrastructure/database/sqlc"
+	"event-bus-demo/infrastructure/error"
+	"fmt"
+	"github.com/google/uuid"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type fakeTransactionalRepository struct {
+}
+
+func (repository *fakeTransactionalRepository) CreateNewTransaction(context.Context) (*sqlc.Queries, error.InfrastructureError) {
+	return sqlc.New(nil), nil
+}
+
+func (repository *fakeTransactionalRepository) CommitTransaction(*sqlc.Queries) error.InfrastructureError {
+	return nil
+}
+
+func (repository *fakeTransactionalRepository) RollbackTransaction(*sqlc.Queries) error.InfrastructureError {
+	return nil
+}
+
+// fakeToDoListRepository searches an in-memory ToDo list the way SearchToDoList does, the list being sorted by sort key
+// and ID.
+type fakeToDoListRepository struct {
+	repository.ToDoRepository
+	results []dbModel.ToDoSearchResultEntity
+}
+
+func newFakeToDoListRepository(count int) *fakeToDoListRepository {
+	toDoRepository := &fakeToDoListRepository{}
+	for i := 0; i < count; i++ {
+		ID := uuid.MustParse(fmt.Sprintf("00000000-0000-0000-0000-%012d", i))
+		toDoRepository.results = append(toDoRepository.results, dbModel.ToDoSearchResultEntity{
+			ToDo:    dbModel.ToDoEntity{ID: ID, Title: fmt.Sprintf("ToDo %d", i)},
+			SortKey: fmt.Sprintf("key %02d", i),
+		})
+	}
+	return toDoRepository
+}
+
+func (toDoRepository *fakeToDoListRepository) SearchToDoList(_ context.Context, _ *sqlc.Queries,
+	search dbModel.ToDoSearchEntity) ([]dbModel.ToDoSearchResultEntity, error.InfrastructureError) {
+	ordered := make([]dbModel.ToDoSearchResultEntity, 0, len(toDoRepository.results))
+	for i := range toDoRepository.results {
+		result := toDoRepository.results[i]
+		if search.Descending {
+			result = toDoRepository.results[len(toDoRepository.results)-1-i]
+		}
+		if search.After != nil {
+			position := strings.Compare(result.SortKey+result.ToDo.ID.String(), search.After.SortKey+search.After.ID.String())
+			if (search.Descending && position >= 0) || (!search.Descending && position <= 0) {
+				continue
+			}
+		}
+		ordered = append(ordered, result)
+	}
+	if search.Offset > len(ordered) {
+		return nil, nil
+	}
+	ordered = ordered[search.Offset:]
+	if len(ordered) > search.Limit {
+		ordered = ordered[:search.Limit]
+	}
+	return ordered, nil
+}
+
+func titles(page model.ToDoPage) []string {
+	result := make([]string, 0, len(page.ToDos))
+	for _, toDo := range page.ToDos {
+		result = append(result, toDo.Title)
+	}
+	return result
+}
+
+func TestToDoCursorRoundTrip(t *testing.T) {
+	event := model.GetToDoListEvent{SortField: model.SortToDoByTitle, Descending: true}
+	cursor := toDoCursor{SortKey: "Buy milk", ID: uuid.New(), SortField: model.SortToDoByTitle, Descending: true,
+		Backward: true}
+	encoded := encodeToDoCursor(cursor)
+	if strings.ContainsAny(encoded, "+/=") {
+		t.Errorf("expected a URL safe cursor, got %s", encoded)
+	}
+	decoded, err := decodeToDoCursor(encoded, event)
+	if err != nil {
+		t.Fatalf("unexpected error %s", err.Error())
+	} else if decoded != cursor {
+		t.Errorf("expected %+v, got %+v", cursor, decoded)
+	}
+}
+
+func TestDecodeToDoCursorRejectsTamperedCursors(t *testing.T) {
+	event := model.GetToDoListEvent{SortField: model.SortToDoByCreatedAt}
+	valid := encodeToDoCursor(toDoCursor{SortKey: "2022-01-01", ID: uuid.New(), SortField: model.SortToDoByCreatedAt})
+	tests := []struct {
+		name     string
+		cursor   string
+		expected string
+	}{
+		{
+			name:     "not base64",
+			cursor:   "not a cursor!",
+			expected: "invalid page cursor",
+		},
+		{
+			name:     "truncated",
+			cursor:   valid[:len(valid)-3],
+			expected: "invalid page cursor",
+		},
+		{
+			name:     "not json",
+			cursor:   base64.RawURLEncoding.EncodeToString([]byte("key=value")),
+			expected: "invalid page cursor",
+		},
+		{
+			name:     "invalid ID",
+			cursor:   base64.RawURLEncoding.EncodeToString([]byte(`{"k":"2022","i":"42","s":"created_at"}`)),
+			expected: "invalid page cursor",
+		},
+		{
+			name:     "other sort field",
+			cursor:   encodeToDoCursor(toDoCursor{SortKey: "Buy milk", ID: uuid.New(), SortField: model.SortToDoByTitle}),
+			expected: "page cursor was made for another sorting",
+		},
+		{
+			name: "other direction",
+			cursor: encodeToDoCursor(toDoCursor{SortKey: "2022-01-01", ID: uuid.New(),
+				SortField: model.SortToDoByCreatedAt, Descending: true}),
+			expected: "page cursor was made for another sorting",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cursor, err := decodeToDoCursor(test.cursor, event)
+			if err == nil {
+				t.Fatalf("expected an error, got %+v", cursor)
+			} else if err.GetCode() != error.InvalidArgument || err.GetMessage() != test.expected {
+				t.Errorf("expected an invalid argument error %q, got %q", test.expected, err.GetMessage())
+			}
+		})
+	}
+}
+
+func TestGetToDoPagePagesForwardAndBackward(t *testing.T) {
+	for _, descending := range []bool{false, true} {
+		t.Run(fmt.Sprintf("descending %t", descending), func(t *testing.T) {
+			dbService := NewToDoDatabaseService(&fakeTransactionalRepository{}, newFakeToDoListRepository(7))
+			event := model.GetToDoListEvent{SortField: model.SortToDoByCreatedAt, Descending: descending,
+				Page: model.ToDoPageRequest{Limit: 3}}
+			expected := [][]string{
+				{"ToDo 0", "ToDo 1", "ToDo 2"},
+				{"ToDo 3", "ToDo 4", "ToDo 5"},
+				{"ToDo 6"},
+			}
+			if descending {
+				expected = [][]string{
+					{"ToDo 6", "ToDo 5", "ToDo 4"},
+					{"ToDo 3", "ToDo 2", "ToDo 1"},
+					{"ToDo 0"},
+				}
+			}
+			pages := make([]model.ToDoPage, 0)
+			for i := range expected {
+				page, err := dbService.GetToDoPage(context.Background(), event)
+				if err != nil {
+					t.Fatalf("unexpected error %s", err.Error())
+				} else if !reflect.DeepEqual(titles(page), expected[i]) {
+					t.Fatalf("expected page %d to be %v, got %v", i+1, expected[i], titles(page))
+				} else if page.HasNext != (i < len(expected)-1) || page.HasPrevious != (i > 0) {
+					t.Errorf("expected page %d to have next %t and previous %t, got %t and %t", i+1,
+						i < len(expected)-1, i > 0, page.HasNext, page.HasPrevious)
+				}
+				pages = append(pages, page)
+				event.Page.Cursor = page.NextCursor
+			}
+			for i := len(expected) - 2; i >= 0; i-- {
+				event.Page.Cursor = pages[i+1].PreviousCursor
+				page, err := dbService.GetToDoPage(context.Background(), event)
+				if err != nil {
+					t.Fatalf("unexpected error %s", err.Error())
+				} else if !reflect.DeepEqual(titles(page), expected[i]) {
+					t.Fatalf("expected previous page %d to be %v, got %v", i+1, expected[i], titles(page))
+				} else if !page.HasNext || page.HasPrevious != (i > 0) {
+					t.Errorf("expected previous page %d to have next and previous %t, got %t and %t", i+1, i > 0,
+						page.HasNext, page.HasPrevious)
+				} else if page.NextCursor != pages[i].NextCursor || page.PreviousCursor != pages[i].PreviousCursor {
+					t.Errorf("expected previous page %d to have the cursors it had going forward", i+1)
+				}
+			}
+		})
+	}
+}
@@ -4,19 +4,33 @@ import (
 	"context"
 	"event-bus-demo/domain/model"
 	"event-bus-demo/infrastructure/database/mapper"
+	dbModel "event-bus-demo/infrastructure/database/model"
 	"event-bus-demo/infrastructure/database/repository"
 	"event-bus-demo/infrastructure/error"
 	"github.com/google/uuid"
+	"time"
 )
 
 type ToDoDatabaseService interface {
-	GetAllToDoList() ([]model.ToDo, error.InfrastructureError)
-	GetToDo(ID uuid.UUID) (model.ToDo, error.InfrastructureError)
-	CreateToDo(toDo model.ToDo) error.InfrastructureError
-	UpdateToDo(toDo model.ToDo) error.InfrastructureError
-	DeleteToDo(ID uuid.UUID) error.InfrastructureError
-	AddCategoriesIntoToDo(event model.AddCategoriesFromToDoEvent) error.InfrastructureError
-	RemoveCategoriesFromToDo(event model.RemoveCategoriesFromToDoEvent) error.InfrastructureError
+	GetToDoPage(ctx context.Context, event model.GetToDoListEvent) (model.ToDoPage, error.InfrastructureError)
+	GetToDo(ctx context.Context, ID uuid.UUID) (model.ToDo, error.InfrastructureError)
+	SearchToDoText(ctx context.Context, query string, limit int) ([]model.ToDoSearchHit, error.InfrastructureError)
+	CreateToDo(ctx context.Context, toDo model.ToDo) error.InfrastructureError
+	UpdateToDo(ctx context.Context, event model.UpdateToDoEvent) error.InfrastructureError
+	DeleteToDo(ctx context.Context, ID uuid.UUID, expectedVersion *int64) error.InfrastructureError
+	ChangeToDoStatus(ctx context.Context, change model.ToDoStatusChange) error.InfrastructureError
+	RescheduleToDo(ctx context.Context, event model.RescheduleToDoEvent) error.InfrastructureError
+	AddCategoriesIntoToDo(ctx context.Context, event model.AddCategoriesFromToDoEvent) error.InfrastructureError
+	RemoveCategoriesFromToDo(ctx context.Context, event model.RemoveCategoriesFromToDoEvent) error.InfrastructureError
+	CreateToDoItem(ctx context.Context, event model.CreateToDoItemEvent) error.InfrastructureError
+	UpdateToDoItem(ctx context.Context, event model.UpdateToDoItemEvent) error.InfrastructureError
+	DeleteToDoItem(ctx context.Context, event model.DeleteToDoItemEvent) error.InfrastructureError
+	ReorderToDoItems(ctx context.Context, event model.ReorderToDoItemsEvent) error.InfrastructureError
+	GetToDoReminders(ctx context.Context, toDoID uuid.UUID) ([]model.ToDoReminder, error.InfrastructureError)
+	GetUpcomingToDoReminders(ctx context.Context, remindBefore time.Time, limit int) ([]model.UpcomingToDoReminder, error.InfrastructureError)
+	CreateToDoReminder(ctx context.Context, event model.CreateToDoReminderEvent) error.InfrastructureError
+	DeleteToDoReminder(ctx context.Context, event model.DeleteToDoReminderEvent) error.InfrastructureError
+	MarkToDoReminderSent(ctx context.Context, event model.ToDoReminderDueEvent) error.InfrastructureError
 }
 
 type toDoDatabaseService struct {
@@ -31,24 +45,75 @@ func NewToDoDatabaseService(transactionalRepository repository.TransactionalRepo
 	}
 }
 
-func (dbService *toDoDatabaseService) GetAllToDoList() ([]model.ToDo, error.InfrastructureError) {
-	ctx := context.Background()
+// GetToDoPage returns a page of the filtered and sorted ToDo list. Pages requested by cursor are found by keyset,
+// going through the list in reverse order for previous pages, while other pages skip the requested offset.
+func (dbService *toDoDatabaseService) GetToDoPage(ctx context.Context, event model.GetToDoListEvent) (model.ToDoPage, error.InfrastructureError) {
+	search := mapper.NewToDoSearchEntityFromEvent(event)
+	// One more ToDo than needed is fetched to know whether the list goes on after the page
+	search.Limit = event.Page.Limit + 1
+	var cursor toDoCursor
+	if event.Page.Cursor != "" {
+		var err error.InfrastructureError
+		if cursor, err = decodeToDoCursor(event.Page.Cursor, event); err != nil {
+			return model.ToDoPage{}, err
+		}
+		search.After = cursor.toEntity()
+		search.Descending = event.Descending != cursor.Backward
+		search.Offset = 0
+	}
+	var results []dbModel.ToDoSearchResultEntity
 	if queries, err := dbService.transactionalRepository.CreateNewTransaction(ctx); err != nil {
-		return nil, error.NewSQLError(err.Error())
-	} else if entities, err := dbService.toDoRepository.FindToDoList(ctx, queries); err != nil {
+		return model.ToDoPage{}, err
+	} else if results, err = dbService.toDoRepository.SearchToDoList(ctx, queries, search); err != nil {
+		_ = dbService.transactionalRepository.RollbackTransaction(queries)
+		return model.ToDoPage{}, err
+	} else if err = dbService.transactionalRepository.CommitTransaction(queries); err != nil {
+		return model.ToDoPage{}, error.NewSQLError(err.Error())
+	}
+	hasMore := len(results) > event.Page.Limit
+	if hasMore {
+		results = results[:event.Page.Limit]
+	}
+	page := model.ToDoPage{
+		HasNext:     hasMore,
+		HasPrevious: event.Page.Cursor != "" || event.Page.Offset > 0,
+	}
+	if cursor.Backward {
+		for i, j := 0, len(results)-1; i < j; i, j = i+1, j-1 {
+			results[i], results[j] = results[j], results[i]
+		}
+		page.HasNext, page.HasPrevious = true, hasMore
+	}
+	page.ToDos = make([]model.ToDo, 0, len(results))
+	for _, result := range results {
+		page.ToDos = append(page.ToDos, mapper.NewToDoFromEntity(result.ToDo))
+	}
+	if len(results) > 0 {
+		first, last := results[0], results[len(results)-1]
+		page.PreviousCursor = encodeToDoCursor(toDoCursor{SortKey: first.SortKey, ID: first.ToDo.ID,
+			SortField: event.SortField, Descending: event.Descending, Backward: true})
+		page.NextCursor = encodeToDoCursor(toDoCursor{SortKey: last.SortKey, ID: last.ToDo.ID,
+			SortField: event.SortField, Descending: event.Descending})
+	}
+	return page, nil
+}
+
+func (dbService *toDoDatabaseService) SearchToDoText(ctx context.Context, query string, limit int) ([]model.ToDoSearchHit, error.InfrastructureError) {
+	if queries, err := dbService.transactionalRepository.CreateNewTransaction(ctx); err != nil {
+		return nil, err
+	} else if entities, err := dbService.toDoRepository.SearchToDoText(ctx, queries, query, limit); err != nil {
 		_ = dbService.transactionalRepository.RollbackTransaction(queries)
 		return nil, err
 	} else if err = dbService.transactionalRepository.CommitTransaction(queries); err != nil {
 		return nil, error.NewSQLError(err.Error())
 	} else {
-		return mapper.NewToDoListFromEntityList(entities), nil
+		return mapper.NewToDoSearchHitListFromEntityList(entities), nil
 	}
 }
 
-func (dbService *toDoDatabaseService) GetToDo(ID uuid.UUID) (model.ToDo, error.InfrastructureError) {
-	ctx := context.Background()
+func (dbService *toDoDatabaseService) GetToDo(ctx context.Context, ID uuid.UUID) (model.ToDo, error.InfrastructureError) {
 	if queries, err := dbService.transactionalRepository.CreateNewTransaction(ctx); err != nil {
-		return model.ToDo{}, error.NewSQLError(err.Error())
+		return model.ToDo{}, err
 	} else if entity, err := dbService.toDoRepository.FindToDoByID(ctx, queries, ID); err != nil {
 		_ = dbService.transactionalRepository.RollbackTransaction(queries)
 		return model.ToDo{}, err
@@ -59,11 +124,10 @@ func (dbService *toDoDatabaseService) GetToDo(ID uuid.UUID) (model.ToDo, error.I
 	}
 }
 
-func (dbService *toDoDatabaseService) CreateToDo(toDo model.ToDo) error.InfrastructureError {
+func (dbService *toDoDatabaseService) CreateToDo(ctx context.Context, toDo model.ToDo) error.InfrastructureError {
 	entity := mapper.NewToDoEntityFromToDoModel(toDo)
-	ctx := context.Background()
 	if queries, err := dbService.transactionalRepository.CreateNewTransaction(ctx); err != nil {
-		return error.NewSQLError(err.Error())
+		return err
 	} else if err := dbService.toDoRepository.CreateToDo(ctx, queries, entity); err != nil {
 		_ = dbService.transactionalRepository.RollbackTransaction(queries)
 		return err
@@ -73,12 +137,13 @@ func (dbService *toDoDatabaseService) CreateToDo(toDo model.ToDo) error.Infrastr
 	return nil
 }
 
-func (dbService *toDoDatabaseService) UpdateToDo(toDo model.ToDo) error.InfrastructureError {
-	entity := mapper.NewToDoEntityFromToDoModel(toDo)
-	ctx := context.Background()
+// UpdateToDo changes the fields of a ToDo listed by the event. When the event expects a version, ToDos at another
+// version are left unchanged and a version mismatch is returned.
+func (dbService *toDoDatabaseService) UpdateToDo(ctx context.Context, event model.UpdateToDoEvent) error.InfrastructureError {
+	update := mapper.NewToDoUpdateEntityFromEvent(event)
 	if queries, err := dbService.transactionalRepository.CreateNewTransaction(ctx); err != nil {
-		return error.NewSQLError(err.Error())
-	} else if err := dbService.toDoRepository.UpdateToDoInformation(ctx, queries, entity); err != nil {
+		return err
+	} else if err := dbService.toDoRepository.UpdateToDoInformation(ctx, queries, update); err != nil {
 		_ = dbService.transactionalRepository.RollbackTransaction(queries)
 		return err
 	} else if err = dbService.transactionalRepository.CommitTransaction(queries); err != nil {
@@ -87,11 +152,24 @@ func (dbService *toDoDatabaseService) UpdateToDo(toDo model.ToDo) error.Infrastr
 	return nil
 }
 
-func (dbService *toDoDatabaseService) DeleteToDo(ID uuid.UUID) error.InfrastructureError {
-	ctx := context.Background()
+func (dbService *toDoDatabaseService) DeleteToDo(ctx context.Context, ID uuid.UUID, expectedVersion *int64) error.InfrastructureError {
 	if queries, err := dbService.transactionalRepository.CreateNewTransaction(ctx); err != nil {
+		return err
+	} else if err := dbService.toDoRepository.DeleteToDoByID(ctx, queries, ID, expectedVersion); err != nil {
+		_ = dbService.transactionalRepository.RollbackTransaction(queries)
+		return err
+	} else if err = dbService.transactionalRepository.CommitTransaction(queries); err != nil {
 		return error.NewSQLError(err.Error())
-	} else if err := dbService.toDoRepository.DeleteToDoByID(ctx, queries, ID); err != nil {
+	}
+	return nil
+}
+
+// ChangeToDoStatus moves a ToDo to another status, provided the transition is allowed from its current status.
+func (dbService *toDoDatabaseService) ChangeToDoStatus(ctx context.Context, change model.ToDoStatusChange) error.InfrastructureError {
+	entity := mapper.NewToDoStatusChangeEntityFromModel(change)
+	if queries, err := dbService.transactionalRepository.CreateNewTransaction(ctx); err != nil {
+		return err
+	} else if err := dbService.toDoRepository.ChangeToDoStatus(ctx, queries, entity); err != nil {
 		_ = dbService.transactionalRepository.RollbackTransaction(queries)
 		return err
 	} else if err = dbService.transactionalRepository.CommitTransaction(queries); err != nil {
@@ -100,10 +178,26 @@ func (dbService *toDoDatabaseService) DeleteToDo(ID uuid.UUID) error.Infrastruct
 	return nil
 }
 
-func (dbService *toDoDatabaseService) AddCategoriesIntoToDo(event model.AddCategoriesFromToDoEvent) error.InfrastructureError {
-	ctx := context.Background()
+// RescheduleToDo changes the due date of a ToDo, provided it is not done or cancelled.
+func (dbService *toDoDatabaseService) RescheduleToDo(ctx context.Context, event model.RescheduleToDoEvent) error.InfrastructureError {
+	entity := mapper.NewToDoRescheduleEntityFromEvent(event)
 	if queries, err := dbService.transactionalRepository.CreateNewTransaction(ctx); err != nil {
+		return err
+	} else if err := dbService.toDoRepository.RescheduleToDo(ctx, queries, entity); err != nil {
+		_ = dbService.transactionalRepository.RollbackTransaction(queries)
+		return err
+	} else if err = dbService.transactionalRepository.CommitTransaction(queries); err != nil {
 		return error.NewSQLError(err.Error())
+	}
+	return nil
+}
+
+func (dbService *toDoDatabaseService) AddCategoriesIntoToDo(ctx context.Context, event model.AddCategoriesFromToDoEvent) error.InfrastructureError {
+	if queries, err := dbService.transactionalRepository.CreateNewTransaction(ctx); err != nil {
+		return err
+	} else if err := dbService.toDoRepository.BumpToDoVersion(ctx, queries, event.ToDoID, event.ExpectedVersion); err != nil {
+		_ = dbService.transactionalRepository.RollbackTransaction(queries)
+		return err
 	} else if err := dbService.toDoRepository.AddToDoCategories(ctx, queries, event.ToDoID, event.Categories); err != nil {
 		_ = dbService.transactionalRepository.RollbackTransaction(queries)
 		return err
@@ -113,10 +207,12 @@ func (dbService *toDoDatabaseService) AddCategoriesIntoToDo(event model.AddCateg
 	return nil
 }
 
-func (dbService *toDoDatabaseService) RemoveCategoriesFromToDo(event model.RemoveCategoriesFromToDoEvent) error.InfrastructureError {
-	ctx := context.Background()
+func (dbService *toDoDatabaseService) RemoveCategoriesFromToDo(ctx context.Context, event model.RemoveCategoriesFromToDoEvent) error.InfrastructureError {
 	if queries, err := dbService.transactionalRepository.CreateNewTransaction(ctx); err != nil {
-		return error.NewSQLError(err.Error())
+		return err
+	} else if err := dbService.toDoRepository.BumpToDoVersion(ctx, queries, event.ToDoID, event.ExpectedVersion); err != nil {
+		_ = dbService.transactionalRepository.RollbackTransaction(queries)
+		return err
 	} else if err := dbService.toDoRepository.DeleteToDoCategories(ctx, queries, event.ToDoID, event.Categories); err != nil {
 		_ = dbService.transactionalRepository.RollbackTransaction(queries)
 		return err
@@ -125,3 +221,133 @@ func (dbService *toDoDatabaseService) RemoveCategoriesFromToDo(event model.Remov
 	}
 	return nil
 }
+
+// CreateToDoItem adds an item to the checklist of a ToDo. As with categories, every change of the checklist increments
+// the version of the ToDo it belongs to.
+func (dbService *toDoDatabaseService) CreateToDoItem(ctx context.Context, event model.CreateToDoItemEvent) error.InfrastructureError {
+	item := mapper.NewToDoItemEntityFromEvent(event)
+	if queries, err := dbService.transactionalRepository.CreateNewTransaction(ctx); err != nil {
+		return err
+	} else if err := dbService.toDoRepository.BumpToDoVersion(ctx, queries, event.ToDoID, event.ExpectedVersion); err != nil {
+		_ = dbService.transactionalRepository.RollbackTransaction(queries)
+		return err
+	} else if err := dbService.toDoRepository.CreateToDoItem(ctx, queries, item, event.Position); err != nil {
+		_ = dbService.transactionalRepository.RollbackTransaction(queries)
+		return err
+	} else if err = dbService.transactionalRepository.CommitTransaction(queries); err != nil {
+		return error.NewSQLError(err.Error())
+	}
+	return nil
+}
+
+func (dbService *toDoDatabaseService) UpdateToDoItem(ctx context.Context, event model.UpdateToDoItemEvent) error.InfrastructureError {
+	update := mapper.NewToDoItemUpdateEntityFromEvent(event)
+	if queries, err := dbService.transactionalRepository.CreateNewTransaction(ctx); err != nil {
+		return err
+	} else if err := dbService.toDoRepository.BumpToDoVersion(ctx, queries, event.ToDoID, event.ExpectedVersion); err != nil {
+		_ = dbService.transactionalRepository.RollbackTransaction(queries)
+		return err
+	} else if err := dbService.toDoRepository.UpdateToDoItem(ctx, queries, update); err != nil {
+		_ = dbService.transactionalRepository.RollbackTransaction(queries)
+		return err
+	} else if err = dbService.transactionalRepository.CommitTransaction(queries); err != nil {
+		return error.NewSQLError(err.Error())
+	}
+	return nil
+}
+
+func (dbService *toDoDatabaseService) DeleteToDoItem(ctx context.Context, event model.DeleteToDoItemEvent) error.InfrastructureError {
+	if queries, err := dbService.transactionalRepository.CreateNewTransaction(ctx); err != nil {
+		return err
+	} else if err := dbService.toDoRepository.BumpToDoVersion(ctx, queries, event.ToDoID, event.ExpectedVersion); err != nil {
+		_ = dbService.transactionalRepository.RollbackTransaction(queries)
+		return err
+	} else if err := dbService.toDoRepository.DeleteToDoItem(ctx, queries, event.ToDoID, event.ID); err != nil {
+		_ = dbService.transactionalRepository.RollbackTransaction(queries)
+		return err
+	} else if err = dbService.transactionalRepository.CommitTransaction(queries); err != nil {
+		return error.NewSQLError(err.Error())
+	}
+	return nil
+}
+
+func (dbService *toDoDatabaseService) ReorderToDoItems(ctx context.Context, event model.ReorderToDoItemsEvent) error.InfrastructureError {
+	if queries, err := dbService.transactionalRepository.CreateNewTransaction(ctx); err != nil {
+		return err
+	} else if err := dbService.toDoRepository.BumpToDoVersion(ctx, queries, event.ToDoID, event.ExpectedVersion); err != nil {
+		_ = dbService.transactionalRepository.RollbackTransaction(queries)
+		return err
+	} else if err := dbService.toDoRepository.ReorderToDoItems(ctx, queries, event.ToDoID, event.Items); err != nil {
+		_ = dbService.transactionalRepository.RollbackTransaction(queries)
+		return err
+	} else if err = dbService.transactionalRepository.CommitTransaction(queries); err != nil {
+		return error.NewSQLError(err.Error())
+	}
+	return nil
+}
+
+func (dbService *toDoDatabaseService) GetToDoReminders(ctx context.Context, toDoID uuid.UUID) ([]model.ToDoReminder, error.InfrastructureError) {
+	if queries, err := dbService.transactionalRepository.CreateNewTransaction(ctx); err != nil {
+		return nil, err
+	} else if entities, err := dbService.toDoRepository.FindToDoReminders(ctx, queries, toDoID); err != nil {
+		_ = dbService.transactionalRepository.RollbackTransaction(queries)
+		return nil, err
+	} else if err = dbService.transactionalRepository.CommitTransaction(queries); err != nil {
+		return nil, error.NewSQLError(err.Error())
+	} else {
+		return mapper.NewToDoReminderListFromEntityList(entities), nil
+	}
+}
+
+func (dbService *toDoDatabaseService) GetUpcomingToDoReminders(ctx context.Context, remindBefore time.Time, limit int) ([]model.UpcomingToDoReminder, error.InfrastructureError) {
+	if queries, err := dbService.transactionalRepository.CreateNewTransaction(ctx); err != nil {
+		return nil, err
+	} else if entities, err := dbService.toDoRepository.FindUpcomingToDoReminders(ctx, queries, remindBefore, limit); err != nil {
+		_ = dbService.transactionalRepository.RollbackTransaction(queries)
+		return nil, err
+	} else if err = dbService.transactionalRepository.CommitTransaction(queries); err != nil {
+		return nil, error.NewSQLError(err.Error())
+	} else {
+		return mapper.NewUpcomingToDoReminderListFromEntityList(entities), nil
+	}
+}
+
+func (dbService *toDoDatabaseService) CreateToDoReminder(ctx context.Context, event model.CreateToDoReminderEvent) error.InfrastructureError {
+	reminder := mapper.NewToDoReminderEntityFromEvent(event)
+	if queries, err := dbService.transactionalRepository.CreateNewTransaction(ctx); err != nil {
+		return err
+	} else if _, err := dbService.toDoRepository.FindToDoByID(ctx, queries, event.ToDoID); err != nil {
+		_ = dbService.transactionalRepository.RollbackTransaction(queries)
+		return err
+	} else if err := dbService.toDoRepository.CreateToDoReminder(ctx, queries, reminder); err != nil {
+		_ = dbService.transactionalRepository.RollbackTransaction(queries)
+		return err
+	} else if err = dbService.transactionalRepository.CommitTransaction(queries); err != nil {
+		return error.NewSQLError(err.Error())
+	}
+	return nil
+}
+
+func (dbService *toDoDatabaseService) DeleteToDoReminder(ctx context.Context, event model.DeleteToDoReminderEvent) error.InfrastructureError {
+	if queries, err := dbService.transactionalRepository.CreateNewTransaction(ctx); err != nil {
+		return err
+	} else if err := dbService.toDoRepository.DeleteToDoReminder(ctx, queries, event.ToDoID, event.ID); err != nil {
+		_ = dbService.transactionalRepository.RollbackTransaction(queries)
+		return err
+	} else if err = dbService.transactionalRepository.CommitTransaction(queries); err != nil {
+		return error.NewSQLError(err.Error())
+	}
+	return nil
+}
+
+func (dbService *toDoDatabaseService) MarkToDoReminderSent(ctx context.Context, event model.ToDoReminderDueEvent) error.InfrastructureError {
+	if queries, err := dbService.transactionalRepository.CreateNewTransaction(ctx); err != nil {
+		return err
+	} else if err := dbService.toDoRepository.MarkToDoReminderSent(ctx, queries, event.ID, event.DueAt); err != nil {
+		_ = dbService.transactionalRepository.RollbackTransaction(queries)
+		return err
+	} else if err = dbService.transactionalRepository.CommitTransaction(queries); err != nil {
+		return error.NewSQLError(err.Error())
+	}
+	return nil
+}
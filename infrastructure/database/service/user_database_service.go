@@ -10,10 +10,11 @@ import (
 )
 
 type UserDatabaseService interface {
-	GetUser(ID uuid.UUID) (model.User, error.InfrastructureError)
-	CreateUser(user model.User) error.InfrastructureError
-	UpdateUserPassword(user model.User) error.InfrastructureError
-	DeleteUser(ID uuid.UUID) error.InfrastructureError
+	GetUser(ctx context.Context, ID uuid.UUID) (model.User, error.InfrastructureError)
+	GetUserByUsername(ctx context.Context, username string) (model.User, error.InfrastructureError)
+	CreateUser(ctx context.Context, user model.User) error.InfrastructureError
+	UpdateUserPassword(ctx context.Context, user model.User) error.InfrastructureError
+	DeleteUser(ctx context.Context, ID uuid.UUID) error.InfrastructureError
 }
 
 type userDatabaseService struct {
@@ -28,10 +29,9 @@ func NewUserDatabaseService(transactionalRepository repository.TransactionalRepo
 	}
 }
 
-func (dbService *userDatabaseService) GetUser(ID uuid.UUID) (model.User, error.InfrastructureError) {
-	ctx := context.Background()
+func (dbService *userDatabaseService) GetUser(ctx context.Context, ID uuid.UUID) (model.User, error.InfrastructureError) {
 	if queries, err := dbService.transactionalRepository.CreateNewTransaction(ctx); err != nil {
-		return model.User{}, error.NewSQLError(err.Error())
+		return model.User{}, err
 	} else if entity, err := dbService.userRepository.FindUserByID(ctx, queries, ID); err != nil {
 		_ = dbService.transactionalRepository.RollbackTransaction(queries)
 		return model.User{}, err
@@ -42,11 +42,23 @@ func (dbService *userDatabaseService) GetUser(ID uuid.UUID) (model.User, error.I
 	}
 }
 
-func (dbService *userDatabaseService) CreateUser(user model.User) error.InfrastructureError {
-	ctx := context.Background()
+func (dbService *userDatabaseService) GetUserByUsername(ctx context.Context, username string) (model.User, error.InfrastructureError) {
+	if queries, err := dbService.transactionalRepository.CreateNewTransaction(ctx); err != nil {
+		return model.User{}, err
+	} else if entity, err := dbService.userRepository.FindUserByUsername(ctx, queries, username); err != nil {
+		_ = dbService.transactionalRepository.RollbackTransaction(queries)
+		return model.User{}, err
+	} else if err = dbService.transactionalRepository.CommitTransaction(queries); err != nil {
+		return model.User{}, error.NewSQLError(err.Error())
+	} else {
+		return mapper.NewUserFromEntity(entity), nil
+	}
+}
+
+func (dbService *userDatabaseService) CreateUser(ctx context.Context, user model.User) error.InfrastructureError {
 	entity := mapper.NewUserEntityFromUserModel(user)
 	if queries, err := dbService.transactionalRepository.CreateNewTransaction(ctx); err != nil {
-		return error.NewSQLError(err.Error())
+		return err
 	} else if err := dbService.userRepository.CreateUser(ctx, queries, entity); err != nil {
 		_ = dbService.transactionalRepository.RollbackTransaction(queries)
 		return err
@@ -56,11 +68,10 @@ func (dbService *userDatabaseService) CreateUser(user model.User) error.Infrastr
 	return nil
 }
 
-func (dbService *userDatabaseService) UpdateUserPassword(user model.User) error.InfrastructureError {
-	ctx := context.Background()
+func (dbService *userDatabaseService) UpdateUserPassword(ctx context.Context, user model.User) error.InfrastructureError {
 	entity := mapper.NewUserEntityFromUserModel(user)
 	if queries, err := dbService.transactionalRepository.CreateNewTransaction(ctx); err != nil {
-		return error.NewSQLError(err.Error())
+		return err
 	} else if err := dbService.userRepository.UpdateUserPassword(ctx, queries, entity); err != nil {
 		_ = dbService.transactionalRepository.RollbackTransaction(queries)
 		return err
@@ -70,10 +81,9 @@ func (dbService *userDatabaseService) UpdateUserPassword(user model.User) error.
 	return nil
 }
 
-func (dbService *userDatabaseService) DeleteUser(ID uuid.UUID) error.InfrastructureError {
-	ctx := context.Background()
+func (dbService *userDatabaseService) DeleteUser(ctx context.Context, ID uuid.UUID) error.InfrastructureError {
 	if queries, err := dbService.transactionalRepository.CreateNewTransaction(ctx); err != nil {
-		return error.NewSQLError(err.Error())
+		return err
 	} else if err := dbService.userRepository.DeleteUserByID(ctx, queries, ID); err != nil {
 		_ = dbService.transactionalRepository.RollbackTransaction(queries)
 		return err
@@ -10,16 +10,26 @@ import (
 )
 
 type Category struct {
-	ID   uuid.UUID
-	Name string
+	ID      uuid.UUID
+	Name    string
+	Version int64
 }
 
 type Todo struct {
-	ID          uuid.UUID
-	Title       string
-	Description string
-	CreatedAt   time.Time
-	UpdatedAt   sql.NullTime
+	ID           uuid.UUID
+	Title        string
+	Description  string
+	CreatedAt    time.Time
+	UpdatedAt    sql.NullTime
+	SearchVector interface{}
+	Version      int64
+	Status       string
+	Priority     string
+	DueAt        sql.NullTime
+	CompletedAt  sql.NullTime
+	Recurrence   sql.NullString
+	SeriesID     uuid.NullUUID
+	Occurrence   int32
 }
 
 type TodoCategory struct {
@@ -27,6 +37,25 @@ type TodoCategory struct {
 	CategoryID uuid.UUID
 }
 
+type TodoItem struct {
+	ID        uuid.UUID
+	TodoID    uuid.UUID
+	Title     string
+	Done      bool
+	Position  int32
+	CreatedAt time.Time
+	UpdatedAt sql.NullTime
+}
+
+type TodoReminder struct {
+	ID               uuid.UUID
+	TodoID           uuid.UUID
+	BeforeDueSeconds int64
+	CreatedAt        time.Time
+	SentDueAt        sql.NullTime
+	SentAt           sql.NullTime
+}
+
 type User struct {
 	ID       uuid.UUID
 	Username string
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
 const addToDoCategory = `-- name: AddToDoCategories :exec
@@ -25,6 +26,85 @@ func (q *Queries) AddToDoCategory(ctx context.Context, arg AddToDoCategoryParams
 	return err
 }
 
+const bumpCategoryToDoVersions = `-- name: BumpCategoryToDoVersions :exec
+UPDATE todos SET version = version + 1 WHERE id IN (SELECT todo_id FROM todo_category WHERE category_id = $1)
+`
+
+func (q *Queries) BumpCategoryToDoVersions(ctx context.Context, categoryID uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, bumpCategoryToDoVersions, categoryID)
+	return err
+}
+
+const bumpToDoVersion = `-- name: BumpToDoVersion :execrows
+UPDATE todos SET version = version + 1
+WHERE id = $1 AND ($2::bigint IS NULL OR version = $2)
+`
+
+type BumpToDoVersionParams struct {
+	ID              uuid.UUID
+	ExpectedVersion sql.NullInt64
+}
+
+func (q *Queries) BumpToDoVersion(ctx context.Context, arg BumpToDoVersionParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, bumpToDoVersion, arg.ID, arg.ExpectedVersion)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const changeToDoStatus = `-- name: ChangeToDoStatus :execrows
+UPDATE todos SET status = $1, completed_at = $2, updated_at = $3, version = version + 1
+WHERE id = $4 AND status = ANY($5::text[])
+    AND ($6::bigint IS NULL OR version = $6)
+`
+
+type ChangeToDoStatusParams struct {
+	Status          string
+	CompletedAt     sql.NullTime
+	UpdatedAt       sql.NullTime
+	ID              uuid.UUID
+	FromStatuses    []string
+	ExpectedVersion sql.NullInt64
+}
+
+func (q *Queries) ChangeToDoStatus(ctx context.Context, arg ChangeToDoStatusParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, changeToDoStatus,
+		arg.Status,
+		arg.CompletedAt,
+		arg.UpdatedAt,
+		arg.ID,
+		pq.Array(arg.FromStatuses),
+		arg.ExpectedVersion,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const countCategoryToDos = `-- name: CountCategoryToDos :one
+SELECT COUNT(*) FROM todo_category WHERE category_id = $1
+`
+
+func (q *Queries) CountCategoryToDos(ctx context.Context, categoryID uuid.UUID) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countCategoryToDos, categoryID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countToDoItems = `-- name: CountToDoItems :one
+SELECT COUNT(*) FROM todo_items WHERE todo_id = $1
+`
+
+func (q *Queries) CountToDoItems(ctx context.Context, todoID uuid.UUID) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countToDoItems, todoID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createCategory = `-- name: CreateCategory :exec
 INSERT INTO categories (id, name) VALUES ($1, $2)
 `
@@ -40,7 +120,8 @@ func (q *Queries) CreateCategory(ctx context.Context, arg CreateCategoryParams)
 }
 
 const createToDo = `-- name: CreateToDo :exec
-INSERT INTO todos (id, title, description, created_at) VALUES ($1, $2, $3, $4)
+INSERT INTO todos (id, title, description, created_at, priority, due_at, recurrence, series_id, occurrence)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
 `
 
 type CreateToDoParams struct {
@@ -48,6 +129,11 @@ type CreateToDoParams struct {
 	Title       string
 	Description string
 	CreatedAt   time.Time
+	Priority    string
+	DueAt       sql.NullTime
+	Recurrence  sql.NullString
+	SeriesID    uuid.NullUUID
+	Occurrence  int32
 }
 
 func (q *Queries) CreateToDo(ctx context.Context, arg CreateToDoParams) error {
@@ -56,6 +142,55 @@ func (q *Queries) CreateToDo(ctx context.Context, arg CreateToDoParams) error {
 		arg.Title,
 		arg.Description,
 		arg.CreatedAt,
+		arg.Priority,
+		arg.DueAt,
+		arg.Recurrence,
+		arg.SeriesID,
+		arg.Occurrence,
+	)
+	return err
+}
+
+const createToDoItem = `-- name: CreateToDoItem :exec
+INSERT INTO todo_items (id, todo_id, title, position, created_at) VALUES ($1, $2, $3, $4, $5)
+`
+
+type CreateToDoItemParams struct {
+	ID        uuid.UUID
+	TodoID    uuid.UUID
+	Title     string
+	Position  int32
+	CreatedAt time.Time
+}
+
+func (q *Queries) CreateToDoItem(ctx context.Context, arg CreateToDoItemParams) error {
+	_, err := q.db.ExecContext(ctx, createToDoItem,
+		arg.ID,
+		arg.TodoID,
+		arg.Title,
+		arg.Position,
+		arg.CreatedAt,
+	)
+	return err
+}
+
+const createToDoReminder = `-- name: CreateToDoReminder :exec
+INSERT INTO todo_reminders (id, todo_id, before_due_seconds, created_at) VALUES ($1, $2, $3, $4)
+`
+
+type CreateToDoReminderParams struct {
+	ID               uuid.UUID
+	TodoID           uuid.UUID
+	BeforeDueSeconds int64
+	CreatedAt        time.Time
+}
+
+func (q *Queries) CreateToDoReminder(ctx context.Context, arg CreateToDoReminderParams) error {
+	_, err := q.db.ExecContext(ctx, createToDoReminder,
+		arg.ID,
+		arg.TodoID,
+		arg.BeforeDueSeconds,
+		arg.CreatedAt,
 	)
 	return err
 }
@@ -81,22 +216,71 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) error {
 	return err
 }
 
-const deleteCategory = `-- name: DeleteCategory :exec
-DELETE FROM categories WHERE id = $1
+const deleteCategory = `-- name: DeleteCategory :execrows
+DELETE FROM categories WHERE id = $1 AND ($2::bigint IS NULL OR version = $2)
 `
 
-func (q *Queries) DeleteCategory(ctx context.Context, id uuid.UUID) error {
-	_, err := q.db.ExecContext(ctx, deleteCategory, id)
-	return err
+type DeleteCategoryParams struct {
+	ID              uuid.UUID
+	ExpectedVersion sql.NullInt64
 }
 
-const deleteToDo = `-- name: DeleteToDo :exec
-DELETE FROM todos WHERE id = $1
+func (q *Queries) DeleteCategory(ctx context.Context, arg DeleteCategoryParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteCategory, arg.ID, arg.ExpectedVersion)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteToDo = `-- name: DeleteToDo :execrows
+DELETE FROM todos WHERE id = $1 AND ($2::bigint IS NULL OR version = $2)
 `
 
-func (q *Queries) DeleteToDo(ctx context.Context, id uuid.UUID) error {
-	_, err := q.db.ExecContext(ctx, deleteToDo, id)
-	return err
+type DeleteToDoParams struct {
+	ID              uuid.UUID
+	ExpectedVersion sql.NullInt64
+}
+
+func (q *Queries) DeleteToDo(ctx context.Context, arg DeleteToDoParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteToDo, arg.ID, arg.ExpectedVersion)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteToDoItem = `-- name: DeleteToDoItem :one
+DELETE FROM todo_items WHERE id = $1 AND todo_id = $2 RETURNING position
+`
+
+type DeleteToDoItemParams struct {
+	ID     uuid.UUID
+	TodoID uuid.UUID
+}
+
+func (q *Queries) DeleteToDoItem(ctx context.Context, arg DeleteToDoItemParams) (int32, error) {
+	row := q.db.QueryRowContext(ctx, deleteToDoItem, arg.ID, arg.TodoID)
+	var position int32
+	err := row.Scan(&position)
+	return position, err
+}
+
+const deleteToDoReminder = `-- name: DeleteToDoReminder :execrows
+DELETE FROM todo_reminders WHERE id = $1 AND todo_id = $2
+`
+
+type DeleteToDoReminderParams struct {
+	ID     uuid.UUID
+	TodoID uuid.UUID
+}
+
+func (q *Queries) DeleteToDoReminder(ctx context.Context, arg DeleteToDoReminderParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteToDoReminder, arg.ID, arg.TodoID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
 }
 
 const deleteUser = `-- name: DeleteUser :exec
@@ -109,7 +293,7 @@ func (q *Queries) DeleteUser(ctx context.Context, id uuid.UUID) error {
 }
 
 const getCategoriesList = `-- name: GetCategoriesList :many
-SELECT id, name FROM categories
+SELECT id, name, version FROM categories
 `
 
 func (q *Queries) GetCategoriesList(ctx context.Context) ([]Category, error) {
@@ -121,7 +305,7 @@ func (q *Queries) GetCategoriesList(ctx context.Context) ([]Category, error) {
 	var items []Category
 	for rows.Next() {
 		var i Category
-		if err := rows.Scan(&i.ID, &i.Name); err != nil {
+		if err := rows.Scan(&i.ID, &i.Name, &i.Version); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -136,18 +320,18 @@ func (q *Queries) GetCategoriesList(ctx context.Context) ([]Category, error) {
 }
 
 const getCategoryById = `-- name: GetCategoryById :one
-SELECT id, name FROM categories WHERE id = $1
+SELECT id, name, version FROM categories WHERE id = $1
 `
 
 func (q *Queries) GetCategoryById(ctx context.Context, id uuid.UUID) (Category, error) {
 	row := q.db.QueryRowContext(ctx, getCategoryById, id)
 	var i Category
-	err := row.Scan(&i.ID, &i.Name)
+	err := row.Scan(&i.ID, &i.Name, &i.Version)
 	return i, err
 }
 
 const getToDoById = `-- name: GetToDoById :one
-SELECT id, title, description, created_at, updated_at FROM todos WHERE id = $1
+SELECT id, title, description, created_at, updated_at, version, status, priority, due_at, completed_at, recurrence, series_id, occurrence FROM todos WHERE id = $1
 `
 
 func (q *Queries) GetToDoById(ctx context.Context, id uuid.UUID) (Todo, error) {
@@ -159,12 +343,20 @@ func (q *Queries) GetToDoById(ctx context.Context, id uuid.UUID) (Todo, error) {
 		&i.Description,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Version,
+		&i.Status,
+		&i.Priority,
+		&i.DueAt,
+		&i.CompletedAt,
+		&i.Recurrence,
+		&i.SeriesID,
+		&i.Occurrence,
 	)
 	return i, err
 }
 
 const getToDoCategories = `-- name: GetToDoCategories :many
-SELECT c.id, c.name FROM todo_category tc INNER JOIN categories c on c.id = tc.category_id WHERE todo_id = $1
+SELECT c.id, c.name, c.version FROM todo_category tc INNER JOIN categories c on c.id = tc.category_id WHERE todo_id = $1
 `
 
 func (q *Queries) GetToDoCategories(ctx context.Context, todoID uuid.UUID) ([]Category, error) {
@@ -176,7 +368,7 @@ func (q *Queries) GetToDoCategories(ctx context.Context, todoID uuid.UUID) ([]Ca
 	var items []Category
 	for rows.Next() {
 		var i Category
-		if err := rows.Scan(&i.ID, &i.Name); err != nil {
+		if err := rows.Scan(&i.ID, &i.Name, &i.Version); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -190,23 +382,25 @@ func (q *Queries) GetToDoCategories(ctx context.Context, todoID uuid.UUID) ([]Ca
 	return items, nil
 }
 
-const getToDoList = `-- name: GetToDoList :many
-SELECT id, title, description, created_at, updated_at FROM todos
+const getToDoItems = `-- name: GetToDoItems :many
+SELECT id, todo_id, title, done, position, created_at, updated_at FROM todo_items WHERE todo_id = $1 ORDER BY position, id
 `
 
-func (q *Queries) GetToDoList(ctx context.Context) ([]Todo, error) {
-	rows, err := q.db.QueryContext(ctx, getToDoList)
+func (q *Queries) GetToDoItems(ctx context.Context, todoID uuid.UUID) ([]TodoItem, error) {
+	rows, err := q.db.QueryContext(ctx, getToDoItems, todoID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []Todo
+	var items []TodoItem
 	for rows.Next() {
-		var i Todo
+		var i TodoItem
 		if err := rows.Scan(
 			&i.ID,
+			&i.TodoID,
 			&i.Title,
-			&i.Description,
+			&i.Done,
+			&i.Position,
 			&i.CreatedAt,
 			&i.UpdatedAt,
 		); err != nil {
@@ -223,6 +417,162 @@ func (q *Queries) GetToDoList(ctx context.Context) ([]Todo, error) {
 	return items, nil
 }
 
+const getToDoListCategories = `-- name: GetToDoListCategories :many
+SELECT tc.todo_id, c.id, c.name FROM todo_category tc INNER JOIN categories c on c.id = tc.category_id WHERE tc.todo_id = ANY($1::uuid[])
+`
+
+type GetToDoListCategoriesRow struct {
+	TodoID uuid.UUID
+	ID     uuid.UUID
+	Name   string
+}
+
+func (q *Queries) GetToDoListCategories(ctx context.Context, todoIds []uuid.UUID) ([]GetToDoListCategoriesRow, error) {
+	rows, err := q.db.QueryContext(ctx, getToDoListCategories, pq.Array(todoIds))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetToDoListCategoriesRow
+	for rows.Next() {
+		var i GetToDoListCategoriesRow
+		if err := rows.Scan(&i.TodoID, &i.ID, &i.Name); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getToDoListItems = `-- name: GetToDoListItems :many
+SELECT id, todo_id, title, done, position, created_at, updated_at FROM todo_items WHERE todo_id = ANY($1::uuid[]) ORDER BY todo_id, position, id
+`
+
+func (q *Queries) GetToDoListItems(ctx context.Context, todoIds []uuid.UUID) ([]TodoItem, error) {
+	rows, err := q.db.QueryContext(ctx, getToDoListItems, pq.Array(todoIds))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []TodoItem
+	for rows.Next() {
+		var i TodoItem
+		if err := rows.Scan(
+			&i.ID,
+			&i.TodoID,
+			&i.Title,
+			&i.Done,
+			&i.Position,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getToDoReminders = `-- name: GetToDoReminders :many
+SELECT id, todo_id, before_due_seconds, created_at, sent_due_at, sent_at FROM todo_reminders WHERE todo_id = $1 ORDER BY before_due_seconds DESC
+`
+
+func (q *Queries) GetToDoReminders(ctx context.Context, todoID uuid.UUID) ([]TodoReminder, error) {
+	rows, err := q.db.QueryContext(ctx, getToDoReminders, todoID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []TodoReminder
+	for rows.Next() {
+		var i TodoReminder
+		if err := rows.Scan(
+			&i.ID,
+			&i.TodoID,
+			&i.BeforeDueSeconds,
+			&i.CreatedAt,
+			&i.SentDueAt,
+			&i.SentAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getUpcomingToDoReminders = `-- name: GetUpcomingToDoReminders :many
+SELECT r.id, r.todo_id, r.before_due_seconds, t.title, t.due_at::timestamp AS due_at,
+    (t.due_at - r.before_due_seconds * INTERVAL '1 second')::timestamp AS remind_at
+FROM todo_reminders r JOIN todos t ON t.id = r.todo_id
+WHERE t.status IN ('open', 'in-progress') AND t.due_at IS NOT NULL AND r.sent_due_at IS DISTINCT FROM t.due_at
+    AND t.due_at - r.before_due_seconds * INTERVAL '1 second' < $1::timestamp
+ORDER BY remind_at, r.id
+LIMIT $2
+`
+
+type GetUpcomingToDoRemindersParams struct {
+	RemindBefore time.Time
+	PageLimit    int32
+}
+
+type GetUpcomingToDoRemindersRow struct {
+	ID               uuid.UUID
+	TodoID           uuid.UUID
+	BeforeDueSeconds int64
+	Title            string
+	DueAt            time.Time
+	RemindAt         time.Time
+}
+
+func (q *Queries) GetUpcomingToDoReminders(ctx context.Context, arg GetUpcomingToDoRemindersParams) ([]GetUpcomingToDoRemindersRow, error) {
+	rows, err := q.db.QueryContext(ctx, getUpcomingToDoReminders, arg.RemindBefore, arg.PageLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetUpcomingToDoRemindersRow
+	for rows.Next() {
+		var i GetUpcomingToDoRemindersRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.TodoID,
+			&i.BeforeDueSeconds,
+			&i.Title,
+			&i.DueAt,
+			&i.RemindAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getUserById = `-- name: GetUserById :one
 SELECT id, username, password, role FROM users WHERE id = $1
 `
@@ -255,6 +605,43 @@ func (q *Queries) GetUserByUsername(ctx context.Context, username string) (User,
 	return i, err
 }
 
+const markToDoReminderSent = `-- name: MarkToDoReminderSent :execrows
+UPDATE todo_reminders SET sent_due_at = $1::timestamp, sent_at = $2::timestamp
+WHERE id = $3 AND sent_due_at IS DISTINCT FROM $1::timestamp
+    AND EXISTS (SELECT 1 FROM todos t WHERE t.id = todo_reminders.todo_id AND t.due_at = $1::timestamp
+        AND t.status IN ('open', 'in-progress'))
+`
+
+type MarkToDoReminderSentParams struct {
+	DueAt  time.Time
+	SentAt time.Time
+	ID     uuid.UUID
+}
+
+func (q *Queries) MarkToDoReminderSent(ctx context.Context, arg MarkToDoReminderSentParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, markToDoReminderSent, arg.DueAt, arg.SentAt, arg.ID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const reassignCategoryToDos = `-- name: ReassignCategoryToDos :exec
+INSERT INTO todo_category (todo_id, category_id)
+SELECT tc.todo_id, $1::uuid FROM todo_category tc WHERE tc.category_id = $2
+ON CONFLICT DO NOTHING
+`
+
+type ReassignCategoryToDosParams struct {
+	TargetCategoryID uuid.UUID
+	CategoryID       uuid.UUID
+}
+
+func (q *Queries) ReassignCategoryToDos(ctx context.Context, arg ReassignCategoryToDosParams) error {
+	_, err := q.db.ExecContext(ctx, reassignCategoryToDos, arg.TargetCategoryID, arg.CategoryID)
+	return err
+}
+
 const removeAllCategoriesFromToDo = `-- name: RemoveAllCategoriesFromToDo :exec
 DELETE FROM todo_category WHERE todo_id = $1
 `
@@ -264,6 +651,15 @@ func (q *Queries) RemoveAllCategoriesFromToDo(ctx context.Context, todoID uuid.U
 	return err
 }
 
+const removeCategoryFromAllToDos = `-- name: RemoveCategoryFromAllToDos :exec
+DELETE FROM todo_category WHERE category_id = $1
+`
+
+func (q *Queries) RemoveCategoryFromAllToDos(ctx context.Context, categoryID uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, removeCategoryFromAllToDos, categoryID)
+	return err
+}
+
 const removeToDoFromCategory = `-- name: RemoveToDoFromCategory :exec
 DELETE FROM todo_category WHERE todo_id = $1 AND category_id = $2
 `
@@ -278,39 +674,347 @@ func (q *Queries) RemoveToDoFromCategory(ctx context.Context, arg RemoveToDoFrom
 	return err
 }
 
-const updateCategoryName = `-- name: UpdateCategoryName :exec
-UPDATE categories SET name = $2 WHERE id = $1
+const reorderToDoItems = `-- name: ReorderToDoItems :exec
+UPDATE todo_items SET position = array_position($1::uuid[], id) - 1 WHERE todo_id = $2
 `
 
-type UpdateCategoryNameParams struct {
-	ID   uuid.UUID
-	Name string
+type ReorderToDoItemsParams struct {
+	ItemIds []uuid.UUID
+	TodoID  uuid.UUID
 }
 
-func (q *Queries) UpdateCategoryName(ctx context.Context, arg UpdateCategoryNameParams) error {
-	_, err := q.db.ExecContext(ctx, updateCategoryName, arg.ID, arg.Name)
+func (q *Queries) ReorderToDoItems(ctx context.Context, arg ReorderToDoItemsParams) error {
+	_, err := q.db.ExecContext(ctx, reorderToDoItems, pq.Array(arg.ItemIds), arg.TodoID)
 	return err
 }
 
-const updateToDoInformation = `-- name: UpdateToDoInformation :exec
-UPDATE todos SET title = $2, description = $3, updated_at = $4 WHERE id = $1
+const rescheduleToDo = `-- name: RescheduleToDo :execrows
+UPDATE todos SET due_at = $1, updated_at = $2, version = version + 1
+WHERE id = $3 AND status = ANY($4::text[])
+    AND ($5::bigint IS NULL OR version = $5)
 `
 
-type UpdateToDoInformationParams struct {
+type RescheduleToDoParams struct {
+	DueAt           sql.NullTime
+	UpdatedAt       sql.NullTime
+	ID              uuid.UUID
+	FromStatuses    []string
+	ExpectedVersion sql.NullInt64
+}
+
+func (q *Queries) RescheduleToDo(ctx context.Context, arg RescheduleToDoParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, rescheduleToDo,
+		arg.DueAt,
+		arg.UpdatedAt,
+		arg.ID,
+		pq.Array(arg.FromStatuses),
+		arg.ExpectedVersion,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const searchToDoList = `-- name: SearchToDoList :many
+SELECT id, title, description, created_at, updated_at, version, status, priority, due_at, completed_at, recurrence, series_id, occurrence,
+    sort_key::text FROM (
+    SELECT t.id, t.title, t.description, t.created_at, t.updated_at, t.version, t.status, t.priority, t.due_at, t.completed_at,
+        t.recurrence, t.series_id, t.occurrence,
+        CASE $1::text
+            WHEN 'title' THEN t.title
+            WHEN 'updated_at' THEN to_char(COALESCE(t.updated_at, t.created_at), 'YYYY-MM-DD HH24:MI:SS.US')
+            ELSE to_char(t.created_at, 'YYYY-MM-DD HH24:MI:SS.US')
+        END AS sort_key
+    FROM todos t
+    WHERE ($2::uuid IS NULL OR EXISTS (
+            SELECT 1 FROM todo_category tc WHERE tc.todo_id = t.id AND tc.category_id = $2))
+        AND ($3::timestamp IS NULL OR t.created_at >= $3)
+        AND ($4::timestamp IS NULL OR t.created_at < $4)
+        AND ($5::timestamp IS NULL OR COALESCE(t.updated_at, t.created_at) >= $5)
+        AND ($6::timestamp IS NULL OR COALESCE(t.updated_at, t.created_at) < $6)
+        AND ($7::text IS NULL OR t.title ILIKE '%' || $7 || '%' ESCAPE '\')
+        AND ($8::text IS NULL OR t.status = $8)
+        AND ($9::text IS NULL OR t.priority = $9)
+        AND ($10::timestamp IS NULL OR t.due_at >= $10)
+        AND ($11::timestamp IS NULL OR t.due_at < $11)
+        AND (NOT $12::boolean OR t.status IN ('open', 'in-progress'))
+) AS filtered
+WHERE $13::uuid IS NULL
+    OR ($14::boolean AND (sort_key, id) < ($15::text, $13))
+    OR (NOT $14::boolean AND (sort_key, id) > ($15::text, $13))
+ORDER BY
+    CASE WHEN $14::boolean THEN sort_key END DESC,
+    CASE WHEN $14::boolean THEN id END DESC,
+    CASE WHEN NOT $14::boolean THEN sort_key END ASC,
+    CASE WHEN NOT $14::boolean THEN id END ASC
+LIMIT $16 OFFSET $17
+`
+
+type SearchToDoListParams struct {
+	SortField   string
+	CategoryID  uuid.NullUUID
+	CreatedFrom sql.NullTime
+	CreatedTo   sql.NullTime
+	UpdatedFrom sql.NullTime
+	UpdatedTo   sql.NullTime
+	Title       sql.NullString
+	Status      sql.NullString
+	Priority    sql.NullString
+	DueFrom     sql.NullTime
+	DueTo       sql.NullTime
+	Unfinished  bool
+	CursorID    uuid.NullUUID
+	Descending  bool
+	CursorKey   sql.NullString
+	PageLimit   int32
+	PageOffset  int32
+}
+
+type SearchToDoListRow struct {
 	ID          uuid.UUID
 	Title       string
 	Description string
+	CreatedAt   time.Time
 	UpdatedAt   sql.NullTime
+	Version     int64
+	Status      string
+	Priority    string
+	DueAt       sql.NullTime
+	CompletedAt sql.NullTime
+	Recurrence  sql.NullString
+	SeriesID    uuid.NullUUID
+	Occurrence  int32
+	SortKey     string
 }
 
-func (q *Queries) UpdateToDoInformation(ctx context.Context, arg UpdateToDoInformationParams) error {
-	_, err := q.db.ExecContext(ctx, updateToDoInformation,
-		arg.ID,
+func (q *Queries) SearchToDoList(ctx context.Context, arg SearchToDoListParams) ([]SearchToDoListRow, error) {
+	rows, err := q.db.QueryContext(ctx, searchToDoList,
+		arg.SortField,
+		arg.CategoryID,
+		arg.CreatedFrom,
+		arg.CreatedTo,
+		arg.UpdatedFrom,
+		arg.UpdatedTo,
+		arg.Title,
+		arg.Status,
+		arg.Priority,
+		arg.DueFrom,
+		arg.DueTo,
+		arg.Unfinished,
+		arg.CursorID,
+		arg.Descending,
+		arg.CursorKey,
+		arg.PageLimit,
+		arg.PageOffset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SearchToDoListRow
+	for rows.Next() {
+		var i SearchToDoListRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Description,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Version,
+			&i.Status,
+			&i.Priority,
+			&i.DueAt,
+			&i.CompletedAt,
+			&i.Recurrence,
+			&i.SeriesID,
+			&i.Occurrence,
+			&i.SortKey,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const searchToDoText = `-- name: SearchToDoText :many
+SELECT t.id, t.title, t.description, t.created_at, t.updated_at, t.version, t.status, t.priority, t.due_at, t.completed_at,
+    t.recurrence, t.series_id, t.occurrence,
+    ts_rank_cd(t.search_vector, query)::real AS rank,
+    ts_headline(t.title, query, 'StartSel=<mark>, StopSel=</mark>, HighlightAll=true')::text AS title_highlight,
+    ts_headline(t.description, query, 'StartSel=<mark>, StopSel=</mark>, MaxFragments=2, MaxWords=20, MinWords=5')::text AS snippet
+FROM todos t, websearch_to_tsquery('english', $1::text) query
+WHERE t.search_vector @@ query
+ORDER BY rank DESC, t.id
+LIMIT $2
+`
+
+type SearchToDoTextParams struct {
+	Query     string
+	PageLimit int32
+}
+
+type SearchToDoTextRow struct {
+	ID             uuid.UUID
+	Title          string
+	Description    string
+	CreatedAt      time.Time
+	UpdatedAt      sql.NullTime
+	Version        int64
+	Status         string
+	Priority       string
+	DueAt          sql.NullTime
+	CompletedAt    sql.NullTime
+	Recurrence     sql.NullString
+	SeriesID       uuid.NullUUID
+	Occurrence     int32
+	Rank           float32
+	TitleHighlight string
+	Snippet        string
+}
+
+func (q *Queries) SearchToDoText(ctx context.Context, arg SearchToDoTextParams) ([]SearchToDoTextRow, error) {
+	rows, err := q.db.QueryContext(ctx, searchToDoText, arg.Query, arg.PageLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SearchToDoTextRow
+	for rows.Next() {
+		var i SearchToDoTextRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Description,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Version,
+			&i.Status,
+			&i.Priority,
+			&i.DueAt,
+			&i.CompletedAt,
+			&i.Recurrence,
+			&i.SeriesID,
+			&i.Occurrence,
+			&i.Rank,
+			&i.TitleHighlight,
+			&i.Snippet,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const shiftToDoItems = `-- name: ShiftToDoItems :exec
+UPDATE todo_items SET position = position + $1::integer WHERE todo_id = $2 AND position >= $3::integer
+`
+
+type ShiftToDoItemsParams struct {
+	Shift        int32
+	TodoID       uuid.UUID
+	FromPosition int32
+}
+
+func (q *Queries) ShiftToDoItems(ctx context.Context, arg ShiftToDoItemsParams) error {
+	_, err := q.db.ExecContext(ctx, shiftToDoItems, arg.Shift, arg.TodoID, arg.FromPosition)
+	return err
+}
+
+const updateCategoryName = `-- name: UpdateCategoryName :execrows
+UPDATE categories SET name = $2, version = version + 1
+WHERE id = $1 AND ($3::bigint IS NULL OR version = $3)
+`
+
+type UpdateCategoryNameParams struct {
+	ID              uuid.UUID
+	Name            string
+	ExpectedVersion sql.NullInt64
+}
+
+func (q *Queries) UpdateCategoryName(ctx context.Context, arg UpdateCategoryNameParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, updateCategoryName, arg.ID, arg.Name, arg.ExpectedVersion)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const updateToDoInformation = `-- name: UpdateToDoInformation :execrows
+UPDATE todos SET title = COALESCE($1, title), description = COALESCE($2, description),
+    priority = COALESCE($3, priority),
+    recurrence = CASE WHEN $4::boolean THEN $5 ELSE recurrence END,
+    updated_at = $6, version = version + 1
+WHERE id = $7 AND ($8::bigint IS NULL OR version = $8)
+`
+
+type UpdateToDoInformationParams struct {
+	Title           sql.NullString
+	Description     sql.NullString
+	Priority        sql.NullString
+	SetRecurrence   bool
+	Recurrence      sql.NullString
+	UpdatedAt       sql.NullTime
+	ID              uuid.UUID
+	ExpectedVersion sql.NullInt64
+}
+
+func (q *Queries) UpdateToDoInformation(ctx context.Context, arg UpdateToDoInformationParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, updateToDoInformation,
 		arg.Title,
 		arg.Description,
+		arg.Priority,
+		arg.SetRecurrence,
+		arg.Recurrence,
 		arg.UpdatedAt,
+		arg.ID,
+		arg.ExpectedVersion,
 	)
-	return err
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const updateToDoItem = `-- name: UpdateToDoItem :execrows
+UPDATE todo_items SET title = COALESCE($1, title), done = COALESCE($2, done), updated_at = $3
+WHERE id = $4 AND todo_id = $5
+`
+
+type UpdateToDoItemParams struct {
+	Title     sql.NullString
+	Done      sql.NullBool
+	UpdatedAt sql.NullTime
+	ID        uuid.UUID
+	TodoID    uuid.UUID
+}
+
+func (q *Queries) UpdateToDoItem(ctx context.Context, arg UpdateToDoItemParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, updateToDoItem,
+		arg.Title,
+		arg.Done,
+		arg.UpdatedAt,
+		arg.ID,
+		arg.TodoID,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
 }
 
 const updateUserPassword = `-- name: UpdateUserPassword :exec
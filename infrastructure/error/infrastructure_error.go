@@ -7,10 +7,16 @@ import (
 type InfrastructureErrorCode string
 
 const (
-	ItemNotFound   InfrastructureErrorCode = "ITEM_NOT_FOUND"
-	SQLError       InfrastructureErrorCode = "SQL_ERROR"
-	ParseFileError InfrastructureErrorCode = "PARSE_FILE_ERROR"
-	HashingError   InfrastructureErrorCode = "HASHING_ERROR"
+	ItemNotFound    InfrastructureErrorCode = "ITEM_NOT_FOUND"
+	SQLError        InfrastructureErrorCode = "SQL_ERROR"
+	ParseFileError  InfrastructureErrorCode = "PARSE_FILE_ERROR"
+	HashingError    InfrastructureErrorCode = "HASHING_ERROR"
+	EventBusError   InfrastructureErrorCode = "EVENT_BUS_ERROR"
+	TracingError    InfrastructureErrorCode = "TRACING_ERROR"
+	InvalidArgument InfrastructureErrorCode = "INVALID_ARGUMENT"
+	CircuitOpen     InfrastructureErrorCode = "CIRCUIT_OPEN"
+	Conflict        InfrastructureErrorCode = "CONFLICT"
+	VersionMismatch InfrastructureErrorCode = "VERSION_MISMATCH"
 )
 
 type InfrastructureError interface {
@@ -63,3 +69,45 @@ func NewHashingError(message string) InfrastructureError {
 		Message: message,
 	}
 }
+
+func NewEventBusError(message string) InfrastructureError {
+	return &infrastructureError{
+		Code:    EventBusError,
+		Message: message,
+	}
+}
+
+func NewTracingError(message string) InfrastructureError {
+	return &infrastructureError{
+		Code:    TracingError,
+		Message: message,
+	}
+}
+
+func NewInvalidArgumentError(message string) InfrastructureError {
+	return &infrastructureError{
+		Code:    InvalidArgument,
+		Message: message,
+	}
+}
+
+func NewCircuitOpenError(message string) InfrastructureError {
+	return &infrastructureError{
+		Code:    CircuitOpen,
+		Message: message,
+	}
+}
+
+func NewConflictError(message string) InfrastructureError {
+	return &infrastructureError{
+		Code:    Conflict,
+		Message: message,
+	}
+}
+
+func NewVersionMismatchError(message string) InfrastructureError {
+	return &infrastructureError{
+		Code:    VersionMismatch,
+		Message: message,
+	}
+}
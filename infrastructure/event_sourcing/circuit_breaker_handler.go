@@ -0,0 +1,95 @@
+package event_sourcing
+
+import (
+	"context"
+	"event-bus-demo/infrastructure/resilience"
+	"fmt"
+	"time"
+)
+
+type CircuitBreakerMode string
+
+const (
+	// HoldCircuitBreakerMode keeps the events of the topic buffered while the circuit breaker is open.
+	HoldCircuitBreakerMode CircuitBreakerMode = "hold"
+	// FailFastCircuitBreakerMode fails the events of the topic straight away while the circuit breaker is open.
+	FailFastCircuitBreakerMode CircuitBreakerMode = "fail-fast"
+)
+
+const holdPollInterval = 100 * time.Millisecond
+
+// HoldingEventHandler is implemented by handlers which can ask the bus to stop dispatching events of their topic for
+// a while. Events stay buffered until the handler stops holding them.
+type HoldingEventHandler interface {
+	EventHandler
+	IsHolding() bool
+}
+
+type circuitBreakerHandler struct {
+	handler   EventHandler
+	breaker   resilience.CircuitBreaker
+	mode      CircuitBreakerMode
+	isFailure func(result EventResult) bool
+}
+
+// NewCircuitBreakerHandler wraps handler with breaker. isFailure tells which results count as a failure of the
+// protected dependency; any other result is reported as a success to the breaker.
+func NewCircuitBreakerHandler(handler EventHandler, breaker resilience.CircuitBreaker, mode CircuitBreakerMode,
+	isFailure func(result EventResult) bool) HoldingEventHandler {
+	return &circuitBreakerHandler{
+		handler:   handler,
+		breaker:   breaker,
+		mode:      mode,
+		isFailure: isFailure,
+	}
+}
+
+func (handler *circuitBreakerHandler) Handle(ctx context.Context, event Event) EventResult {
+	for !handler.breaker.Allow() {
+		if handler.mode == FailFastCircuitBreakerMode {
+			return handler.newCircuitOpenResult(event)
+		}
+		select {
+		case <-ctx.Done():
+			return handler.newCircuitOpenResult(event)
+		case <-time.After(holdPollInterval):
+		}
+	}
+	result := handler.handler.Handle(ctx, event)
+	if handler.isFailure(result) {
+		handler.breaker.Failure()
+	} else {
+		handler.breaker.Success()
+	}
+	return result
+}
+
+func (handler *circuitBreakerHandler) IsHolding() bool {
+	return handler.mode == HoldCircuitBreakerMode && !handler.breaker.Ready()
+}
+
+func (handler *circuitBreakerHandler) Unwrap() EventHandler {
+	return handler.handler
+}
+
+func (handler *circuitBreakerHandler) newCircuitOpenResult(event Event) EventResult {
+	return EventResult{
+		Event:       event,
+		Error:       fmt.Errorf("circuit breaker %s is open", handler.breaker.GetName()),
+		FailureKind: CircuitOpenFailure,
+	}
+}
+
+// unwrapTypedEventHandler returns the typed handler behind handler middlewares, if any.
+func unwrapTypedEventHandler(handler EventHandler) (TypedEventHandler, bool) {
+	for {
+		if typedHandler, ok := handler.(TypedEventHandler); ok {
+			return typedHandler, true
+		}
+		wrapper, ok := handler.(interface{ Unwrap() EventHandler })
+		if !ok {
+			return nil, false
+		}
+		handler = wrapper.Unwrap()
+	}
+}
@@ -0,0 +1,148 @@
+package event_sourcing
+
+import (
+	"context"
+	"errors"
+	"event-bus-demo/infrastructure/resilience"
+	"go.uber.org/zap"
+	"sync"
+	"testing"
+	"time"
+)
+
+const testOpenTimeout = time.Minute
+
+// countingHandler fails while failing is set and counts the events it handled.
+type countingHandler struct {
+	mutex   sync.Mutex
+	calls   int
+	failing bool
+}
+
+func (handler *countingHandler) Handle(_ context.Context, event Event) EventResult {
+	handler.mutex.Lock()
+	defer handler.mutex.Unlock()
+	handler.calls++
+	if handler.failing {
+		return EventResult{Event: event, Error: errors.New("dependency is down"), FailureKind: ErrorFailure}
+	}
+	return EventResult{Succeeded: true, Event: event}
+}
+
+func (handler *countingHandler) setFailing(failing bool) {
+	handler.mutex.Lock()
+	handler.failing = failing
+	handler.mutex.Unlock()
+}
+
+func (handler *countingHandler) handled() int {
+	handler.mutex.Lock()
+	defer handler.mutex.Unlock()
+	return handler.calls
+}
+
+func isErrorFailure(result EventResult) bool {
+	return result.FailureKind == ErrorFailure
+}
+
+// newOpenCircuitBreakerHandler returns a handler whose breaker was opened by two failures of the wrapped handler.
+func newOpenCircuitBreakerHandler(t *testing.T, mode CircuitBreakerMode) (HoldingEventHandler, *countingHandler,
+	*resilience.ManualClock) {
+	clock := resilience.NewManualClock(time.Date(2022, time.January, 1, 0, 0, 0, 0, time.UTC))
+	breaker := resilience.NewCircuitBreaker("test", resilience.CircuitBreakerOptions{
+		FailureThreshold: 2,
+		OpenTimeout:      testOpenTimeout,
+		HalfOpenProbes:   1,
+		Now:              clock.Now,
+	}, resilience.NewNoOpCircuitBreakerObserver(), zap.NewNop())
+	wrapped := &countingHandler{failing: true}
+	handler := NewCircuitBreakerHandler(wrapped, breaker, mode, isErrorFailure)
+	for i := 0; i < 2; i++ {
+		result := handler.Handle(context.Background(), testEvent{topic: "A", name: "TestEvent"})
+		if result.FailureKind != ErrorFailure {
+			t.Fatalf("expected the failure of the wrapped handler, got %+v", result)
+		}
+	}
+	if state := breaker.Snapshot().State; state != resilience.OpenState {
+		t.Fatalf("expected the breaker to be open, got %s", state)
+	}
+	return handler, wrapped, clock
+}
+
+func TestCircuitBreakerHandlerFailsFastWhileOpen(t *testing.T) {
+	handler, wrapped, clock := newOpenCircuitBreakerHandler(t, FailFastCircuitBreakerMode)
+	if handler.IsHolding() {
+		t.Error("expected a fail-fast handler not to hold its events")
+	}
+	result := handler.Handle(context.Background(), testEvent{topic: "A", name: "TestEvent"})
+	if result.Succeeded || result.FailureKind != CircuitOpenFailure || wrapped.handled() != 2 {
+		t.Fatalf("expected a circuit open failure without calling the handler, got %+v", result)
+	}
+	clock.Advance(testOpenTimeout)
+	wrapped.setFailing(false)
+	if result := handler.Handle(context.Background(), testEvent{topic: "A", name: "TestEvent"}); !result.Succeeded {
+		t.Fatalf("expected the probe to reach the handler once the open timeout is over, got %+v", result)
+	}
+	if result := handler.Handle(context.Background(), testEvent{topic: "A", name: "TestEvent"}); !result.Succeeded ||
+		wrapped.handled() != 4 {
+		t.Errorf("expected the successful probe to close the breaker, got %+v", result)
+	}
+}
+
+func TestCircuitBreakerHandlerHoldsEventsWhileOpen(t *testing.T) {
+	handler, wrapped, clock := newOpenCircuitBreakerHandler(t, HoldCircuitBreakerMode)
+	if !handler.IsHolding() {
+		t.Fatal("expected a holding handler to hold its events while the breaker is open")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 3*holdPollInterval)
+	defer cancel()
+	start := time.Now()
+	result := handler.Handle(ctx, testEvent{topic: "A", name: "TestEvent"})
+	if result.FailureKind != CircuitOpenFailure || wrapped.handled() != 2 {
+		t.Fatalf("expected a circuit open failure without calling the handler, got %+v", result)
+	} else if elapsed := time.Since(start); elapsed < 3*holdPollInterval {
+		t.Errorf("expected the handler to wait for the breaker until its deadline, returned after %s", elapsed)
+	}
+	clock.Advance(testOpenTimeout)
+	if handler.IsHolding() {
+		t.Error("expected the handler to stop holding once a probe is allowed")
+	}
+	wrapped.setFailing(false)
+	if result := handler.Handle(context.Background(), testEvent{topic: "A", name: "TestEvent"}); !result.Succeeded {
+		t.Errorf("expected the probe to reach the handler, got %+v", result)
+	}
+}
+
+func TestCircuitBreakerHandlerCountsOnlySelectedFailures(t *testing.T) {
+	breaker := resilience.NewCircuitBreaker("test", resilience.CircuitBreakerOptions{
+		FailureThreshold: 1,
+		OpenTimeout:      testOpenTimeout,
+		HalfOpenProbes:   1,
+	}, resilience.NewNoOpCircuitBreakerObserver(), zap.NewNop())
+	handler := NewCircuitBreakerHandler(&countingHandler{failing: true}, breaker, FailFastCircuitBreakerMode,
+		func(EventResult) bool {
+			return false
+		})
+	for i := 0; i < 3; i++ {
+		handler.Handle(context.Background(), testEvent{topic: "A", name: "TestEvent"})
+	}
+	if state := breaker.Snapshot().State; state != resilience.ClosedState {
+		t.Errorf("expected failures not selected by isFailure to keep the breaker closed, got %s", state)
+	}
+}
+
+func TestDispatchNextSkipsHeldTopics(t *testing.T) {
+	bus, metrics := newTestEventBus(10, 10)
+	handler, _, clock := newOpenCircuitBreakerHandler(t, HoldCircuitBreakerMode)
+	bus.RegisterHandler("A", handler)
+	bus.Publish(context.Background(), testEvent{topic: "A", name: "TestEvent"})
+	bus.Publish(context.Background(), testEvent{topic: "B", name: "TestEvent"})
+	dispatch(bus, 2)
+	if dequeued := metrics.dequeued(); len(dequeued) != 1 || dequeued[0].GetTopic() != "B" {
+		t.Fatalf("expected only the event of the topic which is not held to be dispatched, got %v", dequeued)
+	}
+	clock.Advance(testOpenTimeout)
+	if dispatch(bus, 1) != 1 {
+		t.Error("expected the held event to be dispatched once the breaker allows a probe")
+	}
+}
@@ -1,18 +1,44 @@
 package event_sourcing
 
+import "context"
+
+// FailureKind tells why an event could not be handled.
+type FailureKind string
+
+const (
+	NoFailure      FailureKind = ""
+	ErrorFailure   FailureKind = "error"
+	TimeoutFailure FailureKind = "timeout"
+	// CircuitOpenFailure is reported when the event was not handled because a circuit breaker was open.
+	CircuitOpenFailure FailureKind = "circuit_open"
+)
+
 type Event interface {
 	GetTopic() string
 	GetName() string
 }
 
+// Metadata holds the information carried along with an event from the publisher to its handlers, like the trace
+// context of the request that published it.
+type Metadata map[string]string
+
+type EventEnvelope struct {
+	Event    Event
+	Metadata Metadata
+	Priority Priority
+}
+
 type EventResult struct {
-	Succeeded bool
-	Event     Event
-	Response  interface{}
+	Succeeded   bool
+	Event       Event
+	Response    interface{}
+	Error       error
+	FailureKind FailureKind
+	Metadata    Metadata
 }
 
 type EventHandler interface {
-	Handle(event Event) EventResult
+	Handle(ctx context.Context, event Event) EventResult
 }
 
 type EventSubscriber interface {
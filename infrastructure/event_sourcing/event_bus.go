@@ -2,48 +2,111 @@ package event_sourcing
 
 import (
 	"context"
+	"errors"
+	"event-bus-demo/infrastructure/constants"
+	infrastructure "event-bus-demo/infrastructure/error"
+	"event-bus-demo/infrastructure/logging"
+	"fmt"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
-	"golang.org/x/sync/semaphore"
+	"sync"
+	"time"
 )
 
-type EventBusChannel chan Event
-type QuitSignalChannel chan bool
+const (
+	messagingSystem = "event-bus"
+	// defaultHandlerGracePeriod is how long a worker waits for a timed out handler to return before abandoning it.
+	defaultHandlerGracePeriod = time.Second
+	// GlobalWorkerPool names the worker budget shared by every topic in metrics.
+	GlobalWorkerPool = "global"
+)
+
+var tracer = otel.Tracer("event-bus-demo/infrastructure/event_sourcing")
+
+type EventBusChannel chan EventEnvelope
+type QuitSignalChannel chan struct{}
 
 func NewBufferedEventChannel(bufferSize int) EventBusChannel {
-	return make(chan Event, bufferSize)
+	return make(chan EventEnvelope, bufferSize)
 }
 
 func newQuitSignalChannel() QuitSignalChannel {
-	return make(chan bool)
+	return make(chan struct{})
 }
 
 type EventBus interface {
 	Run()
 	Stop()
-	Publish(event Event)
+	IsRunning() bool
+	Publish(ctx context.Context, event Event)
 	RegisterHandler(topic string, eventBusHandler EventHandler)
 	RegisterSubscriber(topic string, eventSubscriber EventSubscriber)
 	UnregisterHandler(topic string, publisher EventHandler)
 	UnregisterSubscriber(topic string, subscriber EventSubscriber)
+	RegisterEvents(events ...Event)
+	ConfigureTopic(topic string, options TopicOptions)
+	ConfigurePriorities(options PriorityOptions)
+	Describe() EventBusDescription
+	Validate() infrastructure.InfrastructureError
+	Stats() EventBusStats
+	PauseTopic(topic string) infrastructure.InfrastructureError
+	ResumeTopic(topic string) infrastructure.InfrastructureError
+	DrainTopic(ctx context.Context, topic string) infrastructure.InfrastructureError
+	SetMaxWorkers(maxWorkers int) infrastructure.InfrastructureError
+	SetHandlerTimeout(timeout time.Duration)
+	NewEvent(name string, payload []byte) (Event, infrastructure.InfrastructureError)
 }
 
 type eventBus struct {
 	logger             *zap.Logger
+	eventRegistry      map[string]Event
 	handlerRegistry    map[string][]EventHandler
 	subscriberRegistry map[string][]EventSubscriber
-	semaphore          *semaphore.Weighted
-	eventBusChannel    EventBusChannel
+	metrics            EventBusMetrics
+	bufferSize         int
+	wakeUpChannel      chan struct{}
 	quitSignalChannel  QuitSignalChannel
+	stopOnce           sync.Once
+	mutex              sync.Mutex
+	running            bool
+	maxWorkers         int
+	handlerTimeout     time.Duration
+	handlerGracePeriod time.Duration
+	busyWorkers        int
+	virtualTime        float64
+	topicOptions       map[string]TopicOptions
+	priorityOptions    PriorityOptions
+	laneSkips          map[Priority]int
+	topicQueues        map[string]*topicQueue
+	recentFailures     []EventFailure
 }
 
-func NewEventBus(event EventBusChannel, maxWorkers int, logger *zap.Logger) EventBus {
+// NewEventBus builds an event bus which keeps a queue of bufferSize events for every topic and handles at most
+// maxWorkers events at the same time.
+func NewEventBus(bufferSize int, maxWorkers int, metrics EventBusMetrics, logger *zap.Logger) EventBus {
 	return &eventBus{
-		eventBusChannel:    event,
+		bufferSize:         bufferSize,
 		quitSignalChannel:  newQuitSignalChannel(),
+		eventRegistry:      make(map[string]Event),
 		handlerRegistry:    make(map[string][]EventHandler),
 		subscriberRegistry: make(map[string][]EventSubscriber),
-		semaphore:          semaphore.NewWeighted(int64(maxWorkers)),
-		logger:             logger,
+		wakeUpChannel:      make(chan struct{}, 1),
+		maxWorkers:         maxWorkers,
+		handlerGracePeriod: defaultHandlerGracePeriod,
+		topicOptions:       make(map[string]TopicOptions),
+		priorityOptions: PriorityOptions{
+			Events: make(map[string]Priority),
+		},
+		laneSkips:      make(map[Priority]int),
+		topicQueues:    make(map[string]*topicQueue),
+		recentFailures: make([]EventFailure, 0),
+		metrics:        metrics,
+		logger:         logger,
 	}
 }
 
@@ -75,52 +138,194 @@ func (bus *eventBus) UnregisterSubscriber(topic string, subscriber EventSubscrib
 	bus.subscriberRegistry[topic] = temp
 }
 
-func (bus *eventBus) Publish(event Event) {
-	select {
-	case bus.eventBusChannel <- event:
-	default:
+func (bus *eventBus) Publish(ctx context.Context, event Event) {
+	ctx, span := tracer.Start(ctx, "publish "+event.GetName(),
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(eventSpanAttributes(event)...))
+	defer span.End()
+	metadata := make(Metadata)
+	otel.GetTextMapPropagator().Inject(ctx, propagation.MapCarrier(metadata))
+	if requestID := logging.RequestIDFromContext(ctx); requestID != "" {
+		metadata[constants.RequestIDMetadataKey] = requestID
+	}
+	bus.metrics.EventPublished(event)
+	queue := bus.getTopicQueue(event.GetTopic())
+	priority := bus.eventPriority(event)
+	span.SetAttributes(attribute.String("messaging.event_priority", priority.String()))
+	bus.mutex.Lock()
+	queued := queue.enqueue(EventEnvelope{Event: event, Metadata: metadata, Priority: priority})
+	bus.mutex.Unlock()
+	if queued {
+		bus.eventQueued(queue)
+	} else {
+		bus.logger.Warn("event bus topic buffer is full, dropping event", zap.String("name", event.GetName()),
+			zap.String("topic", event.GetTopic()))
+		bus.metrics.EventDropped(event)
+		span.SetStatus(codes.Error, "event bus topic buffer is full")
 	}
 }
 
 func (bus *eventBus) Run() {
-	go func() {
-		for {
-			select {
-			case event := <-bus.eventBusChannel:
-				bus.logger.Debug("new event received", zap.Any("event", event))
-				_ = bus.semaphore.Acquire(context.Background(), 1)
-				go func(event Event) {
-					defer bus.semaphore.Release(1)
-					bus.handleEvent(event)
-				}(event)
-			case <-bus.quitSignalChannel:
-				bus.logger.Info("event bus signaled to stop")
-				return
-			default:
-			}
-		}
-	}()
+	for _, topic := range bus.Describe().Topics {
+		bus.getTopicQueue(topic.Name)
+	}
+	bus.mutex.Lock()
+	bus.running = true
+	bus.mutex.Unlock()
+	go bus.schedule()
 }
 
 func (bus *eventBus) Stop() {
-	go func() {
-		bus.quitSignalChannel <- true
-	}()
+	bus.stopOnce.Do(func() {
+		bus.mutex.Lock()
+		bus.running = false
+		bus.mutex.Unlock()
+		close(bus.quitSignalChannel)
+	})
 }
 
-func (bus *eventBus) handleEvent(event Event) {
+func (bus *eventBus) IsRunning() bool {
+	bus.mutex.Lock()
+	defer bus.mutex.Unlock()
+	return bus.running
+}
+
+func (bus *eventBus) getTopicQueue(topic string) *topicQueue {
+	bus.mutex.Lock()
+	defer bus.mutex.Unlock()
+	queue, ok := bus.topicQueues[topic]
+	if !ok {
+		queue = newTopicQueue(topic, bus.bufferSize)
+		bus.topicQueues[topic] = queue
+	}
+	return queue
+}
+
+func (bus *eventBus) handleEvent(envelope EventEnvelope) {
+	event := envelope.Event
 	eventTopic := event.GetTopic()
 	foundHandlers := bus.handlerRegistry[eventTopic]
 	if foundHandlers == nil {
 		bus.logger.Debug("no bus handlers found for given event topic")
 	} else {
+		ctx := bus.newHandlerContext(envelope.Metadata)
 		for _, handler := range foundHandlers {
-			result := handler.Handle(event)
+			result := bus.runHandler(ctx, handler, event)
+			result.Metadata = envelope.Metadata
+			if !result.Succeeded {
+				bus.recordFailure(result)
+			}
 			bus.notifySubscribers(eventTopic, result)
 		}
 	}
 }
 
+// newHandlerContext rebuilds the context of the publisher from the event metadata, so handlers keep both the trace and
+// the request ID of the request that published the event.
+func (bus *eventBus) newHandlerContext(metadata Metadata) context.Context {
+	ctx := otel.GetTextMapPropagator().Extract(context.Background(), propagation.MapCarrier(metadata))
+	if requestID, ok := metadata[constants.RequestIDMetadataKey]; ok {
+		ctx = logging.WithRequestID(ctx, requestID)
+		ctx = logging.WithLogger(ctx, bus.logger.With(zap.String(logging.RequestIDField, requestID)))
+	}
+	return ctx
+}
+
+func (bus *eventBus) runHandler(ctx context.Context, handler EventHandler, event Event) EventResult {
+	ctx, span := tracer.Start(ctx, "process "+event.GetName(),
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(append(eventSpanAttributes(event), semconv.MessagingOperationProcess)...))
+	defer span.End()
+	bus.metrics.HandlerStarted(event)
+	start := time.Now()
+	result := bus.handleWithTimeout(ctx, handler, event)
+	bus.metrics.HandlerFinished(event, time.Since(start), result.FailureKind)
+	if !result.Succeeded {
+		span.SetAttributes(attribute.String("messaging.failure_kind", string(result.FailureKind)))
+		if result.Error != nil {
+			span.RecordError(result.Error)
+		}
+		span.SetStatus(codes.Error, "event handling failed")
+	}
+	return result
+}
+
+// handleWithTimeout runs the handler with the deadline of the event topic. When the deadline expires the context of the
+// handler is cancelled and the worker waits a grace period for the handler to return. A handler still running after it
+// is abandoned with a timeout result, but it keeps counting against the worker limits of its topic and of the bus until
+// it returns, so handlers ignoring their context cannot pile up beyond the configured number of workers.
+func (bus *eventBus) handleWithTimeout(ctx context.Context, handler EventHandler, event Event) EventResult {
+	timeout := bus.topicHandlerTimeout(event.GetTopic())
+	if timeout <= 0 {
+		return withFailureKind(handler.Handle(ctx, event))
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	resultChannel := make(chan EventResult, 1)
+	go func() {
+		resultChannel <- handler.Handle(ctx, event)
+	}()
+	select {
+	case result := <-resultChannel:
+		if !result.Succeeded && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			result.FailureKind = TimeoutFailure
+		}
+		return withFailureKind(result)
+	case <-ctx.Done():
+		logger := logging.FromContext(ctx, bus.logger).With(zap.String("name", event.GetName()))
+		logger.Error("event handler timed out", zap.Duration("timeout", timeout))
+		bus.waitForTimedOutHandler(event, resultChannel, logger)
+		return EventResult{
+			Event:       event,
+			Error:       fmt.Errorf("handler of event %s timed out after %s", event.GetName(), timeout),
+			FailureKind: TimeoutFailure,
+		}
+	}
+}
+
+// waitForTimedOutHandler waits the grace period for a timed out handler to return. Past it, the handler is abandoned
+// and takes a worker of its own until it returns.
+func (bus *eventBus) waitForTimedOutHandler(event Event, resultChannel <-chan EventResult, logger *zap.Logger) {
+	bus.mutex.Lock()
+	gracePeriod := bus.handlerGracePeriod
+	bus.mutex.Unlock()
+	gracePeriodTimer := time.NewTimer(gracePeriod)
+	defer gracePeriodTimer.Stop()
+	select {
+	case <-resultChannel:
+		return
+	case <-gracePeriodTimer.C:
+	}
+	logger.Error("event handler did not return after its grace period, abandoning it",
+		zap.Duration("grace_period", gracePeriod))
+	queue := bus.getTopicQueue(event.GetTopic())
+	bus.updateAbandonedHandlers(queue, 1)
+	go func() {
+		<-resultChannel
+		logger.Warn("abandoned event handler returned")
+		bus.updateAbandonedHandlers(queue, -1)
+		bus.wakeUp()
+	}()
+}
+
+func (bus *eventBus) updateAbandonedHandlers(queue *topicQueue, delta int) {
+	bus.mutex.Lock()
+	defer bus.mutex.Unlock()
+	queue.abandonedHandlers += delta
+	queue.busyWorkers += delta
+	bus.busyWorkers += delta
+	bus.reportWorkerUsage(queue)
+	bus.metrics.AbandonedHandlers(queue.topic, queue.abandonedHandlers)
+}
+
+// withFailureKind makes sure that failed results coming from handlers which do not set a failure kind have one.
+func withFailureKind(result EventResult) EventResult {
+	if !result.Succeeded && result.FailureKind == NoFailure {
+		result.FailureKind = ErrorFailure
+	}
+	return result
+}
+
 func (bus *eventBus) notifySubscribers(topic string, result EventResult) {
 	foundSubscribers := bus.subscriberRegistry[topic]
 	if foundSubscribers == nil {
@@ -131,3 +336,11 @@ func (bus *eventBus) notifySubscribers(topic string, result EventResult) {
 		}
 	}
 }
+
+func eventSpanAttributes(event Event) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		semconv.MessagingSystemKey.String(messagingSystem),
+		semconv.MessagingDestinationKey.String(event.GetTopic()),
+		attribute.String("messaging.event_name", event.GetName()),
+	}
+}
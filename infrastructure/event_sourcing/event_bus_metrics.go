@@ -0,0 +1,41 @@
+package event_sourcing
+
+import "time"
+
+type EventBusMetrics interface {
+	EventPublished(event Event)
+	EventDropped(event Event)
+	EventDequeued(event Event)
+	HandlerStarted(event Event)
+	HandlerFinished(event Event, duration time.Duration, failureKind FailureKind)
+	WorkerPoolUsage(pool string, busy int, capacity int)
+	AbandonedHandlers(topic string, count int)
+}
+
+type noOpEventBusMetrics struct {
+}
+
+func NewNoOpEventBusMetrics() EventBusMetrics {
+	return &noOpEventBusMetrics{}
+}
+
+func (metrics *noOpEventBusMetrics) EventPublished(Event) {
+}
+
+func (metrics *noOpEventBusMetrics) EventDropped(Event) {
+}
+
+func (metrics *noOpEventBusMetrics) EventDequeued(Event) {
+}
+
+func (metrics *noOpEventBusMetrics) HandlerStarted(Event) {
+}
+
+func (metrics *noOpEventBusMetrics) HandlerFinished(Event, time.Duration, FailureKind) {
+}
+
+func (metrics *noOpEventBusMetrics) WorkerPoolUsage(string, int, int) {
+}
+
+func (metrics *noOpEventBusMetrics) AbandonedHandlers(string, int) {
+}
@@ -0,0 +1,273 @@
+package event_sourcing
+
+import (
+	"context"
+	"encoding/json"
+	"event-bus-demo/infrastructure/constants"
+	infrastructure "event-bus-demo/infrastructure/error"
+	"fmt"
+	"go.uber.org/zap"
+	"reflect"
+	"time"
+)
+
+const (
+	maxRecentFailures = 50
+	drainPollInterval = 50 * time.Millisecond
+)
+
+type EventBusStats struct {
+	BufferLength   int
+	BufferCapacity int
+	BusyWorkers    int
+	MaxWorkers     int
+	Topics         []TopicStats
+	RecentFailures []EventFailure
+}
+
+type TopicStats struct {
+	Name           string
+	Paused         bool
+	Held           bool
+	BufferLength   int
+	BufferCapacity int
+	// OverflowLength holds the number of events kept past the buffer while the topic is paused
+	OverflowLength int
+	// LaneLengths holds the number of buffered events by priority name
+	LaneLengths   map[string]int
+	PendingEvents int
+	BusyWorkers   int
+	MaxWorkers    int
+	Weight        int
+	// AbandonedHandlers holds the number of handlers still running past their timeout, they are part of BusyWorkers
+	AbandonedHandlers int
+}
+
+type EventFailure struct {
+	Topic     string
+	Event     string
+	Kind      FailureKind
+	Error     string
+	RequestID string
+	FailedAt  time.Time
+}
+
+func (bus *eventBus) Stats() EventBusStats {
+	topics := bus.Describe().Topics
+	for _, topic := range topics {
+		bus.getTopicQueue(topic.Name)
+	}
+	bus.mutex.Lock()
+	defer bus.mutex.Unlock()
+	stats := EventBusStats{
+		BusyWorkers:    bus.busyWorkers,
+		MaxWorkers:     bus.maxWorkers,
+		Topics:         make([]TopicStats, 0),
+		RecentFailures: make([]EventFailure, len(bus.recentFailures)),
+	}
+	for _, topic := range topics {
+		queue := bus.topicQueues[topic.Name]
+		stats.BufferLength += queue.bufferLength()
+		stats.BufferCapacity += queue.bufferCapacity()
+		lanes := make(map[string]int)
+		for priority, lane := range queue.lanes {
+			lanes[priority.String()] = len(lane)
+		}
+		stats.Topics = append(stats.Topics, TopicStats{
+			Name:              topic.Name,
+			Paused:            queue.paused,
+			Held:              bus.isTopicHeld(topic.Name),
+			BufferLength:      queue.bufferLength(),
+			BufferCapacity:    queue.bufferCapacity(),
+			OverflowLength:    queue.overflowLength(),
+			LaneLengths:       lanes,
+			PendingEvents:     queue.pending(),
+			BusyWorkers:       queue.busyWorkers,
+			MaxWorkers:        bus.topicMaxWorkers(topic.Name),
+			Weight:            bus.topicWeight(topic.Name),
+			AbandonedHandlers: queue.abandonedHandlers,
+		})
+	}
+	copy(stats.RecentFailures, bus.recentFailures)
+	return stats
+}
+
+// ConfigureTopic sets the worker limit and scheduling weight of a topic. Topics which are not configured can use every
+// worker of the bus and have a weight of 1.
+func (bus *eventBus) ConfigureTopic(topic string, options TopicOptions) {
+	bus.mutex.Lock()
+	bus.topicOptions[topic] = options
+	bus.mutex.Unlock()
+	bus.logger.Info("event bus topic configured", zap.String("topic", topic), zap.Int("max_workers", options.MaxWorkers),
+		zap.Int("weight", options.Weight), zap.Duration("handler_timeout", options.HandlerTimeout))
+	bus.wakeUp()
+}
+
+// SetHandlerTimeout sets the time a handler has to process an event before the event is reported as timed out. A zero
+// timeout lets handlers run for as long as they need.
+func (bus *eventBus) SetHandlerTimeout(timeout time.Duration) {
+	bus.mutex.Lock()
+	bus.handlerTimeout = timeout
+	bus.mutex.Unlock()
+	bus.logger.Info("event bus handler timeout changed", zap.Duration("handler_timeout", timeout))
+}
+
+func (bus *eventBus) topicHandlerTimeout(topic string) time.Duration {
+	bus.mutex.Lock()
+	defer bus.mutex.Unlock()
+	if options, ok := bus.topicOptions[topic]; ok && options.HandlerTimeout > 0 {
+		return options.HandlerTimeout
+	}
+	return bus.handlerTimeout
+}
+
+// ConfigurePriorities sets the priority of events by name and the starvation protection of the lower priority lanes.
+func (bus *eventBus) ConfigurePriorities(options PriorityOptions) {
+	bus.mutex.Lock()
+	bus.priorityOptions = options
+	bus.mutex.Unlock()
+	bus.logger.Info("event bus priorities configured", zap.Int("prioritized_events", len(options.Events)),
+		zap.Int("starvation_threshold", options.StarvationThreshold))
+}
+
+// eventPriority returns the priority configured for the event name, falling back to the priority of the event itself.
+func (bus *eventBus) eventPriority(event Event) Priority {
+	bus.mutex.Lock()
+	defer bus.mutex.Unlock()
+	if priority, ok := bus.priorityOptions.Events[event.GetName()]; ok {
+		return priority
+	} else if prioritizedEvent, ok := event.(PrioritizedEvent); ok && prioritizedEvent.GetPriority().isValid() {
+		return prioritizedEvent.GetPriority()
+	}
+	return NormalPriority
+}
+
+// topicMaxWorkers and topicWeight must be called while holding the bus mutex.
+func (bus *eventBus) topicMaxWorkers(topic string) int {
+	if options, ok := bus.topicOptions[topic]; ok && options.MaxWorkers > 0 {
+		return options.MaxWorkers
+	}
+	return bus.maxWorkers
+}
+
+func (bus *eventBus) topicWeight(topic string) int {
+	if options, ok := bus.topicOptions[topic]; ok && options.Weight > 0 {
+		return options.Weight
+	}
+	return defaultTopicWeight
+}
+
+// PauseTopic stops handling the events of the given topic. Events published meanwhile stay in the topic queue until
+// the topic is resumed, overflowing its buffer when it is full, while the events of other topics keep being handled.
+func (bus *eventBus) PauseTopic(topic string) infrastructure.InfrastructureError {
+	if !bus.isKnownTopic(topic) {
+		return infrastructure.NewItemNotFoundError(fmt.Sprintf("topic %s not found", topic))
+	}
+	queue := bus.getTopicQueue(topic)
+	bus.mutex.Lock()
+	queue.paused = true
+	bus.mutex.Unlock()
+	bus.logger.Info("event bus topic paused", zap.String("topic", topic))
+	return nil
+}
+
+func (bus *eventBus) ResumeTopic(topic string) infrastructure.InfrastructureError {
+	if !bus.isKnownTopic(topic) {
+		return infrastructure.NewItemNotFoundError(fmt.Sprintf("topic %s not found", topic))
+	}
+	queue := bus.getTopicQueue(topic)
+	bus.mutex.Lock()
+	queue.paused = false
+	for _, priority := range priorities {
+		queue.refill(priority)
+	}
+	pending := queue.pending()
+	bus.mutex.Unlock()
+	bus.logger.Info("event bus topic resumed", zap.String("topic", topic), zap.Int("pending_events", pending))
+	bus.wakeUp()
+	return nil
+}
+
+// DrainTopic waits until every event published on the topic so far has been handled. It fails when the topic is
+// paused, since its events would never be handled, or when ctx is done before the topic is drained.
+func (bus *eventBus) DrainTopic(ctx context.Context, topic string) infrastructure.InfrastructureError {
+	if !bus.isKnownTopic(topic) {
+		return infrastructure.NewItemNotFoundError(fmt.Sprintf("topic %s not found", topic))
+	}
+	queue := bus.getTopicQueue(topic)
+	ticker := time.NewTicker(drainPollInterval)
+	defer ticker.Stop()
+	for {
+		bus.mutex.Lock()
+		pending, paused := queue.pending(), queue.paused
+		bus.mutex.Unlock()
+		if pending == 0 {
+			break
+		} else if paused {
+			return infrastructure.NewInvalidArgumentError(fmt.Sprintf("topic %s is paused and cannot be drained", topic))
+		}
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return infrastructure.NewEventBusError(fmt.Sprintf("topic %s not drained: %s", topic, ctx.Err().Error()))
+		}
+	}
+	bus.logger.Info("event bus topic drained", zap.String("topic", topic))
+	return nil
+}
+
+func (bus *eventBus) SetMaxWorkers(maxWorkers int) infrastructure.InfrastructureError {
+	if maxWorkers < 1 {
+		return infrastructure.NewInvalidArgumentError("max workers must be greater than zero")
+	}
+	bus.mutex.Lock()
+	bus.maxWorkers = maxWorkers
+	bus.mutex.Unlock()
+	bus.logger.Info("event bus max workers changed", zap.Int("max_workers", maxWorkers))
+	bus.wakeUp()
+	return nil
+}
+
+// NewEvent builds an event of one of the registered event types from its JSON representation.
+func (bus *eventBus) NewEvent(name string, payload []byte) (Event, infrastructure.InfrastructureError) {
+	prototype, ok := bus.eventRegistry[name]
+	if !ok {
+		return nil, infrastructure.NewItemNotFoundError(fmt.Sprintf("event %s is not registered on the bus", name))
+	}
+	event := reflect.New(reflect.TypeOf(prototype))
+	if len(payload) > 0 {
+		if err := json.Unmarshal(payload, event.Interface()); err != nil {
+			return nil, infrastructure.NewInvalidArgumentError(fmt.Sprintf("invalid payload for event %s: %s", name, err.Error()))
+		}
+	}
+	return event.Elem().Interface().(Event), nil
+}
+
+func (bus *eventBus) isKnownTopic(topic string) bool {
+	for _, description := range bus.Describe().Topics {
+		if description.Name == topic {
+			return true
+		}
+	}
+	return false
+}
+
+func (bus *eventBus) recordFailure(result EventResult) {
+	failure := EventFailure{
+		Topic:     result.Event.GetTopic(),
+		Event:     result.Event.GetName(),
+		Kind:      result.FailureKind,
+		Error:     "event handling failed",
+		RequestID: result.Metadata[constants.RequestIDMetadataKey],
+		FailedAt:  time.Now(),
+	}
+	if result.Error != nil {
+		failure.Error = result.Error.Error()
+	}
+	bus.mutex.Lock()
+	defer bus.mutex.Unlock()
+	bus.recentFailures = append(bus.recentFailures, failure)
+	if len(bus.recentFailures) > maxRecentFailures {
+		bus.recentFailures = bus.recentFailures[len(bus.recentFailures)-maxRecentFailures:]
+	}
+}
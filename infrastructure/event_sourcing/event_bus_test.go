@@ -0,0 +1,262 @@
+package event_sourcing
+
+import (
+	"context"
+	infrastructure "event-bus-demo/infrastructure/error"
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+// resultRecorder keeps the results its topic subscribers are notified of.
+type resultRecorder struct {
+	mutex   sync.Mutex
+	results []EventResult
+}
+
+func (subscriber *resultRecorder) Notify(result EventResult) {
+	subscriber.mutex.Lock()
+	subscriber.results = append(subscriber.results, result)
+	subscriber.mutex.Unlock()
+}
+
+func (subscriber *resultRecorder) received() []EventResult {
+	subscriber.mutex.Lock()
+	defer subscriber.mutex.Unlock()
+	return append([]EventResult(nil), subscriber.results...)
+}
+
+// contextHandler returns as soon as its context is cancelled.
+type contextHandler struct {
+}
+
+func (handler *contextHandler) Handle(ctx context.Context, event Event) EventResult {
+	<-ctx.Done()
+	return EventResult{Event: event, Error: ctx.Err()}
+}
+
+func waitFor(t *testing.T, description string, condition func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", description)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func topicStats(bus *eventBus, topic string) TopicStats {
+	bus.mutex.Lock()
+	defer bus.mutex.Unlock()
+	queue := bus.topicQueues[topic]
+	return TopicStats{
+		Name:              topic,
+		PendingEvents:     queue.pending(),
+		BusyWorkers:       queue.busyWorkers,
+		AbandonedHandlers: queue.abandonedHandlers,
+	}
+}
+
+func busyWorkers(bus *eventBus) int {
+	bus.mutex.Lock()
+	defer bus.mutex.Unlock()
+	return bus.busyWorkers
+}
+
+func TestHandleWithTimeoutReleasesWorkerOnceHandlerReturns(t *testing.T) {
+	bus, _ := newTestEventBus(10, 1)
+	bus.SetHandlerTimeout(10 * time.Millisecond)
+	bus.handlerGracePeriod = time.Minute
+	subscriber := &resultRecorder{}
+	bus.RegisterHandler("A", &contextHandler{})
+	bus.RegisterSubscriber("A", subscriber)
+	bus.Publish(context.Background(), testEvent{topic: "A", name: "TestEvent"})
+	dispatch(bus, 1)
+	waitFor(t, "the timeout result", func() bool {
+		return len(subscriber.received()) == 1
+	})
+	result := subscriber.received()[0]
+	if result.Succeeded || result.FailureKind != TimeoutFailure {
+		t.Errorf("expected a timeout failure, got %+v", result)
+	}
+	waitFor(t, "the worker to be released", func() bool {
+		return busyWorkers(bus) == 0
+	})
+	if stats := topicStats(bus, "A"); stats.AbandonedHandlers != 0 {
+		t.Errorf("expected no abandoned handler, got %d", stats.AbandonedHandlers)
+	}
+}
+
+func TestHandleWithTimeoutWaitsGracePeriodForHandlerIgnoringContext(t *testing.T) {
+	bus, _ := newTestEventBus(10, 1)
+	bus.SetHandlerTimeout(10 * time.Millisecond)
+	bus.handlerGracePeriod = time.Minute
+	subscriber := &resultRecorder{}
+	blocking := &blockingHandler{release: make(chan struct{})}
+	bus.RegisterHandler("A", blocking)
+	bus.RegisterSubscriber("A", subscriber)
+	bus.Publish(context.Background(), testEvent{topic: "A", name: "TestEvent"})
+	dispatch(bus, 1)
+	time.Sleep(50 * time.Millisecond)
+	if received := subscriber.received(); len(received) != 0 {
+		t.Fatalf("expected the worker to wait for the handler during the grace period, got %+v", received)
+	}
+	close(blocking.release)
+	waitFor(t, "the timeout result", func() bool {
+		return len(subscriber.received()) == 1
+	})
+	if result := subscriber.received()[0]; result.Succeeded || result.FailureKind != TimeoutFailure {
+		t.Errorf("expected a timeout failure, got %+v", result)
+	}
+	waitFor(t, "the worker to be released", func() bool {
+		return busyWorkers(bus) == 0
+	})
+}
+
+func TestHandleWithTimeoutKeepsAbandonedHandlerWithinWorkerLimit(t *testing.T) {
+	bus, _ := newTestEventBus(10, 1)
+	bus.SetHandlerTimeout(10 * time.Millisecond)
+	bus.handlerGracePeriod = 10 * time.Millisecond
+	subscriber := &resultRecorder{}
+	blocking := &blockingHandler{release: make(chan struct{})}
+	bus.RegisterHandler("A", blocking)
+	bus.RegisterSubscriber("A", subscriber)
+	bus.Publish(context.Background(), testEvent{topic: "A", name: "TestEvent"})
+	bus.Publish(context.Background(), testEvent{topic: "A", name: "TestEvent"})
+	dispatch(bus, 1)
+	waitFor(t, "the timeout result", func() bool {
+		return len(subscriber.received()) == 1
+	})
+	// The worker which ran the handler is released, the abandoned handler keeps a worker of its own
+	waitFor(t, "the handler to be abandoned", func() bool {
+		stats := topicStats(bus, "A")
+		return stats.AbandonedHandlers == 1 && stats.BusyWorkers == 1 && busyWorkers(bus) == 1
+	})
+	if bus.dispatchNext() {
+		t.Fatal("expected no worker to be available while the handler is running")
+	}
+	close(blocking.release)
+	waitFor(t, "the abandoned handler to return", func() bool {
+		return topicStats(bus, "A").AbandonedHandlers == 0 && busyWorkers(bus) == 0
+	})
+	if !bus.dispatchNext() {
+		t.Error("expected the worker to be available once the abandoned handler returned")
+	}
+}
+
+func handledNames(results []EventResult) []string {
+	names := make([]string, 0, len(results))
+	for _, result := range results {
+		names = append(names, result.Event.GetName())
+	}
+	return names
+}
+
+func TestPausedTopicKeepsEventsPastItsBufferUntilResumed(t *testing.T) {
+	bus, _ := newTestEventBus(2, 1)
+	subscriber := &resultRecorder{}
+	released := &blockingHandler{release: make(chan struct{})}
+	close(released.release)
+	bus.RegisterHandler("A", released)
+	bus.RegisterSubscriber("A", subscriber)
+	bus.Run()
+	defer bus.Stop()
+	if err := bus.PauseTopic("A"); err != nil {
+		t.Fatalf("unexpected error %s", err.Error())
+	}
+	expected := make([]string, 0)
+	for i := 0; i < 10; i++ {
+		name := fmt.Sprintf("TestEvent%d", i)
+		expected = append(expected, name)
+		bus.Publish(context.Background(), testEvent{topic: "A", name: name})
+	}
+	stats := bus.Stats().Topics[0]
+	if !stats.Paused || stats.BufferLength != 2 || stats.OverflowLength != 8 || stats.PendingEvents != 10 {
+		t.Fatalf("expected 2 buffered and 8 overflowing events, got %+v", stats)
+	}
+	if err := bus.DrainTopic(context.Background(), "A"); err == nil || err.GetCode() != infrastructure.InvalidArgument {
+		t.Errorf("expected a paused topic not to be drained, got %v", err)
+	}
+	if received := subscriber.received(); len(received) != 0 {
+		t.Fatalf("expected no event of a paused topic to be handled, got %v", handledNames(received))
+	}
+
+	if err := bus.ResumeTopic("A"); err != nil {
+		t.Fatalf("unexpected error %s", err.Error())
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := bus.DrainTopic(ctx, "A"); err != nil {
+		t.Fatalf("unexpected error %s", err.Error())
+	}
+	waitFor(t, "the results of every event", func() bool {
+		return len(subscriber.received()) == len(expected)
+	})
+	if actual := handledNames(subscriber.received()); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected the events to be handled in publication order %v, got %v", expected, actual)
+	}
+	if stats := topicStats(bus, "A"); stats.PendingEvents != 0 {
+		t.Errorf("expected a drained topic to have no pending event, got %d", stats.PendingEvents)
+	}
+}
+
+func TestResumedTopicKeepsEventsUntilOverflowIsEmpty(t *testing.T) {
+	bus, metrics := newTestEventBus(2, 100)
+	bus.RegisterHandler("A", &blockingHandler{release: make(chan struct{})})
+	if err := bus.PauseTopic("A"); err != nil {
+		t.Fatalf("unexpected error %s", err.Error())
+	}
+	for i := 0; i < 4; i++ {
+		bus.Publish(context.Background(), testEvent{topic: "A", name: fmt.Sprintf("TestEvent%d", i)})
+	}
+	if err := bus.ResumeTopic("A"); err != nil {
+		t.Fatalf("unexpected error %s", err.Error())
+	}
+	dispatch(bus, 1)
+	// The lane is full again with the events taken from the overflow, which the event joins instead of being dropped
+	bus.Publish(context.Background(), testEvent{topic: "A", name: "TestEvent4"})
+	dispatch(bus, 10)
+	expected := []string{"TestEvent0", "TestEvent1", "TestEvent2", "TestEvent3", "TestEvent4"}
+	actual := make([]string, 0)
+	for _, event := range metrics.dequeued() {
+		actual = append(actual, event.GetName())
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected the events in publication order %v, got %v", expected, actual)
+	}
+}
+
+func TestRunningTopicDropsEventsPastItsBuffer(t *testing.T) {
+	bus, _ := newTestEventBus(2, 1)
+	bus.RegisterHandler("A", &blockingHandler{release: make(chan struct{})})
+	for i := 0; i < 5; i++ {
+		bus.Publish(context.Background(), testEvent{topic: "A", name: "TestEvent"})
+	}
+	if stats := bus.Stats().Topics[0]; stats.PendingEvents != 2 || stats.OverflowLength != 0 {
+		t.Errorf("expected the events past the buffer of a topic not paused to be dropped, got %+v", stats)
+	}
+}
+
+func TestTopicControlRejectsUnknownTopics(t *testing.T) {
+	bus, _ := newTestEventBus(2, 1)
+	bus.RegisterHandler("A", &blockingHandler{release: make(chan struct{})})
+	controls := map[string]func() infrastructure.InfrastructureError{
+		"pause": func() infrastructure.InfrastructureError {
+			return bus.PauseTopic("B")
+		},
+		"resume": func() infrastructure.InfrastructureError {
+			return bus.ResumeTopic("B")
+		},
+		"drain": func() infrastructure.InfrastructureError {
+			return bus.DrainTopic(context.Background(), "B")
+		},
+	}
+	for name, control := range controls {
+		if err := control(); err == nil || err.GetCode() != infrastructure.ItemNotFound {
+			t.Errorf("expected %s to reject an unknown topic, got %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,219 @@
+package event_sourcing
+
+import (
+	infrastructure "event-bus-demo/infrastructure/error"
+	"fmt"
+	"go.uber.org/zap"
+	"sort"
+	"strings"
+)
+
+const (
+	MermaidGraphFormat  = "mermaid"
+	GraphvizGraphFormat = "dot"
+)
+
+type EventBusDescription struct {
+	Topics []TopicDescription
+}
+
+type TopicDescription struct {
+	Name        string
+	Events      []string
+	Handlers    []HandlerDescription
+	Subscribers []string
+}
+
+type HandlerDescription struct {
+	Name string
+	// Events handled by the handler. It is nil for untyped handlers, which receive every event of the topic.
+	Events []string
+}
+
+func (bus *eventBus) RegisterEvents(events ...Event) {
+	for _, event := range events {
+		bus.eventRegistry[event.GetName()] = event
+	}
+}
+
+func (bus *eventBus) Describe() EventBusDescription {
+	topicNames := make(map[string]bool)
+	for topic := range bus.handlerRegistry {
+		topicNames[topic] = true
+	}
+	for topic := range bus.subscriberRegistry {
+		topicNames[topic] = true
+	}
+	for _, event := range bus.eventRegistry {
+		topicNames[event.GetTopic()] = true
+	}
+	topics := make([]TopicDescription, 0, len(topicNames))
+	for topic := range topicNames {
+		topics = append(topics, bus.describeTopic(topic))
+	}
+	sort.Slice(topics, func(i, j int) bool {
+		return topics[i].Name < topics[j].Name
+	})
+	return EventBusDescription{
+		Topics: topics,
+	}
+}
+
+func (bus *eventBus) describeTopic(topic string) TopicDescription {
+	description := TopicDescription{
+		Name:        topic,
+		Events:      make([]string, 0),
+		Handlers:    make([]HandlerDescription, 0),
+		Subscribers: make([]string, 0),
+	}
+	for name, event := range bus.eventRegistry {
+		if event.GetTopic() == topic {
+			description.Events = append(description.Events, name)
+		}
+	}
+	sort.Strings(description.Events)
+	for index, handler := range bus.handlerRegistry[topic] {
+		handlerDescription := HandlerDescription{
+			Name: fmt.Sprintf("%s handler #%d", topic, index+1),
+		}
+		if typedHandler, ok := unwrapTypedEventHandler(handler); ok {
+			handlerDescription.Events = typedHandler.GetHandledEvents()
+		}
+		description.Handlers = append(description.Handlers, handlerDescription)
+	}
+	for _, subscriber := range bus.subscriberRegistry[topic] {
+		description.Subscribers = append(description.Subscribers, fmt.Sprintf("%T", subscriber))
+	}
+	return description
+}
+
+// Validate checks that every registered event is processed by exactly one handler of its topic, that typed handlers
+// are registered on their own topic and that every configured topic and prioritized event exists. Topics without subscribers and handled
+// events that were never registered are reported as warnings without failing the validation.
+func (bus *eventBus) Validate() infrastructure.InfrastructureError {
+	problems := make([]string, 0)
+	for topic, handlers := range bus.handlerRegistry {
+		for _, handler := range handlers {
+			if typedHandler, ok := unwrapTypedEventHandler(handler); ok && typedHandler.GetTopic() != topic {
+				problems = append(problems, fmt.Sprintf("handler for topic %s is registered on topic %s",
+					typedHandler.GetTopic(), topic))
+			}
+		}
+	}
+	eventNames := make([]string, 0, len(bus.eventRegistry))
+	for name := range bus.eventRegistry {
+		eventNames = append(eventNames, name)
+	}
+	sort.Strings(eventNames)
+	for _, name := range eventNames {
+		event := bus.eventRegistry[name]
+		if count := bus.countHandlers(event); count != 1 {
+			problems = append(problems, fmt.Sprintf("event %s on topic %s has %d handlers, expected exactly 1",
+				name, event.GetTopic(), count))
+		}
+	}
+	for name := range bus.priorityOptions.Events {
+		if _, ok := bus.eventRegistry[name]; !ok {
+			problems = append(problems, fmt.Sprintf("priority is configured for event %s which is not registered", name))
+		}
+	}
+	for topic := range bus.topicOptions {
+		if !bus.isKnownTopic(topic) {
+			problems = append(problems, fmt.Sprintf("topic %s is configured but has no events, handlers or subscribers", topic))
+		}
+	}
+	for _, topic := range bus.Describe().Topics {
+		if len(topic.Subscribers) == 0 {
+			bus.logger.Warn("no bus subscribers registered for topic", zap.String("topic", topic.Name))
+		}
+		for _, handler := range topic.Handlers {
+			for _, name := range handler.Events {
+				if _, ok := bus.eventRegistry[name]; !ok {
+					bus.logger.Warn("bus handler handles an event which is not registered on the bus",
+						zap.String("handler", handler.Name), zap.String("event", name))
+				}
+			}
+		}
+	}
+	if len(problems) > 0 {
+		return infrastructure.NewEventBusError(fmt.Sprintf("invalid event bus wiring: %s", strings.Join(problems, "; ")))
+	}
+	return nil
+}
+
+func (bus *eventBus) countHandlers(event Event) int {
+	count := 0
+	for _, handler := range bus.handlerRegistry[event.GetTopic()] {
+		if typedHandler, ok := unwrapTypedEventHandler(handler); !ok || typedHandler.CanHandle(event) {
+			count++
+		}
+	}
+	return count
+}
+
+func RenderWiringGraph(description EventBusDescription, format string) (string, error) {
+	switch format {
+	case MermaidGraphFormat:
+		return renderMermaidGraph(description), nil
+	case GraphvizGraphFormat:
+		return renderGraphvizGraph(description), nil
+	default:
+		return "", fmt.Errorf("unknown graph format %s, expected one of %s, %s", format, MermaidGraphFormat, GraphvizGraphFormat)
+	}
+}
+
+func renderMermaidGraph(description EventBusDescription) string {
+	var builder strings.Builder
+	builder.WriteString("flowchart LR\n")
+	for _, topic := range description.Topics {
+		topicNode := graphNodeID("topic", topic.Name)
+		builder.WriteString(fmt.Sprintf("    %s[[\"%s\"]]\n", topicNode, topic.Name))
+		for _, event := range topic.Events {
+			builder.WriteString(fmt.Sprintf("    %s(\"%s\") --> %s\n", graphNodeID("event", event), event, topicNode))
+		}
+		for _, handler := range topic.Handlers {
+			handlerNode := graphNodeID("handler", handler.Name)
+			builder.WriteString(fmt.Sprintf("    %s --> %s[\"%s\"]\n", topicNode, handlerNode, handler.Name))
+			for _, subscriber := range topic.Subscribers {
+				builder.WriteString(fmt.Sprintf("    %s -. result .-> %s[\"%s\"]\n", handlerNode,
+					graphNodeID("subscriber", subscriber), subscriber))
+			}
+		}
+	}
+	return builder.String()
+}
+
+func renderGraphvizGraph(description EventBusDescription) string {
+	var builder strings.Builder
+	builder.WriteString("digraph event_bus {\n    rankdir=LR;\n")
+	for _, topic := range description.Topics {
+		topicNode := graphNodeID("topic", topic.Name)
+		builder.WriteString(fmt.Sprintf("    %s [label=\"%s\", shape=box3d];\n", topicNode, topic.Name))
+		for _, event := range topic.Events {
+			eventNode := graphNodeID("event", event)
+			builder.WriteString(fmt.Sprintf("    %s [label=\"%s\", shape=ellipse];\n", eventNode, event))
+			builder.WriteString(fmt.Sprintf("    %s -> %s;\n", eventNode, topicNode))
+		}
+		for _, handler := range topic.Handlers {
+			handlerNode := graphNodeID("handler", handler.Name)
+			builder.WriteString(fmt.Sprintf("    %s [label=\"%s\", shape=box];\n", handlerNode, handler.Name))
+			builder.WriteString(fmt.Sprintf("    %s -> %s;\n", topicNode, handlerNode))
+			for _, subscriber := range topic.Subscribers {
+				subscriberNode := graphNodeID("subscriber", subscriber)
+				builder.WriteString(fmt.Sprintf("    %s [label=\"%s\", shape=note];\n", subscriberNode, subscriber))
+				builder.WriteString(fmt.Sprintf("    %s -> %s [label=\"result\", style=dashed];\n", handlerNode, subscriberNode))
+			}
+		}
+	}
+	builder.WriteString("}\n")
+	return builder.String()
+}
+
+func graphNodeID(kind, name string) string {
+	return kind + "_" + strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return '_'
+	}, name)
+}
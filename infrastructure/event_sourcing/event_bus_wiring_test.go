@@ -0,0 +1,124 @@
+package event_sourcing
+
+import (
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	"strings"
+	"testing"
+)
+
+// newWiredTestBus builds a bus whose topic A has its event, a typed handler for it and a subscriber, and logs into the
+// returned observer.
+func newWiredTestBus(t *testing.T) (*eventBus, *observer.ObservedLogs) {
+	core, logs := observer.New(zapcore.WarnLevel)
+	bus := NewEventBus(10, 1, &noOpEventBusMetrics{}, zap.New(core)).(*eventBus)
+	handler := NewTypedEventHandler("A", zap.NewNop())
+	if err := Handle(handler, ignore[topicAEvent]); err != nil {
+		t.Fatalf("unexpected error %s", err.Error())
+	}
+	bus.RegisterEvents(topicAEvent{})
+	bus.RegisterHandler("A", handler)
+	bus.RegisterSubscriber("A", &resultRecorder{})
+	return bus, logs
+}
+
+func TestValidateAcceptsCompleteWiring(t *testing.T) {
+	bus, logs := newWiredTestBus(t)
+	bus.ConfigureTopic("A", TopicOptions{MaxWorkers: 1})
+	bus.ConfigurePriorities(PriorityOptions{Events: map[string]Priority{"TopicAEvent": HighPriority}})
+	if err := bus.Validate(); err != nil {
+		t.Errorf("unexpected error %s", err.Error())
+	}
+	if logs.Len() != 0 {
+		t.Errorf("expected no warning, got %v", logs.All())
+	}
+}
+
+func TestValidateRejectsIncompleteWiring(t *testing.T) {
+	tests := []struct {
+		name     string
+		wire     func(bus *eventBus)
+		expected string
+	}{
+		{
+			name: "event without handler",
+			wire: func(bus *eventBus) {
+				bus.RegisterEvents(topicBEvent{})
+			},
+			expected: "event TopicBEvent on topic B has 0 handlers, expected exactly 1",
+		},
+		{
+			name: "event with two handlers",
+			wire: func(bus *eventBus) {
+				bus.RegisterHandler("A", &blockingHandler{})
+			},
+			expected: "event TopicAEvent on topic A has 2 handlers, expected exactly 1",
+		},
+		{
+			name: "handler on another topic",
+			wire: func(bus *eventBus) {
+				bus.RegisterHandler("C", NewTypedEventHandler("B", zap.NewNop()))
+			},
+			expected: "handler for topic B is registered on topic C",
+		},
+		{
+			name: "priority of unregistered event",
+			wire: func(bus *eventBus) {
+				bus.ConfigurePriorities(PriorityOptions{Events: map[string]Priority{"UnknownEvent": LowPriority}})
+			},
+			expected: "priority is configured for event UnknownEvent which is not registered",
+		},
+		{
+			name: "configured unknown topic",
+			wire: func(bus *eventBus) {
+				bus.ConfigureTopic("D", TopicOptions{MaxWorkers: 1})
+			},
+			expected: "topic D is configured but has no events, handlers or subscribers",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			bus, _ := newWiredTestBus(t)
+			test.wire(bus)
+			if err := bus.Validate(); err == nil || !strings.Contains(err.GetMessage(), test.expected) {
+				t.Errorf("expected an error containing %q, got %v", test.expected, err)
+			}
+		})
+	}
+}
+
+func TestValidateWarnsAboutTopicsWithoutSubscribers(t *testing.T) {
+	bus, logs := newWiredTestBus(t)
+	handler := NewTypedEventHandler("B", zap.NewNop())
+	if err := Handle(handler, ignore[topicBEvent]); err != nil {
+		t.Fatalf("unexpected error %s", err.Error())
+	}
+	bus.RegisterEvents(topicBEvent{})
+	bus.RegisterHandler("B", handler)
+	if err := bus.Validate(); err != nil {
+		t.Fatalf("expected a topic without subscribers to be valid, got %s", err.Error())
+	}
+	warnings := logs.FilterMessage("no bus subscribers registered for topic").All()
+	if len(warnings) != 1 || warnings[0].ContextMap()["topic"] != "B" {
+		t.Errorf("expected a warning for topic B, got %v", logs.All())
+	}
+}
+
+func TestValidateWarnsAboutHandledEventsNotRegistered(t *testing.T) {
+	core, logs := observer.New(zapcore.WarnLevel)
+	bus := NewEventBus(10, 1, &noOpEventBusMetrics{}, zap.New(core)).(*eventBus)
+	handler := NewTypedEventHandler("A", zap.NewNop())
+	if err := Handle(handler, ignore[topicAEvent]); err != nil {
+		t.Fatalf("unexpected error %s", err.Error())
+	}
+	bus.RegisterHandler("A", handler)
+	bus.RegisterSubscriber("A", &resultRecorder{})
+	if err := bus.Validate(); err != nil {
+		t.Fatalf("unexpected error %s", err.Error())
+	}
+	warnings := logs.FilterMessage("bus handler handles an event which is not registered on the bus").All()
+	if len(warnings) != 1 || warnings[0].ContextMap()["event"] != "TopicAEvent" {
+		t.Errorf("expected a warning for TopicAEvent, got %v", logs.All())
+	}
+}
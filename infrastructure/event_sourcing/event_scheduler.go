@@ -0,0 +1,149 @@
+package event_sourcing
+
+import (
+	"go.uber.org/zap"
+	"time"
+)
+
+// schedule hands buffered events to workers until the bus is stopped. Events of the highest priority lane go first,
+// with the lower lanes being served once they have been skipped PriorityOptions.StarvationThreshold times. Topics share the global worker budget with
+// weighted fair queuing: every event taken from a topic advances the virtual time of the topic by the inverse of its
+// weight, and the next free worker goes to the topic with the lowest virtual time among the ones with buffered events,
+// not paused and below their own worker limit. While backlogged, a topic with weight 3 gets three workers for every
+// one granted to a topic with weight 1.
+func (bus *eventBus) schedule() {
+	ticker := time.NewTicker(holdPollInterval)
+	defer ticker.Stop()
+	for {
+		for bus.dispatchNext() {
+		}
+		select {
+		case <-bus.wakeUpChannel:
+		case <-ticker.C:
+		case <-bus.quitSignalChannel:
+			bus.logger.Info("event bus signaled to stop")
+			return
+		}
+	}
+}
+
+// wakeUp tells the scheduler that an event may be ready to be dispatched.
+func (bus *eventBus) wakeUp() {
+	select {
+	case bus.wakeUpChannel <- struct{}{}:
+	default:
+	}
+}
+
+func (bus *eventBus) dispatchNext() bool {
+	bus.mutex.Lock()
+	defer bus.mutex.Unlock()
+	if bus.busyWorkers >= bus.maxWorkers {
+		return false
+	}
+	candidates := make(map[Priority]*topicQueue)
+	for _, priority := range priorities {
+		if queue := bus.selectTopicQueue(priority); queue != nil {
+			candidates[priority] = queue
+		}
+	}
+	priority, ok := bus.selectLane(candidates)
+	if !ok {
+		return false
+	}
+	selected := candidates[priority]
+	var envelope EventEnvelope
+	select {
+	case envelope = <-selected.lanes[priority]:
+	default:
+		return false
+	}
+	selected.refill(priority)
+	for waiting := range candidates {
+		if waiting < priority {
+			bus.laneSkips[waiting]++
+		}
+	}
+	bus.laneSkips[priority] = 0
+	bus.logger.Debug("new event received", zap.Any("event", envelope.Event), zap.Stringer("priority", priority))
+	bus.metrics.EventDequeued(envelope.Event)
+	bus.virtualTime = selected.virtualTime
+	selected.virtualTime += 1 / float64(bus.topicWeight(selected.topic))
+	selected.busyWorkers++
+	bus.busyWorkers++
+	bus.reportWorkerUsage(selected)
+	go bus.process(selected, envelope)
+	return true
+}
+
+// selectTopicQueue returns the topic with the lowest virtual time among the ones which can handle an event of the given
+// priority lane right now.
+func (bus *eventBus) selectTopicQueue(priority Priority) *topicQueue {
+	var selected *topicQueue
+	for _, queue := range bus.topicQueues {
+		if queue.paused || len(queue.lanes[priority]) == 0 || queue.busyWorkers >= bus.topicMaxWorkers(queue.topic) ||
+			bus.isTopicHeld(queue.topic) {
+			continue
+		}
+		if selected == nil || queue.virtualTime < selected.virtualTime ||
+			(queue.virtualTime == selected.virtualTime && queue.topic < selected.topic) {
+			selected = queue
+		}
+	}
+	return selected
+}
+
+// selectLane returns the highest priority lane with events to dispatch, unless a lower priority lane has been skipped
+// too many times in a row.
+func (bus *eventBus) selectLane(candidates map[Priority]*topicQueue) (Priority, bool) {
+	for _, priority := range priorities {
+		if _, ok := candidates[priority]; ok && bus.laneSkips[priority] >= bus.priorityOptions.StarvationThreshold &&
+			bus.priorityOptions.StarvationThreshold > 0 {
+			return priority, true
+		}
+	}
+	for _, priority := range priorities {
+		if _, ok := candidates[priority]; ok {
+			return priority, true
+		}
+	}
+	return NormalPriority, false
+}
+
+func (bus *eventBus) process(queue *topicQueue, envelope EventEnvelope) {
+	defer func() {
+		bus.mutex.Lock()
+		queue.busyWorkers--
+		bus.busyWorkers--
+		bus.reportWorkerUsage(queue)
+		bus.mutex.Unlock()
+		bus.wakeUp()
+	}()
+	bus.handleEvent(envelope)
+}
+
+// eventQueued keeps a topic which was idle from accumulating credit over the topics which kept the workers busy.
+func (bus *eventBus) eventQueued(queue *topicQueue) {
+	bus.mutex.Lock()
+	if queue.pending() == 1 && queue.virtualTime < bus.virtualTime {
+		queue.virtualTime = bus.virtualTime
+	}
+	bus.mutex.Unlock()
+	bus.wakeUp()
+}
+
+// isTopicHeld tells whether a handler of the topic asked to keep its events buffered.
+func (bus *eventBus) isTopicHeld(topic string) bool {
+	for _, handler := range bus.handlerRegistry[topic] {
+		if holdingHandler, ok := handler.(HoldingEventHandler); ok && holdingHandler.IsHolding() {
+			return true
+		}
+	}
+	return false
+}
+
+// reportWorkerUsage must be called while holding the bus mutex.
+func (bus *eventBus) reportWorkerUsage(queue *topicQueue) {
+	bus.metrics.WorkerPoolUsage(queue.topic, queue.busyWorkers, bus.topicMaxWorkers(queue.topic))
+	bus.metrics.WorkerPoolUsage(GlobalWorkerPool, bus.busyWorkers, bus.maxWorkers)
+}
@@ -0,0 +1,252 @@
+package event_sourcing
+
+import (
+	"context"
+	"go.uber.org/zap"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+type testEvent struct {
+	topic    string
+	name     string
+	priority Priority
+}
+
+func (event testEvent) GetTopic() string {
+	return event.topic
+}
+
+func (event testEvent) GetName() string {
+	return event.name
+}
+
+func (event testEvent) GetPriority() Priority {
+	return event.priority
+}
+
+// dequeueRecorder keeps the events in the order the scheduler hands them to workers.
+type dequeueRecorder struct {
+	noOpEventBusMetrics
+	mutex  sync.Mutex
+	events []Event
+}
+
+func (metrics *dequeueRecorder) EventDequeued(event Event) {
+	metrics.mutex.Lock()
+	metrics.events = append(metrics.events, event)
+	metrics.mutex.Unlock()
+}
+
+func (metrics *dequeueRecorder) dequeued() []Event {
+	metrics.mutex.Lock()
+	defer metrics.mutex.Unlock()
+	return append([]Event(nil), metrics.events...)
+}
+
+// newTestEventBus builds a bus which is not running, so the tests call dispatchNext themselves and the order of the
+// dispatched events does not depend on the scheduler goroutine.
+func newTestEventBus(bufferSize int, maxWorkers int) (*eventBus, *dequeueRecorder) {
+	metrics := &dequeueRecorder{}
+	bus := NewEventBus(bufferSize, maxWorkers, metrics, zap.NewNop()).(*eventBus)
+	return bus, metrics
+}
+
+// dispatch hands at most count events to workers and returns how many were dispatched.
+func dispatch(bus *eventBus, count int) int {
+	dispatched := 0
+	for dispatched < count && bus.dispatchNext() {
+		dispatched++
+	}
+	return dispatched
+}
+
+func countByTopic(events []Event) map[string]int {
+	counts := make(map[string]int)
+	for _, event := range events {
+		counts[event.GetTopic()]++
+	}
+	return counts
+}
+
+func TestDispatchNextSharesWorkersByTopicWeight(t *testing.T) {
+	tests := []struct {
+		name     string
+		weights  map[string]int
+		dequeues int
+		expected map[string]int
+	}{
+		{
+			name:     "same weight",
+			weights:  map[string]int{"A": 1, "B": 1},
+			dequeues: 20,
+			expected: map[string]int{"A": 10, "B": 10},
+		},
+		{
+			name:     "three to one",
+			weights:  map[string]int{"A": 3, "B": 1},
+			dequeues: 40,
+			expected: map[string]int{"A": 30, "B": 10},
+		},
+		{
+			name:     "default weight",
+			weights:  map[string]int{"A": 0, "B": 4},
+			dequeues: 50,
+			expected: map[string]int{"A": 10, "B": 40},
+		},
+		{
+			name:     "three topics",
+			weights:  map[string]int{"A": 1, "B": 2, "C": 3},
+			dequeues: 60,
+			expected: map[string]int{"A": 10, "B": 20, "C": 30},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			bus, metrics := newTestEventBus(100, 1000)
+			for topic, weight := range test.weights {
+				bus.ConfigureTopic(topic, TopicOptions{Weight: weight})
+				for i := 0; i < 100; i++ {
+					bus.Publish(context.Background(), testEvent{topic: topic, name: "TestEvent"})
+				}
+			}
+			if dispatched := dispatch(bus, test.dequeues); dispatched != test.dequeues {
+				t.Fatalf("expected %d dispatched events, got %d", test.dequeues, dispatched)
+			}
+			counts := countByTopic(metrics.dequeued())
+			for topic, expected := range test.expected {
+				if counts[topic] != expected {
+					t.Errorf("expected %d events of topic %s, got %d (%v)", expected, topic, counts[topic], counts)
+				}
+			}
+		})
+	}
+}
+
+func TestDispatchNextKeepsIdleTopicFromAccumulatingCredit(t *testing.T) {
+	bus, metrics := newTestEventBus(100, 1000)
+	for i := 0; i < 20; i++ {
+		bus.Publish(context.Background(), testEvent{topic: "A", name: "TestEvent"})
+	}
+	dispatch(bus, 10)
+	// B was idle while A was served, it must share the workers from now on instead of catching up on A
+	for i := 0; i < 20; i++ {
+		bus.Publish(context.Background(), testEvent{topic: "B", name: "TestEvent"})
+	}
+	dispatch(bus, 10)
+	counts := countByTopic(metrics.dequeued()[10:])
+	if counts["A"] != 5 || counts["B"] != 5 {
+		t.Errorf("expected 5 events of each topic once both are backlogged, got %v", counts)
+	}
+}
+
+func TestDispatchNextRespectsWorkerLimits(t *testing.T) {
+	bus, metrics := newTestEventBus(100, 3)
+	bus.ConfigureTopic("A", TopicOptions{MaxWorkers: 1})
+	blocking := &blockingHandler{release: make(chan struct{})}
+	bus.RegisterHandler("A", blocking)
+	bus.RegisterHandler("B", blocking)
+	for i := 0; i < 5; i++ {
+		bus.Publish(context.Background(), testEvent{topic: "A", name: "TestEvent"})
+		bus.Publish(context.Background(), testEvent{topic: "B", name: "TestEvent"})
+	}
+	if dispatched := dispatch(bus, 10); dispatched != 3 {
+		t.Fatalf("expected the global limit of 3 workers to be used, got %d", dispatched)
+	}
+	counts := countByTopic(metrics.dequeued())
+	if counts["A"] != 1 || counts["B"] != 2 {
+		t.Errorf("expected 1 worker for A and 2 for B, got %v", counts)
+	}
+	close(blocking.release)
+}
+
+// blockingHandler keeps its workers busy until released.
+type blockingHandler struct {
+	release chan struct{}
+}
+
+func (handler *blockingHandler) Handle(_ context.Context, event Event) EventResult {
+	<-handler.release
+	return EventResult{Succeeded: true, Event: event}
+}
+
+func prioritiesOf(events []Event) []Priority {
+	result := make([]Priority, 0, len(events))
+	for _, event := range events {
+		result = append(result, event.(testEvent).priority)
+	}
+	return result
+}
+
+func TestDispatchNextServesHigherPriorityLanesFirst(t *testing.T) {
+	bus, metrics := newTestEventBus(10, 100)
+	for _, priority := range []Priority{LowPriority, NormalPriority, HighPriority, NormalPriority, LowPriority, HighPriority} {
+		bus.Publish(context.Background(), testEvent{topic: "A", name: "TestEvent", priority: priority})
+	}
+	dispatch(bus, 6)
+	expected := []Priority{HighPriority, HighPriority, NormalPriority, NormalPriority, LowPriority, LowPriority}
+	if actual := prioritiesOf(metrics.dequeued()); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected lanes %v, got %v", expected, actual)
+	}
+}
+
+func TestDispatchNextUsesPriorityConfiguredByName(t *testing.T) {
+	bus, metrics := newTestEventBus(10, 100)
+	bus.ConfigurePriorities(PriorityOptions{Events: map[string]Priority{"UrgentEvent": HighPriority}})
+	bus.Publish(context.Background(), testEvent{topic: "A", name: "TestEvent", priority: HighPriority})
+	bus.Publish(context.Background(), testEvent{topic: "A", name: "UrgentEvent", priority: LowPriority})
+	dispatch(bus, 2)
+	dequeued := metrics.dequeued()
+	if len(dequeued) != 2 || dequeued[0].GetName() != "TestEvent" || dequeued[1].GetName() != "UrgentEvent" {
+		t.Errorf("expected both events in the high lane in publication order, got %v", dequeued)
+	}
+	bus.Publish(context.Background(), testEvent{topic: "A", name: "TestEvent", priority: LowPriority})
+	bus.Publish(context.Background(), testEvent{topic: "A", name: "UrgentEvent", priority: LowPriority})
+	dispatch(bus, 2)
+	if dequeued := metrics.dequeued(); dequeued[2].GetName() != "UrgentEvent" {
+		t.Errorf("expected the configured priority to take precedence over the event one, got %v", dequeued[2:])
+	}
+}
+
+func TestDispatchNextPromotesStarvedLanes(t *testing.T) {
+	tests := []struct {
+		name      string
+		threshold int
+		published []Priority
+		expected  []Priority
+	}{
+		{
+			name:      "disabled",
+			threshold: 0,
+			published: []Priority{LowPriority, HighPriority, HighPriority, HighPriority, HighPriority},
+			expected:  []Priority{HighPriority, HighPriority, HighPriority, HighPriority, LowPriority},
+		},
+		{
+			name:      "promoted after threshold",
+			threshold: 2,
+			published: []Priority{LowPriority, LowPriority, HighPriority, HighPriority, HighPriority, HighPriority, HighPriority},
+			expected: []Priority{HighPriority, HighPriority, LowPriority, HighPriority, HighPriority, LowPriority,
+				HighPriority},
+		},
+		{
+			name:      "highest starved lane first",
+			threshold: 1,
+			published: []Priority{LowPriority, NormalPriority, HighPriority, HighPriority},
+			expected:  []Priority{HighPriority, NormalPriority, LowPriority, HighPriority},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			bus, metrics := newTestEventBus(10, 100)
+			bus.ConfigurePriorities(PriorityOptions{StarvationThreshold: test.threshold})
+			for _, priority := range test.published {
+				bus.Publish(context.Background(), testEvent{topic: "A", name: "TestEvent", priority: priority})
+			}
+			dispatch(bus, len(test.published))
+			if actual := prioritiesOf(metrics.dequeued()); !reflect.DeepEqual(actual, test.expected) {
+				t.Errorf("expected lanes %v, got %v", test.expected, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package event_sourcing
+
+import "fmt"
+
+type Priority int
+
+const (
+	LowPriority Priority = iota
+	NormalPriority
+	HighPriority
+)
+
+const (
+	lowPriorityName    = "low"
+	normalPriorityName = "normal"
+	highPriorityName   = "high"
+)
+
+// priorities lists every priority from the highest to the lowest one.
+var priorities = []Priority{HighPriority, NormalPriority, LowPriority}
+
+// PrioritizedEvent is implemented by events which are not handled with the normal priority by default. The priority
+// configured on the bus for the event name takes precedence.
+type PrioritizedEvent interface {
+	Event
+	GetPriority() Priority
+}
+
+// PriorityOptions sets the priority of events by name. Once StarvationThreshold events have been taken from higher
+// priority lanes while an event of a lower priority lane was waiting, the next worker goes to the lower priority lane.
+type PriorityOptions struct {
+	Events              map[string]Priority
+	StarvationThreshold int
+}
+
+func ParsePriority(name string) (Priority, error) {
+	switch name {
+	case lowPriorityName:
+		return LowPriority, nil
+	case normalPriorityName:
+		return NormalPriority, nil
+	case highPriorityName:
+		return HighPriority, nil
+	default:
+		return NormalPriority, fmt.Errorf("unknown priority %s, expected one of %s, %s, %s", name, highPriorityName,
+			normalPriorityName, lowPriorityName)
+	}
+}
+
+func (priority Priority) String() string {
+	switch priority {
+	case LowPriority:
+		return lowPriorityName
+	case HighPriority:
+		return highPriorityName
+	default:
+		return normalPriorityName
+	}
+}
+
+func (priority Priority) isValid() bool {
+	return priority >= LowPriority && priority <= HighPriority
+}
@@ -0,0 +1,104 @@
+package event_sourcing
+
+import "time"
+
+const defaultTopicWeight = 1
+
+// TopicOptions limits the workers a topic can use and sets its share of the global worker budget when several topics
+// are competing for it. HandlerTimeout overrides the handler timeout of the bus for the topic when it is not zero.
+type TopicOptions struct {
+	MaxWorkers     int
+	Weight         int
+	HandlerTimeout time.Duration
+}
+
+// topicQueue buffers the events of a single topic, so a paused or slow topic does not hold back the others. Events are
+// kept in one lane per priority. Apart from the lanes, its fields are guarded by the bus mutex. Abandoned handlers are
+// counted in busyWorkers as well.
+//
+// The events published on a paused topic whose lane is full are kept in the overflow of the lane, which has no limit,
+// so pausing a topic loses no event. The overflow moves to the lane as workers free room in it once the topic is
+// resumed, the events published meanwhile joining the overflow rather than being dropped until it is empty.
+type topicQueue struct {
+	topic             string
+	lanes             map[Priority]EventBusChannel
+	overflows         map[Priority][]EventEnvelope
+	paused            bool
+	busyWorkers       int
+	abandonedHandlers int
+	virtualTime       float64
+}
+
+func newTopicQueue(topic string, bufferSize int) *topicQueue {
+	lanes := make(map[Priority]EventBusChannel)
+	for _, priority := range priorities {
+		lanes[priority] = NewBufferedEventChannel(bufferSize)
+	}
+	return &topicQueue{
+		topic:     topic,
+		lanes:     lanes,
+		overflows: make(map[Priority][]EventEnvelope),
+	}
+}
+
+// enqueue buffers the event in its lane, or in the overflow of the lane as explained on topicQueue. It returns false
+// when the event has to be dropped. It must be called while holding the bus mutex.
+func (queue *topicQueue) enqueue(envelope EventEnvelope) bool {
+	overflow := queue.overflows[envelope.Priority]
+	if len(overflow) == 0 {
+		select {
+		case queue.lanes[envelope.Priority] <- envelope:
+			return true
+		default:
+		}
+		if !queue.paused {
+			return false
+		}
+	}
+	queue.overflows[envelope.Priority] = append(overflow, envelope)
+	return true
+}
+
+// refill moves the overflow of the lane to the lane as far as it has room. It must be called while holding the bus
+// mutex.
+func (queue *topicQueue) refill(priority Priority) {
+	overflow := queue.overflows[priority]
+	moved := 0
+	for moved < len(overflow) && len(queue.lanes[priority]) < cap(queue.lanes[priority]) {
+		queue.lanes[priority] <- overflow[moved]
+		moved++
+	}
+	if moved == len(overflow) {
+		delete(queue.overflows, priority)
+	} else {
+		queue.overflows[priority] = overflow[moved:]
+	}
+}
+
+func (queue *topicQueue) overflowLength() int {
+	length := 0
+	for _, overflow := range queue.overflows {
+		length += len(overflow)
+	}
+	return length
+}
+func (queue *topicQueue) bufferLength() int {
+	length := 0
+	for _, lane := range queue.lanes {
+		length += len(lane)
+	}
+	return length
+}
+
+func (queue *topicQueue) bufferCapacity() int {
+	capacity := 0
+	for _, lane := range queue.lanes {
+		capacity += cap(lane)
+	}
+	return capacity
+}
+
+// pending returns the number of events of the topic which are either buffered, overflowing or being handled.
+func (queue *topicQueue) pending() int {
+	return queue.bufferLength() + queue.overflowLength() + queue.busyWorkers
+}
@@ -0,0 +1,107 @@
+package event_sourcing
+
+import (
+	"context"
+	"event-bus-demo/infrastructure/logging"
+	"fmt"
+	"go.uber.org/zap"
+	"reflect"
+	"sort"
+)
+
+type TypedEventHandler interface {
+	EventHandler
+	GetTopic() string
+	CanHandle(event Event) bool
+	GetHandledEvents() []string
+	register(eventType reflect.Type, eventName string, handle func(ctx context.Context, event Event) error) error
+}
+
+type typedHandlerEntry struct {
+	name   string
+	handle func(ctx context.Context, event Event) error
+}
+
+type typedEventHandler struct {
+	topic    string
+	logger   *zap.Logger
+	registry map[reflect.Type]typedHandlerEntry
+}
+
+func NewTypedEventHandler(topic string, logger *zap.Logger) TypedEventHandler {
+	return &typedEventHandler{
+		topic:    topic,
+		logger:   logger,
+		registry: make(map[reflect.Type]typedHandlerEntry),
+	}
+}
+
+// Handle registers handle as the function in charge of every event of concrete type E received by handler. The event
+// type must be a non-pointer type belonging to the handler topic and can only be registered once.
+func Handle[E Event](handler TypedEventHandler, handle func(ctx context.Context, event E) error) error {
+	var zero E
+	eventType := reflect.TypeOf(zero)
+	if eventType == nil || eventType.Kind() == reflect.Ptr {
+		return fmt.Errorf("event handlers must be registered for concrete value types, got %T", zero)
+	}
+	if zero.GetTopic() != handler.GetTopic() {
+		return fmt.Errorf("event %s belongs to topic %s and cannot be handled on topic %s",
+			zero.GetName(), zero.GetTopic(), handler.GetTopic())
+	}
+	return handler.register(eventType, zero.GetName(), func(ctx context.Context, event Event) error {
+		return handle(ctx, event.(E))
+	})
+}
+
+func (handler *typedEventHandler) GetTopic() string {
+	return handler.topic
+}
+
+func (handler *typedEventHandler) CanHandle(event Event) bool {
+	_, ok := handler.registry[reflect.TypeOf(event)]
+	return ok
+}
+
+func (handler *typedEventHandler) GetHandledEvents() []string {
+	names := make([]string, 0, len(handler.registry))
+	for _, entry := range handler.registry {
+		names = append(names, entry.name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (handler *typedEventHandler) register(eventType reflect.Type, eventName string, handle func(ctx context.Context, event Event) error) error {
+	if _, ok := handler.registry[eventType]; ok {
+		return fmt.Errorf("event %s already has a handler registered on topic %s", eventName, handler.topic)
+	}
+	handler.registry[eventType] = typedHandlerEntry{
+		name:   eventName,
+		handle: handle,
+	}
+	return nil
+}
+
+func (handler *typedEventHandler) Handle(ctx context.Context, event Event) EventResult {
+	entry, ok := handler.registry[reflect.TypeOf(event)]
+	if !ok {
+		return handler.newEventResult(ctx, event, fmt.Errorf("unknown event %s on topic %s", event.GetName(), handler.topic))
+	}
+	return handler.newEventResult(ctx, event, entry.handle(ctx, event))
+}
+
+func (handler *typedEventHandler) newEventResult(ctx context.Context, event Event, err error) EventResult {
+	if err != nil {
+		logging.FromContext(ctx, handler.logger).Error("error during event execution", zap.String("name", event.GetName()),
+			zap.String("error", err.Error()))
+		return EventResult{
+			Event:       event,
+			Error:       err,
+			FailureKind: ErrorFailure,
+		}
+	}
+	return EventResult{
+		Succeeded: true,
+		Event:     event,
+	}
+}
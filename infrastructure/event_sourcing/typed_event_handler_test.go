@@ -0,0 +1,132 @@
+package event_sourcing
+
+import (
+	"context"
+	"go.uber.org/zap"
+	"reflect"
+	"testing"
+)
+
+type otherTestEvent struct {
+	topic string
+}
+
+func (event otherTestEvent) GetTopic() string {
+	return event.topic
+}
+
+func (event otherTestEvent) GetName() string {
+	return "OtherTestEvent"
+}
+
+// topicAEvent belongs to topic A whatever its value, as Handle reads the topic of the zero value.
+type topicAEvent struct {
+	name string
+}
+
+func (topicAEvent) GetTopic() string {
+	return "A"
+}
+
+func (topicAEvent) GetName() string {
+	return "TopicAEvent"
+}
+
+type topicBEvent struct{}
+
+func (topicBEvent) GetTopic() string {
+	return "B"
+}
+
+func (topicBEvent) GetName() string {
+	return "TopicBEvent"
+}
+
+type pointerEvent struct{}
+
+func (*pointerEvent) GetTopic() string {
+	return "A"
+}
+
+func (*pointerEvent) GetName() string {
+	return "PointerEvent"
+}
+
+func ignore[E Event](context.Context, E) error {
+	return nil
+}
+
+func TestHandleRejectsInvalidRegistrations(t *testing.T) {
+	tests := []struct {
+		name     string
+		register func(handler TypedEventHandler) error
+		expected string
+	}{
+		{
+			name: "pointer event",
+			register: func(handler TypedEventHandler) error {
+				return Handle(handler, ignore[*pointerEvent])
+			},
+			expected: "event handlers must be registered for concrete value types, got *event_sourcing.pointerEvent",
+		},
+		{
+			name: "interface event",
+			register: func(handler TypedEventHandler) error {
+				return Handle(handler, ignore[Event])
+			},
+			expected: "event handlers must be registered for concrete value types, got <nil>",
+		},
+		{
+			name: "wrong topic",
+			register: func(handler TypedEventHandler) error {
+				return Handle(handler, ignore[topicBEvent])
+			},
+			expected: "event TopicBEvent belongs to topic B and cannot be handled on topic A",
+		},
+		{
+			name: "duplicate",
+			register: func(handler TypedEventHandler) error {
+				if err := Handle(handler, ignore[topicAEvent]); err != nil {
+					return err
+				}
+				return Handle(handler, ignore[topicAEvent])
+			},
+			expected: "event TopicAEvent already has a handler registered on topic A",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			handler := NewTypedEventHandler("A", zap.NewNop())
+			if err := test.register(handler); err == nil || err.Error() != test.expected {
+				t.Errorf("expected error %q, got %v", test.expected, err)
+			}
+		})
+	}
+}
+
+func TestTypedEventHandlerDispatchesByEventType(t *testing.T) {
+	handler := NewTypedEventHandler("A", zap.NewNop())
+	handled := make([]string, 0)
+	if err := Handle(handler, func(_ context.Context, event topicAEvent) error {
+		handled = append(handled, event.name)
+		return nil
+	}); err != nil {
+		t.Fatalf("unexpected error %s", err.Error())
+	}
+	if names := handler.GetHandledEvents(); !reflect.DeepEqual(names, []string{"TopicAEvent"}) {
+		t.Errorf("expected the registered event only, got %v", names)
+	}
+	if result := handler.Handle(context.Background(), topicAEvent{name: "first"}); !result.Succeeded {
+		t.Errorf("expected the event to be handled, got %+v", result)
+	}
+	if !reflect.DeepEqual(handled, []string{"first"}) {
+		t.Errorf("expected the handle function to receive the event, got %v", handled)
+	}
+	unknown := otherTestEvent{topic: "A"}
+	if handler.CanHandle(unknown) {
+		t.Error("expected an unregistered event not to be handled")
+	}
+	if result := handler.Handle(context.Background(), unknown); result.Succeeded || result.FailureKind != ErrorFailure {
+		t.Errorf("expected an unregistered event to fail, got %+v", result)
+	}
+}
@@ -0,0 +1,173 @@
+package health
+
+import (
+	"context"
+	"database/sql"
+	"event-bus-demo/infrastructure/event_sourcing"
+	"event-bus-demo/infrastructure/resilience"
+	"fmt"
+)
+
+type Status string
+
+const (
+	UpStatus   Status = "UP"
+	DownStatus Status = "DOWN"
+)
+
+type CheckResult struct {
+	Name    string
+	Status  Status
+	Details map[string]interface{}
+	Error   string
+}
+
+type Report struct {
+	Status Status
+	Checks []CheckResult
+}
+
+// Checker checks one dependency of the application.
+type Checker interface {
+	Check(ctx context.Context) CheckResult
+}
+
+// RunChecks runs every checker, the report is down as soon as one of the checks is down.
+func RunChecks(ctx context.Context, checkers ...Checker) Report {
+	report := Report{
+		Status: UpStatus,
+		Checks: make([]CheckResult, 0, len(checkers)),
+	}
+	for _, checker := range checkers {
+		result := checker.Check(ctx)
+		if result.Status != UpStatus {
+			report.Status = DownStatus
+		}
+		report.Checks = append(report.Checks, result)
+	}
+	return report
+}
+
+type databaseChecker struct {
+	db *sql.DB
+}
+
+func NewDatabaseChecker(db *sql.DB) Checker {
+	return &databaseChecker{
+		db: db,
+	}
+}
+
+func (checker *databaseChecker) Check(ctx context.Context) CheckResult {
+	stats := checker.db.Stats()
+	result := CheckResult{
+		Name:   "database",
+		Status: UpStatus,
+		Details: map[string]interface{}{
+			"openConnections": stats.OpenConnections,
+			"inUse":           stats.InUse,
+			"idle":            stats.Idle,
+		},
+	}
+	if err := checker.db.PingContext(ctx); err != nil {
+		result.Status = DownStatus
+		result.Error = err.Error()
+	}
+	return result
+}
+
+type eventBusChecker struct {
+	eventBus            event_sourcing.EventBus
+	saturationThreshold float64
+}
+
+// NewEventBusChecker reports the bus as down when it is not running or when the buffer of a topic is filled above
+// saturationThreshold, since new events of that topic are about to be dropped.
+func NewEventBusChecker(eventBus event_sourcing.EventBus, saturationThreshold float64) Checker {
+	return &eventBusChecker{
+		eventBus:            eventBus,
+		saturationThreshold: saturationThreshold,
+	}
+}
+
+func (checker *eventBusChecker) Check(context.Context) CheckResult {
+	running := checker.eventBus.IsRunning()
+	saturations := make(map[string]float64)
+	result := CheckResult{
+		Name:   "eventBus",
+		Status: UpStatus,
+		Details: map[string]interface{}{
+			"running":          running,
+			"queueSaturations": saturations,
+		},
+	}
+	if !running {
+		result.Status = DownStatus
+		result.Error = "event bus is not running"
+	}
+	for _, topic := range checker.eventBus.Stats().Topics {
+		if topic.BufferCapacity == 0 {
+			continue
+		}
+		saturation := float64(topic.BufferLength) / float64(topic.BufferCapacity)
+		saturations[topic.Name] = saturation
+		if saturation >= checker.saturationThreshold && result.Status == UpStatus {
+			result.Status = DownStatus
+			result.Error = fmt.Sprintf("queue of topic %s is saturated", topic.Name)
+		}
+	}
+	return result
+}
+
+type circuitBreakerChecker struct {
+	circuitBreakers []resilience.CircuitBreaker
+}
+
+// NewCircuitBreakerChecker reports the circuit breakers as down while one of them is open.
+func NewCircuitBreakerChecker(circuitBreakers []resilience.CircuitBreaker) Checker {
+	return &circuitBreakerChecker{
+		circuitBreakers: circuitBreakers,
+	}
+}
+
+func (checker *circuitBreakerChecker) Check(context.Context) CheckResult {
+	states := make(map[string]string)
+	result := CheckResult{
+		Name:   "circuitBreakers",
+		Status: UpStatus,
+		Details: map[string]interface{}{
+			"states": states,
+		},
+	}
+	for _, breaker := range checker.circuitBreakers {
+		snapshot := breaker.Snapshot()
+		states[snapshot.Name] = string(snapshot.State)
+		if snapshot.State == resilience.OpenState && result.Status == UpStatus {
+			result.Status = DownStatus
+			result.Error = fmt.Sprintf("circuit breaker %s is open", snapshot.Name)
+		}
+	}
+	return result
+}
+
+type configurationChecker struct {
+	profiles []string
+}
+
+// NewConfigurationChecker reports the configuration profiles which were loaded. Since the application does not start
+// with an invalid configuration, the check is always up.
+func NewConfigurationChecker(profiles []string) Checker {
+	return &configurationChecker{
+		profiles: profiles,
+	}
+}
+
+func (checker *configurationChecker) Check(context.Context) CheckResult {
+	return CheckResult{
+		Name:   "configuration",
+		Status: UpStatus,
+		Details: map[string]interface{}{
+			"profiles": checker.profiles,
+		},
+	}
+}
@@ -0,0 +1,37 @@
+package logging
+
+import (
+	"context"
+	"go.uber.org/zap"
+)
+
+type contextKey string
+
+const (
+	requestIDContextKey contextKey = "request_id"
+	loggerContextKey    contextKey = "logger"
+)
+
+const RequestIDField = "request_id"
+
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDContextKey, requestID)
+}
+
+func RequestIDFromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDContextKey).(string)
+	return requestID
+}
+
+func WithLogger(ctx context.Context, logger *zap.Logger) context.Context {
+	return context.WithValue(ctx, loggerContextKey, logger)
+}
+
+// FromContext returns the request scoped logger stored in ctx, falling back to the given logger when the context does
+// not carry one.
+func FromContext(ctx context.Context, fallback *zap.Logger) *zap.Logger {
+	if logger, ok := ctx.Value(loggerContextKey).(*zap.Logger); ok {
+		return logger
+	}
+	return fallback
+}
@@ -0,0 +1,41 @@
+package metrics
+
+import (
+	"event-bus-demo/infrastructure/resilience"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var circuitBreakerStateValues = map[resilience.CircuitBreakerState]float64{
+	resilience.ClosedState:   0,
+	resilience.HalfOpenState: 1,
+	resilience.OpenState:     2,
+}
+
+type circuitBreakerMetrics struct {
+	state       *prometheus.GaugeVec
+	transitions *prometheus.CounterVec
+}
+
+func NewCircuitBreakerMetrics(registerer prometheus.Registerer) resilience.CircuitBreakerObserver {
+	metrics := &circuitBreakerMetrics{
+		state: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "circuit_breaker",
+			Name:      "state",
+			Help:      "Current state of each circuit breaker: 0 closed, 1 half-open, 2 open.",
+		}, []string{"name"}),
+		transitions: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "circuit_breaker",
+			Name:      "transitions_total",
+			Help:      "Number of times each circuit breaker entered a state.",
+		}, []string{"name", "state"}),
+	}
+	registerer.MustRegister(metrics.state, metrics.transitions)
+	return metrics
+}
+
+func (metrics *circuitBreakerMetrics) StateChanged(name string, state resilience.CircuitBreakerState) {
+	metrics.state.WithLabelValues(name).Set(circuitBreakerStateValues[state])
+	metrics.transitions.WithLabelValues(name, string(state)).Inc()
+}
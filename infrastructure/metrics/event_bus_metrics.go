@@ -0,0 +1,152 @@
+package metrics
+
+import (
+	"event-bus-demo/infrastructure/event_sourcing"
+	"github.com/prometheus/client_golang/prometheus"
+	"time"
+)
+
+const (
+	handlerSucceededResult = "success"
+	handlerFailedResult    = "failure"
+	handlerTimeoutResult   = "timeout"
+	handlerRejectedResult  = "circuit_open"
+)
+
+type eventBusMetrics struct {
+	published       *prometheus.CounterVec
+	dropped         *prometheus.CounterVec
+	queueLength     *prometheus.GaugeVec
+	inFlight        *prometheus.GaugeVec
+	handlerDuration *prometheus.HistogramVec
+	handled         *prometheus.CounterVec
+	workersBusy     *prometheus.GaugeVec
+	workersCapacity *prometheus.GaugeVec
+	saturation      *prometheus.GaugeVec
+	abandoned       *prometheus.GaugeVec
+}
+
+func NewEventBusMetrics(registerer prometheus.Registerer) event_sourcing.EventBusMetrics {
+	metrics := &eventBusMetrics{
+		published: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "event_bus",
+			Name:      "events_published_total",
+			Help:      "Number of events published on the bus, including the ones dropped because the buffer was full.",
+		}, []string{"topic", "event"}),
+		dropped: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "event_bus",
+			Name:      "events_dropped_total",
+			Help:      "Number of events dropped because the bus buffer was full.",
+		}, []string{"topic", "event"}),
+		queueLength: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "event_bus",
+			Name:      "queue_length",
+			Help:      "Number of events waiting in the bus buffer.",
+		}, []string{"topic"}),
+		inFlight: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "event_bus",
+			Name:      "events_in_flight",
+			Help:      "Number of events currently being processed by a handler.",
+		}, []string{"topic"}),
+		handlerDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "event_bus",
+			Name:      "handler_duration_seconds",
+			Help:      "Time spent by handlers processing an event.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"topic", "event"}),
+		handled: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "event_bus",
+			Name:      "events_handled_total",
+			Help:      "Number of events processed by handlers, by result: success, failure, timeout or circuit_open.",
+		}, []string{"topic", "event", "result"}),
+		workersBusy: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "event_bus",
+			Name:      "workers_busy",
+			Help:      "Number of busy workers of each topic pool and of the global pool.",
+		}, []string{"pool"}),
+		workersCapacity: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "event_bus",
+			Name:      "workers_capacity",
+			Help:      "Maximum number of workers of each topic pool and of the global pool.",
+		}, []string{"pool"}),
+		saturation: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "event_bus",
+			Name:      "worker_pool_saturation_ratio",
+			Help:      "Ratio of busy workers to the capacity of each topic pool and of the global pool.",
+		}, []string{"pool"}),
+		abandoned: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "event_bus",
+			Name:      "abandoned_handlers",
+			Help:      "Number of handlers still running past their timeout and grace period, they keep a worker busy.",
+		}, []string{"topic"}),
+	}
+	registerer.MustRegister(
+		metrics.published,
+		metrics.dropped,
+		metrics.queueLength,
+		metrics.inFlight,
+		metrics.handlerDuration,
+		metrics.handled,
+		metrics.workersBusy,
+		metrics.workersCapacity,
+		metrics.saturation,
+		metrics.abandoned,
+	)
+	return metrics
+}
+
+func (metrics *eventBusMetrics) EventPublished(event event_sourcing.Event) {
+	metrics.published.WithLabelValues(event.GetTopic(), event.GetName()).Inc()
+	metrics.queueLength.WithLabelValues(event.GetTopic()).Inc()
+}
+
+func (metrics *eventBusMetrics) EventDropped(event event_sourcing.Event) {
+	metrics.dropped.WithLabelValues(event.GetTopic(), event.GetName()).Inc()
+	metrics.queueLength.WithLabelValues(event.GetTopic()).Dec()
+}
+
+func (metrics *eventBusMetrics) EventDequeued(event event_sourcing.Event) {
+	metrics.queueLength.WithLabelValues(event.GetTopic()).Dec()
+}
+
+func (metrics *eventBusMetrics) HandlerStarted(event event_sourcing.Event) {
+	metrics.inFlight.WithLabelValues(event.GetTopic()).Inc()
+}
+
+func (metrics *eventBusMetrics) HandlerFinished(event event_sourcing.Event, duration time.Duration, failureKind event_sourcing.FailureKind) {
+	metrics.inFlight.WithLabelValues(event.GetTopic()).Dec()
+	metrics.handlerDuration.WithLabelValues(event.GetTopic(), event.GetName()).Observe(duration.Seconds())
+	result := handlerSucceededResult
+	switch failureKind {
+	case event_sourcing.NoFailure:
+	case event_sourcing.TimeoutFailure:
+		result = handlerTimeoutResult
+	case event_sourcing.CircuitOpenFailure:
+		result = handlerRejectedResult
+	default:
+		result = handlerFailedResult
+	}
+	metrics.handled.WithLabelValues(event.GetTopic(), event.GetName(), result).Inc()
+}
+
+func (metrics *eventBusMetrics) WorkerPoolUsage(pool string, busy int, capacity int) {
+	metrics.workersBusy.WithLabelValues(pool).Set(float64(busy))
+	metrics.workersCapacity.WithLabelValues(pool).Set(float64(capacity))
+	if capacity > 0 {
+		metrics.saturation.WithLabelValues(pool).Set(float64(busy) / float64(capacity))
+	}
+}
+
+func (metrics *eventBusMetrics) AbandonedHandlers(topic string, count int) {
+	metrics.abandoned.WithLabelValues(topic).Set(float64(count))
+}
@@ -0,0 +1,41 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"strconv"
+	"time"
+)
+
+type HTTPMetrics interface {
+	ObserveRequest(method, route string, status int, duration time.Duration)
+}
+
+type httpMetrics struct {
+	requests        *prometheus.CounterVec
+	requestDuration *prometheus.HistogramVec
+}
+
+func NewHTTPMetrics(registerer prometheus.Registerer) HTTPMetrics {
+	metrics := &httpMetrics{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "requests_total",
+			Help:      "Number of HTTP requests served, by route and status code.",
+		}, []string{"method", "route", "status"}),
+		requestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "request_duration_seconds",
+			Help:      "Time spent serving HTTP requests, by route.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route"}),
+	}
+	registerer.MustRegister(metrics.requests, metrics.requestDuration)
+	return metrics
+}
+
+func (metrics *httpMetrics) ObserveRequest(method, route string, status int, duration time.Duration) {
+	metrics.requests.WithLabelValues(method, route, strconv.Itoa(status)).Inc()
+	metrics.requestDuration.WithLabelValues(method, route).Observe(duration.Seconds())
+}
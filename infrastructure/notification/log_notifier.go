@@ -0,0 +1,28 @@
+package notification
+
+import (
+	"context"
+	"event-bus-demo/infrastructure/logging"
+	"go.uber.org/zap"
+)
+
+type logNotifier struct {
+	logger *zap.Logger
+}
+
+// NewLogNotifier writes reminders to the application log, which is mostly useful while developing.
+func NewLogNotifier(logger *zap.Logger) Notifier {
+	return &logNotifier{
+		logger: logger,
+	}
+}
+
+func (notifier *logNotifier) GetName() string {
+	return LogNotifier
+}
+
+func (notifier *logNotifier) Send(ctx context.Context, reminder Reminder) error {
+	logging.FromContext(ctx, notifier.logger).Info("toDo reminder", zap.Stringer("id", reminder.ToDoID),
+		zap.Stringer("reminder", reminder.ID), zap.String("title", reminder.Title), zap.Time("dueAt", reminder.DueAt))
+	return nil
+}
@@ -0,0 +1,35 @@
+package notification
+
+import (
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"time"
+)
+
+const (
+	LogNotifier     = "log"
+	WebhookNotifier = "webhook"
+	SMTPNotifier    = "smtp"
+)
+
+// Reminder is a due ToDo reminder as handed to notifiers.
+type Reminder struct {
+	ID       uuid.UUID `json:"id"`
+	ToDoID   uuid.UUID `json:"toDoId"`
+	Title    string    `json:"title"`
+	DueAt    time.Time `json:"dueAt"`
+	RemindAt time.Time `json:"remindAt"`
+}
+
+// DeliveryID identifies the delivery of the reminder for its due date, so receivers can recognize a reminder they
+// already got.
+func (reminder Reminder) DeliveryID() string {
+	return fmt.Sprintf("%s-%d", reminder.ID, reminder.DueAt.Unix())
+}
+
+// Notifier delivers reminders through one channel, such as a webhook or an email.
+type Notifier interface {
+	GetName() string
+	Send(ctx context.Context, reminder Reminder) error
+}
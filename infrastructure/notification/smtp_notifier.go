@@ -0,0 +1,108 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SMTPOptions tells how to reach the mail server. The connection is upgraded with STARTTLS when the server offers it,
+// and authenticated only when a username is given.
+type SMTPOptions struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+	To       []string
+	Timeout  time.Duration
+}
+
+type smtpNotifier struct {
+	options SMTPOptions
+}
+
+// NewSMTPNotifier emails reminders to a fixed list of recipients.
+func NewSMTPNotifier(options SMTPOptions) Notifier {
+	return &smtpNotifier{
+		options: options,
+	}
+}
+
+func (notifier *smtpNotifier) GetName() string {
+	return SMTPNotifier
+}
+
+func (notifier *smtpNotifier) Send(ctx context.Context, reminder Reminder) error {
+	ctx, cancel := context.WithTimeout(ctx, notifier.options.Timeout)
+	defer cancel()
+	dialer := net.Dialer{}
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(notifier.options.Host, strconv.Itoa(notifier.options.Port)))
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := conn.SetDeadline(deadline); err != nil {
+			return err
+		}
+	}
+	client, err := smtp.NewClient(conn, notifier.options.Host)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: notifier.options.Host}); err != nil {
+			return err
+		}
+	}
+	if notifier.options.Username != "" {
+		auth := smtp.PlainAuth("", notifier.options.Username, notifier.options.Password, notifier.options.Host)
+		if err := client.Auth(auth); err != nil {
+			return err
+		}
+	}
+	if err := client.Mail(notifier.options.From); err != nil {
+		return err
+	}
+	for _, recipient := range notifier.options.To {
+		if err := client.Rcpt(recipient); err != nil {
+			return err
+		}
+	}
+	writer, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := writer.Write(notifier.newMessage(reminder)); err != nil {
+		return err
+	} else if err := writer.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+// newMessage writes the email of the reminder. Its Message-ID is derived from the delivery ID, so mail clients can
+// recognize a reminder they already got.
+func (notifier *smtpNotifier) newMessage(reminder Reminder) []byte {
+	// Line breaks in the title would end the header early
+	title := strings.NewReplacer("\r", " ", "\n", " ").Replace(reminder.Title)
+	var message bytes.Buffer
+	fmt.Fprintf(&message, "From: %s\r\n", notifier.options.From)
+	fmt.Fprintf(&message, "To: %s\r\n", strings.Join(notifier.options.To, ", "))
+	fmt.Fprintf(&message, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", "Reminder: "+title))
+	fmt.Fprintf(&message, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	fmt.Fprintf(&message, "Message-ID: <%s@%s>\r\n", reminder.DeliveryID(), notifier.options.Host)
+	fmt.Fprintf(&message, "MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&message, "Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	fmt.Fprintf(&message, "%s is due at %s.\r\n\r\nToDo ID: %s\r\n", title, reminder.DueAt.Format(time.RFC1123), reminder.ToDoID)
+	return message.Bytes()
+}
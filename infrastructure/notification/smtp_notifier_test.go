@@ -0,0 +1,198 @@
+package notification
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"mime"
+	"net"
+	"net/mail"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// smtpServer accepts a single SMTP session on a local port and records its commands and message. It offers no
+// extension, STARTTLS and AUTH included.
+type smtpServer struct {
+	listener net.Listener
+	done     chan struct{}
+	mutex    sync.Mutex
+	commands []string
+	message  string
+}
+
+func newSMTPServer(t *testing.T) *smtpServer {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unexpected error %s", err.Error())
+	}
+	server := &smtpServer{listener: listener, done: make(chan struct{})}
+	go server.serve()
+	t.Cleanup(func() {
+		_ = listener.Close()
+	})
+	return server
+}
+
+func (server *smtpServer) serve() {
+	defer close(server.done)
+	conn, err := server.listener.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(5 * time.Second))
+	reader := bufio.NewReader(conn)
+	reply := func(line string) {
+		_, _ = io.WriteString(conn, line+"\r\n")
+	}
+	reply("220 localhost ESMTP test")
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		command := strings.TrimRight(line, "\r\n")
+		server.mutex.Lock()
+		server.commands = append(server.commands, command)
+		server.mutex.Unlock()
+		verb := strings.ToUpper(strings.SplitN(command, " ", 2)[0])
+		switch {
+		case verb == "EHLO" || verb == "HELO":
+			reply("250 localhost")
+		case verb == "MAIL" || verb == "RCPT":
+			reply("250 OK")
+		case verb == "DATA":
+			reply("354 End data with <CR><LF>.<CR><LF>")
+			var message strings.Builder
+			for {
+				line, err := reader.ReadString('\n')
+				if err != nil {
+					return
+				} else if line == ".\r\n" {
+					break
+				}
+				message.WriteString(strings.TrimPrefix(line, "."))
+			}
+			server.mutex.Lock()
+			server.message = message.String()
+			server.mutex.Unlock()
+			reply("250 OK")
+		case verb == "QUIT":
+			reply("221 Bye")
+			return
+		default:
+			reply("502 Command not implemented")
+		}
+	}
+}
+
+// session waits for the session to end and returns its commands and message.
+func (server *smtpServer) session(t *testing.T) ([]string, string) {
+	t.Helper()
+	select {
+	case <-server.done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the SMTP session to end")
+	}
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	return server.commands, server.message
+}
+
+func (server *smtpServer) options() SMTPOptions {
+	address := server.listener.Addr().(*net.TCPAddr)
+	return SMTPOptions{
+		Host:    address.IP.String(),
+		Port:    address.Port,
+		From:    "todo@example.com",
+		To:      []string{"alice@example.com", "bob@example.com"},
+		Timeout: 5 * time.Second,
+	}
+}
+
+var testReminder = Reminder{
+	ID:       [16]byte{1},
+	ToDoID:   [16]byte{2},
+	Title:    "Water the plants",
+	DueAt:    time.Date(2026, time.October, 20, 9, 0, 0, 0, time.UTC),
+	RemindAt: time.Date(2026, time.October, 20, 8, 0, 0, 0, time.UTC),
+}
+
+func TestSMTPNotifierSendsReminderEmail(t *testing.T) {
+	server := newSMTPServer(t)
+	options := server.options()
+	if err := NewSMTPNotifier(options).Send(context.Background(), testReminder); err != nil {
+		t.Fatalf("unexpected error %s", err.Error())
+	}
+	commands, data := server.session(t)
+	expected := []string{"EHLO localhost", "MAIL FROM:<todo@example.com>", "RCPT TO:<alice@example.com>",
+		"RCPT TO:<bob@example.com>", "DATA", "QUIT"}
+	if !reflect.DeepEqual(commands, expected) {
+		t.Errorf("expected neither STARTTLS nor AUTH without server support nor username, got commands %q", commands)
+	}
+	message, err := mail.ReadMessage(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("expected a valid message, got %s", err.Error())
+	}
+	subject, err := new(mime.WordDecoder).DecodeHeader(message.Header.Get("Subject"))
+	if err != nil || subject != "Reminder: Water the plants" {
+		t.Errorf("expected the title in the subject, got %q", message.Header.Get("Subject"))
+	}
+	headers := map[string]string{
+		"From":         "todo@example.com",
+		"To":           "alice@example.com, bob@example.com",
+		"Message-ID":   "<" + testReminder.DeliveryID() + "@" + options.Host + ">",
+		"MIME-Version": "1.0",
+		"Content-Type": "text/plain; charset=utf-8",
+	}
+	for name, value := range headers {
+		if actual := message.Header.Get(name); actual != value {
+			t.Errorf("expected header %s to be %q, got %q", name, value, actual)
+		}
+	}
+	if _, err := message.Header.Date(); err != nil {
+		t.Errorf("expected a valid Date header, got %s", err.Error())
+	}
+	body, _ := io.ReadAll(message.Body)
+	expectedBody := "Water the plants is due at Tue, 20 Oct 2026 09:00:00 UTC.\r\n\r\nToDo ID: " +
+		testReminder.ToDoID.String() + "\r\n"
+	if string(body) != expectedBody {
+		t.Errorf("expected body %q, got %q", expectedBody, string(body))
+	}
+}
+
+func TestSMTPNotifierKeepsTitleInSubject(t *testing.T) {
+	server := newSMTPServer(t)
+	reminder := testReminder
+	reminder.Title = "Water the plants\r\nBcc: eve@example.com"
+	if err := NewSMTPNotifier(server.options()).Send(context.Background(), reminder); err != nil {
+		t.Fatalf("unexpected error %s", err.Error())
+	}
+	_, data := server.session(t)
+	message, err := mail.ReadMessage(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("expected a valid message, got %s", err.Error())
+	} else if bcc := message.Header.Get("Bcc"); bcc != "" {
+		t.Errorf("expected the title not to add headers, got Bcc %q", bcc)
+	}
+}
+
+func TestSMTPNotifierRequiresAuthWhenConfigured(t *testing.T) {
+	server := newSMTPServer(t)
+	options := server.options()
+	options.Username, options.Password = "todo", "secret"
+	if err := NewSMTPNotifier(options).Send(context.Background(), testReminder); err == nil {
+		t.Fatal("expected an error from a server not offering AUTH")
+	}
+	_ = server.listener.Close()
+	commands, _ := server.session(t)
+	for _, command := range commands {
+		if strings.HasPrefix(command, "MAIL") {
+			t.Errorf("expected no mail to be sent without authentication, got commands %q", commands)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// IdempotencyKeyHeader carries the delivery ID of the reminder posted to a webhook.
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+type webhookNotifier struct {
+	url    string
+	client *http.Client
+}
+
+// NewWebhookNotifier posts reminders as JSON to the given URL, any status other than 2xx failing the delivery.
+func NewWebhookNotifier(url string, timeout time.Duration) Notifier {
+	return &webhookNotifier{
+		url: url,
+		client: &http.Client{
+			Timeout: timeout,
+		},
+	}
+}
+
+func (notifier *webhookNotifier) GetName() string {
+	return WebhookNotifier
+}
+
+func (notifier *webhookNotifier) Send(ctx context.Context, reminder Reminder) error {
+	body, err := json.Marshal(reminder)
+	if err != nil {
+		return err
+	}
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, notifier.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set(IdempotencyKeyHeader, reminder.DeliveryID())
+	response, err := notifier.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return fmt.Errorf("webhook %s answered with status %d", notifier.url, response.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWebhookNotifierPostsReminder(t *testing.T) {
+	requests := make(chan *http.Request, 1)
+	var received Reminder
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		if err := json.NewDecoder(request.Body).Decode(&received); err != nil {
+			writer.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		requests <- request
+		writer.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+	notifier := NewWebhookNotifier(server.URL+"/reminders", time.Second)
+	if err := notifier.Send(context.Background(), testReminder); err != nil {
+		t.Fatalf("unexpected error %s", err.Error())
+	}
+	request := <-requests
+	if request.Method != http.MethodPost || request.URL.Path != "/reminders" {
+		t.Errorf("expected a POST to /reminders, got %s %s", request.Method, request.URL.Path)
+	}
+	if contentType := request.Header.Get("Content-Type"); contentType != "application/json" {
+		t.Errorf("expected a JSON body, got %s", contentType)
+	}
+	if key := request.Header.Get(IdempotencyKeyHeader); key != testReminder.DeliveryID() {
+		t.Errorf("expected the idempotency key %s, got %s", testReminder.DeliveryID(), key)
+	}
+	if received.ID != testReminder.ID || received.ToDoID != testReminder.ToDoID || received.Title != testReminder.Title ||
+		!received.DueAt.Equal(testReminder.DueAt) || !received.RemindAt.Equal(testReminder.RemindAt) {
+		t.Errorf("expected the reminder %+v, got %+v", testReminder, received)
+	}
+}
+
+func TestWebhookNotifierFailsOnErrorStatus(t *testing.T) {
+	for _, status := range []int{http.StatusMultipleChoices, http.StatusNotFound, http.StatusInternalServerError} {
+		server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, _ *http.Request) {
+			writer.WriteHeader(status)
+		}))
+		err := NewWebhookNotifier(server.URL, time.Second).Send(context.Background(), testReminder)
+		server.Close()
+		if err == nil || !strings.Contains(err.Error(), fmt.Sprintf("status %d", status)) {
+			t.Errorf("expected status %d to fail the delivery, got %v", status, err)
+		}
+	}
+}
+
+func TestWebhookNotifierGivesUpAfterTimeout(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+	start := time.Now()
+	if err := NewWebhookNotifier(server.URL, 50*time.Millisecond).Send(context.Background(), testReminder); err == nil {
+		t.Fatal("expected a webhook not answering in time to fail the delivery")
+	} else if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("expected the delivery to give up after its timeout, took %s", elapsed)
+	}
+}
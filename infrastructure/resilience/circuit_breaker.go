@@ -0,0 +1,163 @@
+package resilience
+
+import (
+	"go.uber.org/zap"
+	"sync"
+	"time"
+)
+
+type CircuitBreakerState string
+
+const (
+	ClosedState   CircuitBreakerState = "closed"
+	OpenState     CircuitBreakerState = "open"
+	HalfOpenState CircuitBreakerState = "half-open"
+)
+
+// CircuitBreaker stops calls to a failing dependency. It opens after FailureThreshold consecutive failures, rejects
+// every call for OpenTimeout and then lets up to HalfOpenProbes calls through to probe the dependency: the first
+// success closes it again while a failure opens it for another OpenTimeout.
+//
+// Every call allowed by Allow must be reported with either Success or Failure.
+type CircuitBreaker interface {
+	GetName() string
+	Allow() bool
+	Success()
+	Failure()
+	// Ready tells whether a call would be allowed right now, without taking a half-open probe.
+	Ready() bool
+	Snapshot() CircuitBreakerSnapshot
+}
+
+// CircuitBreakerOptions configures a circuit breaker. Now returns the current time and defaults to time.Now.
+type CircuitBreakerOptions struct {
+	FailureThreshold int
+	OpenTimeout      time.Duration
+	HalfOpenProbes   int
+	Now              func() time.Time
+}
+
+type CircuitBreakerSnapshot struct {
+	Name                string
+	State               CircuitBreakerState
+	ConsecutiveFailures int
+	OpenedAt            time.Time
+}
+
+// CircuitBreakerObserver is notified every time a circuit breaker changes its state.
+type CircuitBreakerObserver interface {
+	StateChanged(name string, state CircuitBreakerState)
+}
+
+type circuitBreaker struct {
+	name                string
+	options             CircuitBreakerOptions
+	observer            CircuitBreakerObserver
+	logger              *zap.Logger
+	mutex               sync.Mutex
+	state               CircuitBreakerState
+	consecutiveFailures int
+	openedAt            time.Time
+	probes              int
+}
+
+func NewCircuitBreaker(name string, options CircuitBreakerOptions, observer CircuitBreakerObserver, logger *zap.Logger) CircuitBreaker {
+	if options.Now == nil {
+		options.Now = time.Now
+	}
+	breaker := &circuitBreaker{
+		name:     name,
+		options:  options,
+		observer: observer,
+		logger:   logger,
+		state:    ClosedState,
+	}
+	observer.StateChanged(name, ClosedState)
+	return breaker
+}
+
+func (breaker *circuitBreaker) GetName() string {
+	return breaker.name
+}
+
+func (breaker *circuitBreaker) Allow() bool {
+	breaker.mutex.Lock()
+	defer breaker.mutex.Unlock()
+	switch breaker.state {
+	case ClosedState:
+		return true
+	case OpenState:
+		if breaker.options.Now().Sub(breaker.openedAt) < breaker.options.OpenTimeout {
+			return false
+		}
+		breaker.setState(HalfOpenState)
+		breaker.probes = 0
+	}
+	if breaker.probes >= breaker.options.HalfOpenProbes {
+		return false
+	}
+	breaker.probes++
+	return true
+}
+
+func (breaker *circuitBreaker) Ready() bool {
+	breaker.mutex.Lock()
+	defer breaker.mutex.Unlock()
+	switch breaker.state {
+	case ClosedState:
+		return true
+	case OpenState:
+		return breaker.options.Now().Sub(breaker.openedAt) >= breaker.options.OpenTimeout
+	default:
+		return breaker.probes < breaker.options.HalfOpenProbes
+	}
+}
+
+func (breaker *circuitBreaker) Success() {
+	breaker.mutex.Lock()
+	defer breaker.mutex.Unlock()
+	breaker.consecutiveFailures = 0
+	if breaker.state != ClosedState {
+		breaker.setState(ClosedState)
+	}
+}
+
+func (breaker *circuitBreaker) Failure() {
+	breaker.mutex.Lock()
+	defer breaker.mutex.Unlock()
+	breaker.consecutiveFailures++
+	if breaker.state == HalfOpenState ||
+		(breaker.state == ClosedState && breaker.consecutiveFailures >= breaker.options.FailureThreshold) {
+		breaker.openedAt = breaker.options.Now()
+		breaker.setState(OpenState)
+	}
+}
+
+func (breaker *circuitBreaker) Snapshot() CircuitBreakerSnapshot {
+	breaker.mutex.Lock()
+	defer breaker.mutex.Unlock()
+	return CircuitBreakerSnapshot{
+		Name:                breaker.name,
+		State:               breaker.state,
+		ConsecutiveFailures: breaker.consecutiveFailures,
+		OpenedAt:            breaker.openedAt,
+	}
+}
+
+// setState must be called while holding the breaker mutex.
+func (breaker *circuitBreaker) setState(state CircuitBreakerState) {
+	breaker.state = state
+	breaker.logger.Warn("circuit breaker state changed", zap.String("circuit_breaker", breaker.name),
+		zap.String("state", string(state)), zap.Int("consecutive_failures", breaker.consecutiveFailures))
+	breaker.observer.StateChanged(breaker.name, state)
+}
+
+type noOpCircuitBreakerObserver struct {
+}
+
+func NewNoOpCircuitBreakerObserver() CircuitBreakerObserver {
+	return &noOpCircuitBreakerObserver{}
+}
+
+func (observer *noOpCircuitBreakerObserver) StateChanged(string, CircuitBreakerState) {
+}
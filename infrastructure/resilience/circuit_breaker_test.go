@@ -0,0 +1,122 @@
+package resilience
+
+import (
+	"go.uber.org/zap"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func newFakeClock() *ManualClock {
+	return NewManualClock(time.Date(2022, time.January, 1, 0, 0, 0, 0, time.UTC))
+}
+
+type stateRecorder struct {
+	states []CircuitBreakerState
+}
+
+func (observer *stateRecorder) StateChanged(_ string, state CircuitBreakerState) {
+	observer.states = append(observer.states, state)
+}
+
+func newTestCircuitBreaker(clock *ManualClock, observer CircuitBreakerObserver) CircuitBreaker {
+	return NewCircuitBreaker("test", CircuitBreakerOptions{
+		FailureThreshold: 3,
+		OpenTimeout:      10 * time.Second,
+		HalfOpenProbes:   2,
+		Now:              clock.Now,
+	}, observer, zap.NewNop())
+}
+
+// fail reports count failed calls, every one of them having been allowed.
+func fail(t *testing.T, breaker CircuitBreaker, count int) {
+	t.Helper()
+	for i := 0; i < count; i++ {
+		if !breaker.Allow() {
+			t.Fatalf("expected call %d to be allowed", i+1)
+		}
+		breaker.Failure()
+	}
+}
+
+func TestCircuitBreakerOpensAfterConsecutiveFailures(t *testing.T) {
+	clock := newFakeClock()
+	breaker := newTestCircuitBreaker(clock, NewNoOpCircuitBreakerObserver())
+	fail(t, breaker, 2)
+	breaker.Allow()
+	breaker.Success()
+	fail(t, breaker, 2)
+	if state := breaker.Snapshot().State; state != ClosedState {
+		t.Fatalf("expected a success to reset the consecutive failures, got state %s", state)
+	}
+	fail(t, breaker, 1)
+	snapshot := breaker.Snapshot()
+	if snapshot.State != OpenState || snapshot.ConsecutiveFailures != 3 || !snapshot.OpenedAt.Equal(clock.Now()) {
+		t.Fatalf("expected the breaker to open on the third consecutive failure, got %+v", snapshot)
+	}
+	if breaker.Allow() || breaker.Ready() {
+		t.Error("expected an open breaker to reject calls")
+	}
+	clock.Advance(10*time.Second - time.Nanosecond)
+	if breaker.Allow() || breaker.Ready() {
+		t.Error("expected the breaker to reject calls until the open timeout is over")
+	}
+}
+
+func TestCircuitBreakerHalfOpenProbes(t *testing.T) {
+	tests := []struct {
+		name          string
+		probeSucceeds bool
+		expected      []CircuitBreakerState
+	}{
+		{
+			name:          "probe success closes",
+			probeSucceeds: true,
+			expected:      []CircuitBreakerState{ClosedState, OpenState, HalfOpenState, ClosedState},
+		},
+		{
+			name:          "probe failure opens again",
+			probeSucceeds: false,
+			expected:      []CircuitBreakerState{ClosedState, OpenState, HalfOpenState, OpenState},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			clock := newFakeClock()
+			observer := &stateRecorder{}
+			breaker := newTestCircuitBreaker(clock, observer)
+			fail(t, breaker, 3)
+			clock.Advance(10 * time.Second)
+			if !breaker.Ready() || breaker.Snapshot().State != OpenState {
+				t.Fatal("expected Ready to tell a probe is allowed without taking it")
+			}
+			if !breaker.Allow() || !breaker.Allow() {
+				t.Fatal("expected the breaker to allow two probes once the open timeout is over")
+			}
+			if breaker.Allow() || breaker.Ready() {
+				t.Fatal("expected the breaker to reject calls beyond its probes")
+			}
+			if state := breaker.Snapshot().State; state != HalfOpenState {
+				t.Fatalf("expected a half-open breaker, got %s", state)
+			}
+			if test.probeSucceeds {
+				breaker.Success()
+				if !breaker.Allow() || breaker.Snapshot().ConsecutiveFailures != 0 {
+					t.Error("expected a closed breaker to allow calls")
+				}
+			} else {
+				breaker.Failure()
+				if breaker.Allow() || !breaker.Snapshot().OpenedAt.Equal(clock.Now()) {
+					t.Error("expected the breaker to be opened again for another open timeout")
+				}
+				clock.Advance(10 * time.Second)
+				if !breaker.Allow() {
+					t.Error("expected a new probe once the second open timeout is over")
+				}
+			}
+			if !reflect.DeepEqual(observer.states[:len(test.expected)], test.expected) {
+				t.Errorf("expected states %v, got %v", test.expected, observer.states)
+			}
+		})
+	}
+}
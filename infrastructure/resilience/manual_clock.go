@@ -0,0 +1,29 @@
+package resilience
+
+import (
+	"sync"
+	"time"
+)
+
+// ManualClock is a clock which only moves when advanced, so tests control the time seen by circuit breakers through
+// CircuitBreakerOptions.Now.
+type ManualClock struct {
+	mutex sync.Mutex
+	now   time.Time
+}
+
+func NewManualClock(now time.Time) *ManualClock {
+	return &ManualClock{now: now}
+}
+
+func (clock *ManualClock) Now() time.Time {
+	clock.mutex.Lock()
+	defer clock.mutex.Unlock()
+	return clock.now
+}
+
+func (clock *ManualClock) Advance(duration time.Duration) {
+	clock.mutex.Lock()
+	clock.now = clock.now.Add(duration)
+	clock.mutex.Unlock()
+}
@@ -0,0 +1,232 @@
+package search
+
+import (
+	"context"
+	"event-bus-demo/domain/model"
+	"event-bus-demo/infrastructure/database/service"
+	infrastructure "event-bus-demo/infrastructure/error"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+const (
+	// titleWeight and descriptionWeight match the default weights Postgres gives to the A and B labels
+	titleWeight       = 1.0
+	descriptionWeight = 0.4
+	snippetWords      = 20
+	loadPageSize      = 100
+	highlightStart    = "<mark>"
+	highlightStop     = "</mark>"
+)
+
+type memoryToDoSearchIndex struct {
+	toDoDatabaseService service.ToDoDatabaseService
+	logger              *zap.Logger
+	mutex               sync.RWMutex
+	toDos               map[uuid.UUID]model.ToDo
+	// postings maps every word to the ToDos containing it
+	postings map[string]map[uuid.UUID]struct{}
+}
+
+// NewMemoryToDoSearchIndex keeps an in-process index of ToDos, for storages without text search. Every query word must
+// be found in a ToDo for it to match, and ToDos are ranked by how often the words appear, title words counting more.
+func NewMemoryToDoSearchIndex(toDoDatabaseService service.ToDoDatabaseService, logger *zap.Logger) ToDoSearchIndex {
+	return &memoryToDoSearchIndex{
+		toDoDatabaseService: toDoDatabaseService,
+		logger:              logger,
+		toDos:               make(map[uuid.UUID]model.ToDo),
+		postings:            make(map[string]map[uuid.UUID]struct{}),
+	}
+}
+
+func (index *memoryToDoSearchIndex) Load(ctx context.Context) infrastructure.InfrastructureError {
+	event := model.GetToDoListEvent{
+		SortField: model.SortToDoByCreatedAt,
+		Page:      model.ToDoPageRequest{Limit: loadPageSize},
+	}
+	count := 0
+	for {
+		page, err := index.toDoDatabaseService.GetToDoPage(ctx, event)
+		if err != nil {
+			return err
+		}
+		for _, toDo := range page.ToDos {
+			index.Index(toDo)
+		}
+		count += len(page.ToDos)
+		if !page.HasNext {
+			break
+		}
+		event.Page.Cursor = page.NextCursor
+	}
+	index.logger.Info("memory search index loaded", zap.Int("todos", count))
+	return nil
+}
+
+func (index *memoryToDoSearchIndex) Index(toDo model.ToDo) {
+	index.mutex.Lock()
+	defer index.mutex.Unlock()
+	index.remove(toDo.ID)
+	index.toDos[toDo.ID] = toDo
+	for _, word := range tokenize(toDo.Title + " " + toDo.Description) {
+		if index.postings[word] == nil {
+			index.postings[word] = make(map[uuid.UUID]struct{})
+		}
+		index.postings[word][toDo.ID] = struct{}{}
+	}
+}
+
+func (index *memoryToDoSearchIndex) Remove(ID uuid.UUID) {
+	index.mutex.Lock()
+	defer index.mutex.Unlock()
+	index.remove(ID)
+}
+
+// remove must be called while holding the write lock.
+func (index *memoryToDoSearchIndex) remove(ID uuid.UUID) {
+	toDo, ok := index.toDos[ID]
+	if !ok {
+		return
+	}
+	for _, word := range tokenize(toDo.Title + " " + toDo.Description) {
+		delete(index.postings[word], ID)
+		if len(index.postings[word]) == 0 {
+			delete(index.postings, word)
+		}
+	}
+	delete(index.toDos, ID)
+}
+
+func (index *memoryToDoSearchIndex) CategoryToDos(categoryID uuid.UUID) []uuid.UUID {
+	index.mutex.RLock()
+	defer index.mutex.RUnlock()
+	IDs := make([]uuid.UUID, 0)
+	for ID, toDo := range index.toDos {
+		for _, category := range toDo.Categories {
+			if category.ID == categoryID {
+				IDs = append(IDs, ID)
+				break
+			}
+		}
+	}
+	return IDs
+}
+
+func (index *memoryToDoSearchIndex) Search(_ context.Context, query string, limit int) ([]model.ToDoSearchHit, infrastructure.InfrastructureError) {
+	words := uniqueWords(tokenize(query))
+	if len(words) == 0 {
+		return make([]model.ToDoSearchHit, 0), nil
+	}
+	index.mutex.RLock()
+	defer index.mutex.RUnlock()
+	hits := make([]model.ToDoSearchHit, 0)
+	for ID := range index.postings[words[0]] {
+		toDo := index.toDos[ID]
+		matchesAll := true
+		for _, word := range words[1:] {
+			if _, ok := index.postings[word][ID]; !ok {
+				matchesAll = false
+				break
+			}
+		}
+		if matchesAll {
+			hits = append(hits, newSearchHit(toDo, words))
+		}
+	}
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Rank != hits[j].Rank {
+			return hits[i].Rank > hits[j].Rank
+		}
+		return hits[i].ToDo.ID.String() < hits[j].ToDo.ID.String()
+	})
+	if len(hits) > limit {
+		hits = hits[:limit]
+	}
+	return hits, nil
+}
+
+func newSearchHit(toDo model.ToDo, words []string) model.ToDoSearchHit {
+	matches := make(map[string]bool, len(words))
+	for _, word := range words {
+		matches[word] = true
+	}
+	rank := 0.0
+	for _, word := range tokenize(toDo.Title) {
+		if matches[word] {
+			rank += titleWeight
+		}
+	}
+	for _, word := range tokenize(toDo.Description) {
+		if matches[word] {
+			rank += descriptionWeight
+		}
+	}
+	return model.ToDoSearchHit{
+		ToDo:           toDo,
+		Rank:           rank,
+		TitleHighlight: strings.Join(highlight(strings.Fields(toDo.Title), matches), " "),
+		Snippet:        snippet(strings.Fields(toDo.Description), matches),
+	}
+}
+
+// snippet returns the words of the description around its first match, with the matches highlighted.
+func snippet(fields []string, matches map[string]bool) string {
+	start := 0
+	for i, field := range fields {
+		if isMatch(field, matches) {
+			start = i - snippetWords/4
+			break
+		}
+	}
+	if start < 0 {
+		start = 0
+	}
+	end := start + snippetWords
+	if end > len(fields) {
+		end = len(fields)
+	}
+	return strings.Join(highlight(fields[start:end], matches), " ")
+}
+
+func highlight(fields []string, matches map[string]bool) []string {
+	highlighted := make([]string, 0, len(fields))
+	for _, field := range fields {
+		if isMatch(field, matches) {
+			field = highlightStart + field + highlightStop
+		}
+		highlighted = append(highlighted, field)
+	}
+	return highlighted
+}
+
+func isMatch(field string, matches map[string]bool) bool {
+	for _, word := range tokenize(field) {
+		if matches[word] {
+			return true
+		}
+	}
+	return false
+}
+
+// tokenize splits text into lower case words made of letters and digits.
+func tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+func uniqueWords(words []string) []string {
+	seen := make(map[string]bool, len(words))
+	unique := make([]string, 0, len(words))
+	for _, word := range words {
+		if !seen[word] {
+			seen[word] = true
+			unique = append(unique, word)
+		}
+	}
+	return unique
+}
@@ -0,0 +1,188 @@
+package search
+
+import (
+	"context"
+	"event-bus-demo/domain/model"
+	"fmt"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func newTestIndex(toDos ...model.ToDo) ToDoSearchIndex {
+	index := NewMemoryToDoSearchIndex(nil, zap.NewNop())
+	for _, toDo := range toDos {
+		index.Index(toDo)
+	}
+	return index
+}
+
+func search(t *testing.T, index ToDoSearchIndex, query string) []model.ToDoSearchHit {
+	t.Helper()
+	hits, err := index.Search(context.Background(), query, 10)
+	if err != nil {
+		t.Fatalf("unexpected error %s", err.Error())
+	}
+	return hits
+}
+
+func hitTitles(hits []model.ToDoSearchHit) []string {
+	titles := make([]string, 0, len(hits))
+	for _, hit := range hits {
+		titles = append(titles, hit.ToDo.Title)
+	}
+	return titles
+}
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		text     string
+		expected []string
+	}{
+		{text: "Buy milk", expected: []string{"buy", "milk"}},
+		{text: "  Call Bob, at 5pm! ", expected: []string{"call", "bob", "at", "5pm"}},
+		{text: "e-mail the café", expected: []string{"e", "mail", "the", "café"}},
+		{text: "--- ...", expected: []string{}},
+	}
+	for _, test := range tests {
+		t.Run(test.text, func(t *testing.T) {
+			if actual := tokenize(test.text); !reflect.DeepEqual(actual, test.expected) {
+				t.Errorf("expected %q, got %q", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestMemoryToDoSearchIndexMatchesEveryWord(t *testing.T) {
+	index := newTestIndex(
+		model.ToDo{ID: uuid.New(), Title: "Buy milk", Description: "From the farm shop"},
+		model.ToDo{ID: uuid.New(), Title: "Buy bread"},
+		model.ToDo{ID: uuid.New(), Title: "Water the plants"},
+	)
+	tests := []struct {
+		query    string
+		expected []string
+	}{
+		{query: "buy", expected: []string{"Buy bread", "Buy milk"}},
+		{query: "BUY MILK", expected: []string{"Buy milk"}},
+		{query: "milk shop", expected: []string{"Buy milk"}},
+		{query: "buy plants", expected: []string{}},
+		{query: "bu", expected: []string{}},
+		{query: "!?", expected: []string{}},
+	}
+	for _, test := range tests {
+		t.Run(test.query, func(t *testing.T) {
+			// Hits of the same rank are sorted by ID, the titles are sorted to compare them
+			actual := hitTitles(search(t, index, test.query))
+			sort.Strings(actual)
+			if !reflect.DeepEqual(actual, test.expected) {
+				t.Errorf("expected %q, got %q", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestMemoryToDoSearchIndexRanksTitleMatchesFirst(t *testing.T) {
+	index := newTestIndex(
+		model.ToDo{ID: uuid.New(), Title: "Groceries", Description: "Milk and milk again"},
+		model.ToDo{ID: uuid.New(), Title: "Milk", Description: "Semi skimmed"},
+		model.ToDo{ID: uuid.New(), Title: "Milk the cow", Description: "Fresh milk"},
+		model.ToDo{ID: uuid.New(), Title: "Cheese", Description: "Made of milk"},
+	)
+	hits := search(t, index, "milk")
+	expected := []string{"Milk the cow", "Milk", "Groceries", "Cheese"}
+	if actual := hitTitles(hits); !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("expected %q, got %q", expected, actual)
+	}
+	ranks := []float64{titleWeight + descriptionWeight, titleWeight, 2 * descriptionWeight, descriptionWeight}
+	for i, hit := range hits {
+		if hit.Rank != ranks[i] {
+			t.Errorf("expected %s to have rank %f, got %f", hit.ToDo.Title, ranks[i], hit.Rank)
+		}
+	}
+	if hits[0].TitleHighlight != "<mark>Milk</mark> the cow" {
+		t.Errorf("expected the title match to be highlighted, got %q", hits[0].TitleHighlight)
+	}
+	limited, _ := index.Search(context.Background(), "milk", 2)
+	if actual := hitTitles(limited); !reflect.DeepEqual(actual, expected[:2]) {
+		t.Errorf("expected the best %q within the limit, got %q", expected[:2], actual)
+	}
+}
+
+func TestSnippetKeepsWordsAroundFirstMatch(t *testing.T) {
+	fields := make([]string, 0, 50)
+	for i := 0; i < 50; i++ {
+		fields = append(fields, fmt.Sprintf("w%d", i))
+	}
+	tests := []struct {
+		name     string
+		match    string
+		from, to int
+	}{
+		{name: "first word", match: "w0", from: 0, to: 20},
+		{name: "near the start", match: "w3", from: 0, to: 20},
+		{name: "middle", match: "w30", from: 25, to: 45},
+		{name: "near the end", match: "w48", from: 43, to: 50},
+		{name: "no match", match: "none", from: 0, to: 20},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual := strings.Fields(snippet(fields, map[string]bool{test.match: true}))
+			if len(actual) != test.to-test.from {
+				t.Fatalf("expected %d words, got %q", test.to-test.from, actual)
+			}
+			for i, word := range actual {
+				expected := fields[test.from+i]
+				if expected == test.match {
+					expected = highlightStart + expected + highlightStop
+				}
+				if word != expected {
+					t.Errorf("expected word %d to be %s, got %s", i, expected, word)
+				}
+			}
+		})
+	}
+	if actual := snippet(strings.Fields("Short description"), map[string]bool{"description": true}); actual !=
+		"Short <mark>description</mark>" {
+		t.Errorf("expected a short description to be kept whole, got %q", actual)
+	}
+}
+
+func TestMemoryToDoSearchIndexRemovesAndReplacesToDos(t *testing.T) {
+	toDo := model.ToDo{ID: uuid.New(), Title: "Buy milk"}
+	other := model.ToDo{ID: uuid.New(), Title: "Buy bread"}
+	index := newTestIndex(toDo, other)
+	toDo.Title = "Buy cheese"
+	index.Index(toDo)
+	if hits := search(t, index, "milk"); len(hits) != 0 {
+		t.Errorf("expected the former title not to match anymore, got %q", hitTitles(hits))
+	}
+	if hits := search(t, index, "cheese"); len(hits) != 1 {
+		t.Errorf("expected the new title to match, got %q", hitTitles(hits))
+	}
+	index.Remove(toDo.ID)
+	index.Remove(uuid.New())
+	if actual := hitTitles(search(t, index, "buy")); !reflect.DeepEqual(actual, []string{"Buy bread"}) {
+		t.Errorf("expected only the remaining ToDo to match, got %q", actual)
+	}
+	memoryIndex := index.(*memoryToDoSearchIndex)
+	if _, ok := memoryIndex.postings["cheese"]; ok {
+		t.Errorf("expected the words of removed ToDos to be dropped, got %v", memoryIndex.postings)
+	}
+}
+
+func TestMemoryToDoSearchIndexListsCategoryToDos(t *testing.T) {
+	category := model.Category{ID: uuid.New(), Name: "Shopping"}
+	toDo := model.ToDo{ID: uuid.New(), Title: "Buy milk",
+		Categories: []model.Category{{ID: uuid.New(), Name: "Home"}, category}}
+	index := newTestIndex(toDo, model.ToDo{ID: uuid.New(), Title: "Buy bread"})
+	if IDs := index.CategoryToDos(category.ID); !reflect.DeepEqual(IDs, []uuid.UUID{toDo.ID}) {
+		t.Errorf("expected the ToDo of the category, got %v", IDs)
+	}
+	if IDs := index.CategoryToDos(uuid.New()); len(IDs) != 0 {
+		t.Errorf("expected no ToDo for an unknown category, got %v", IDs)
+	}
+}
@@ -0,0 +1,55 @@
+package search
+
+import (
+	"context"
+	"event-bus-demo/domain/model"
+	"event-bus-demo/infrastructure/database/service"
+	infrastructure "event-bus-demo/infrastructure/error"
+	"github.com/google/uuid"
+)
+
+const (
+	PostgresBackend = "postgres"
+	MemoryBackend   = "memory"
+)
+
+// ToDoSearchIndex finds ToDos by the words of their title and description, best matches first.
+type ToDoSearchIndex interface {
+	Search(ctx context.Context, query string, limit int) ([]model.ToDoSearchHit, infrastructure.InfrastructureError)
+	// Load fills the index with the ToDos already stored, for indexes which do not live in the storage itself.
+	Load(ctx context.Context) infrastructure.InfrastructureError
+	Index(toDo model.ToDo)
+	Remove(ID uuid.UUID)
+	// CategoryToDos returns the indexed ToDos of the category, to index them again once the category changes.
+	CategoryToDos(categoryID uuid.UUID) []uuid.UUID
+}
+
+type postgresToDoSearchIndex struct {
+	toDoDatabaseService service.ToDoDatabaseService
+}
+
+// NewPostgresToDoSearchIndex searches the tsvector column of the ToDo table. Postgres keeps that column up to date on
+// its own, so Load, Index and Remove do nothing and no ToDo has to be indexed again.
+func NewPostgresToDoSearchIndex(toDoDatabaseService service.ToDoDatabaseService) ToDoSearchIndex {
+	return &postgresToDoSearchIndex{
+		toDoDatabaseService: toDoDatabaseService,
+	}
+}
+
+func (index *postgresToDoSearchIndex) Search(ctx context.Context, query string, limit int) ([]model.ToDoSearchHit, infrastructure.InfrastructureError) {
+	return index.toDoDatabaseService.SearchToDoText(ctx, query, limit)
+}
+
+func (index *postgresToDoSearchIndex) Load(context.Context) infrastructure.InfrastructureError {
+	return nil
+}
+
+func (index *postgresToDoSearchIndex) Index(model.ToDo) {
+}
+
+func (index *postgresToDoSearchIndex) Remove(uuid.UUID) {
+}
+
+func (index *postgresToDoSearchIndex) CategoryToDos(uuid.UUID) []uuid.UUID {
+	return nil
+}
@@ -0,0 +1,56 @@
+package tracing
+
+import (
+	"context"
+	"event-bus-demo/infrastructure/configuration"
+	"event-bus-demo/infrastructure/constants"
+	infrastructure "event-bus-demo/infrastructure/error"
+	"fmt"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"os"
+)
+
+// InitializeTracerProvider builds the tracer provider described by the given configuration and installs it, along with
+// the W3C trace context propagator, as the global OpenTelemetry provider.
+func InitializeTracerProvider(config configuration.TracingConfiguration) (*sdktrace.TracerProvider, infrastructure.InfrastructureError) {
+	exporter, err := newSpanExporter(config)
+	if err != nil {
+		return nil, infrastructure.NewTracingError(err.Error())
+	}
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(*config.SampleRatio))),
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL,
+			semconv.ServiceNameKey.String(*config.ServiceName))),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	return provider, nil
+}
+
+func newSpanExporter(config configuration.TracingConfiguration) (sdktrace.SpanExporter, error) {
+	switch *config.Exporter {
+	case constants.OTLPTracingExporter:
+		options := []otlptracehttp.Option{otlptracehttp.WithEndpoint(*config.Endpoint)}
+		if config.Insecure != nil && *config.Insecure {
+			options = append(options, otlptracehttp.WithInsecure())
+		}
+		return otlptracehttp.New(context.Background(), options...)
+	case constants.StdoutTracingExporter:
+		return stdouttrace.New(stdouttrace.WithPrettyPrint())
+	case constants.FileTracingExporter:
+		file, err := os.OpenFile(*config.FilePath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			return nil, err
+		}
+		return stdouttrace.New(stdouttrace.WithWriter(file))
+	default:
+		return nil, fmt.Errorf("unknown tracing exporter %s", *config.Exporter)
+	}
+}
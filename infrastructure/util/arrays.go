@@ -8,3 +8,21 @@ func Contains[T comparable](array []T, element T) bool {
 	}
 	return false
 }
+
+// IsPermutation tells whether permutation holds the elements of array in any order, each of them once.
+func IsPermutation[T comparable](array []T, permutation []T) bool {
+	if len(array) != len(permutation) {
+		return false
+	}
+	remaining := make(map[T]int, len(array))
+	for _, value := range array {
+		remaining[value]++
+	}
+	for _, value := range permutation {
+		if remaining[value] == 0 {
+			return false
+		}
+		remaining[value]--
+	}
+	return true
+}
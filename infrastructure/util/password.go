@@ -6,9 +6,13 @@ import (
 )
 
 func HashPassword(password string) (string, error.InfrastructureError) {
-	bytes, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MaxCost)
+	bytes, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
 	if err != nil {
 		return "", error.NewHashingError(err.Error())
 	}
 	return string(bytes), nil
 }
+
+func CheckPassword(hashedPassword, password string) bool {
+	return bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(password)) == nil
+}
@@ -1,7 +1,10 @@
 package main
 
 import (
+	"context"
 	"event-bus-demo/infrastructure/configuration"
+	"event-bus-demo/infrastructure/database/migration"
+	"event-bus-demo/infrastructure/event_sourcing"
 	"fmt"
 	"github.com/go-playground/validator/v10"
 	"log"
@@ -14,16 +17,60 @@ func main() {
 	if err != nil {
 		log.Fatalf("failed loading configuration due to %s", err.Error())
 	}
-	deps, err := initializeDependencies(config)
+	deps, err := initializeDependencies(arguments.ActiveConfigurationProfiles, config)
 	if err != nil {
 		log.Fatalf("failed while initializing dependencies due to %s", err.Error())
 	}
-	router := initializeRoutes(arguments.ActiveConfigurationProfiles, deps.RequiredControllers)
+	if arguments.EventGraphFormat != "" {
+		printEventBusGraph(deps.EventBus, arguments.EventGraphFormat)
+		return
+	}
+	if err := configuration.WaitForDatabase(deps.Database, *config.Rdbms.StartupProbe, deps.Logger); err != nil {
+		log.Fatalf("failed while waiting for the database due to %s", err.Error())
+	}
+	migrator, err := newMigrator(deps.Database, deps.Logger)
+	if err != nil {
+		log.Fatalf("failed loading database migrations due to %s", err.Error())
+	}
+	if len(arguments.Command) > 0 {
+		switch arguments.Command[0] {
+		case migrateCommand:
+			err = runMigrateCommand(migrator, arguments.Command[1:])
+		case repairCommand:
+			err = runRepairCommand(migration.NewIntegrityRepair(deps.Database, deps.Logger), arguments.Command[1:])
+		default:
+			log.Fatalf("unknown command %s, expected %s or %s", arguments.Command[0], migrateCommand, repairCommand)
+		}
+		if err != nil {
+			log.Fatalf("failed running %s command due to %s", arguments.Command[0], err.Error())
+		}
+		return
+	}
+	if *config.Rdbms.Migrations.RunOnStartup {
+		if err := migrator.Up(context.Background()); err != nil {
+			log.Fatalf("failed migrating the database due to %s", err.Error())
+		}
+	}
+	if err := deps.ToDoSearchIndex.Load(context.Background()); err != nil {
+		log.Fatalf("failed loading the search index due to %s", err.Error())
+	}
+	router := initializeRoutes(arguments.ActiveConfigurationProfiles, config, deps)
 	deps.EventBus.Run()
+	if deps.ToDoReminderScheduler != nil {
+		deps.ToDoReminderScheduler.Run()
+	}
 	_ = os.Setenv("PORT", fmt.Sprintf("%d", *config.Gin.Port))
 	_ = router.Run()
 }
 
+func printEventBusGraph(eventBus event_sourcing.EventBus, format string) {
+	graph, err := event_sourcing.RenderWiringGraph(eventBus.Describe(), format)
+	if err != nil {
+		log.Fatalf("failed rendering event bus graph due to %s", err.Error())
+	}
+	fmt.Print(graph)
+}
+
 func loadConfiguration(arguments configuration.Arguments) (configuration.ApplicationConfiguration, error) {
 	validate := validator.New()
 	yamlParser := configuration.NewYamlParser(validate)
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"event-bus-demo/infrastructure/database/migration"
+	infrastructure "event-bus-demo/infrastructure/error"
+	"fmt"
+	"go.uber.org/zap"
+	"io/fs"
+	"strconv"
+)
+
+const (
+	migrateCommand = "migrate"
+	repairCommand  = "repair"
+)
+
+//go:embed resources/db/migrations/*.sql
+var migrationFiles embed.FS
+
+func newMigrator(db *sql.DB, logger *zap.Logger) (migration.Migrator, infrastructure.InfrastructureError) {
+	migrationsRoot, err := fs.Sub(migrationFiles, "resources/db/migrations")
+	if err != nil {
+		return nil, infrastructure.NewParseFileError(err.Error())
+	}
+	migrations, loadErr := migration.LoadMigrations(migrationsRoot)
+	if loadErr != nil {
+		return nil, loadErr
+	}
+	return migration.NewMigrator(db, migrations, logger), nil
+}
+
+// runMigrateCommand handles `migrate up`, `migrate down [steps]` and `migrate status`, up being the default action.
+func runMigrateCommand(migrator migration.Migrator, arguments []string) infrastructure.InfrastructureError {
+	ctx := context.Background()
+	action := "up"
+	if len(arguments) > 0 {
+		action = arguments[0]
+	}
+	switch action {
+	case "up":
+		return migrator.Up(ctx)
+	case "down":
+		steps := 1
+		if len(arguments) > 1 {
+			parsed, err := strconv.Atoi(arguments[1])
+			if err != nil {
+				return infrastructure.NewInvalidArgumentError(fmt.Sprintf("invalid number of steps %s", arguments[1]))
+			}
+			steps = parsed
+		}
+		return migrator.Down(ctx, steps)
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		for _, status := range statuses {
+			appliedAt := "pending"
+			if status.Applied {
+				appliedAt = status.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%04d_%s\t%s\n", status.Version, status.Name, appliedAt)
+		}
+		return nil
+	default:
+		return infrastructure.NewInvalidArgumentError(fmt.Sprintf("unknown migrate action %s, expected up, down or status", action))
+	}
+}
+
+// runRepairCommand handles `repair`, which removes orphaned rows and validates the constraints they break, and
+// `repair check`, which only reports them.
+func runRepairCommand(repair migration.IntegrityRepair, arguments []string) infrastructure.InfrastructureError {
+	ctx := context.Background()
+	var reports []migration.IntegrityReport
+	var err infrastructure.InfrastructureError
+	if len(arguments) > 0 && arguments[0] == "check" {
+		reports, err = repair.Check(ctx)
+	} else if len(arguments) > 0 {
+		return infrastructure.NewInvalidArgumentError(fmt.Sprintf("unknown repair action %s, expected check", arguments[0]))
+	} else {
+		reports, err = repair.Repair(ctx)
+	}
+	if err != nil {
+		return err
+	}
+	for _, report := range reports {
+		fmt.Printf("%s\torphaned rows: %d\tvalidated: %t\n", report.Constraint, report.OrphanedRows, report.Validated)
+	}
+	return nil
+}
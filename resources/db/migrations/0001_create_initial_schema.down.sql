@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS USERS;
+DROP TABLE IF EXISTS TODO_CATEGORY;
+DROP TABLE IF EXISTS CATEGORIES;
+DROP TABLE IF EXISTS TODOS;
@@ -1,4 +1,4 @@
-CREATE TABLE TODOS (
+CREATE TABLE IF NOT EXISTS TODOS (
     ID UUID PRIMARY KEY,
     TITLE TEXT NOT NULL,
     DESCRIPTION TEXT NOT NULL,
@@ -6,21 +6,21 @@ CREATE TABLE TODOS (
     UPDATED_AT TIMESTAMP
 );
 
-CREATE TABLE CATEGORIES (
+CREATE TABLE IF NOT EXISTS CATEGORIES (
     ID UUID PRIMARY KEY,
     NAME TEXT NOT NULL
 );
 
-CREATE TABLE TODO_CATEGORY (
+CREATE TABLE IF NOT EXISTS TODO_CATEGORY (
     TODO_ID UUID,
     CATEGORY_ID UUID,
     PRIMARY KEY (TODO_ID, CATEGORY_ID)
 );
 
-CREATE TABLE USERS (
+CREATE TABLE IF NOT EXISTS USERS (
     ID UUID PRIMARY KEY,
     USERNAME TEXT UNIQUE NOT NULL,
     PASSWORD TEXT NOT NULL,
     ROLE TEXT NOT NULL DEFAULT 'USER',
     CONSTRAINT CHECK_ROLE CHECK ( ROLE IN ('USER', 'ADMIN') )
-);
\ No newline at end of file
+);
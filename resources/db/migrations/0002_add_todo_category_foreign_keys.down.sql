@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS IDX_TODO_CATEGORY_CATEGORY_ID;
+ALTER TABLE TODO_CATEGORY DROP CONSTRAINT IF EXISTS FK_TODO_CATEGORY_CATEGORY;
+ALTER TABLE TODO_CATEGORY DROP CONSTRAINT IF EXISTS FK_TODO_CATEGORY_TODO;
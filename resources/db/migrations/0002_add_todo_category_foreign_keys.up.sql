@@ -0,0 +1,9 @@
+-- Constraints are added as NOT VALID so databases holding orphaned rows can still be migrated, existing rows are
+-- checked once they are repaired with the repair command.
+ALTER TABLE TODO_CATEGORY
+    ADD CONSTRAINT FK_TODO_CATEGORY_TODO FOREIGN KEY (TODO_ID) REFERENCES TODOS (ID) ON DELETE CASCADE NOT VALID;
+
+ALTER TABLE TODO_CATEGORY
+    ADD CONSTRAINT FK_TODO_CATEGORY_CATEGORY FOREIGN KEY (CATEGORY_ID) REFERENCES CATEGORIES (ID) ON DELETE RESTRICT NOT VALID;
+
+CREATE INDEX IDX_TODO_CATEGORY_CATEGORY_ID ON TODO_CATEGORY (CATEGORY_ID);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS IDX_TODOS_SEARCH_VECTOR;
+DROP TRIGGER IF EXISTS TODOS_SEARCH_VECTOR ON TODOS;
+DROP FUNCTION IF EXISTS TODOS_SEARCH_VECTOR_UPDATE();
+ALTER TABLE TODOS DROP COLUMN IF EXISTS SEARCH_VECTOR;
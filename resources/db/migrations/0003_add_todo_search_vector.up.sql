@@ -0,0 +1,19 @@
+-- The search vector names its text search configuration, so it does not depend on the session writing the row. The
+-- search queries use the same configuration.
+ALTER TABLE TODOS ADD COLUMN SEARCH_VECTOR TSVECTOR;
+
+CREATE FUNCTION TODOS_SEARCH_VECTOR_UPDATE() RETURNS TRIGGER AS $$
+BEGIN
+    NEW.SEARCH_VECTOR := setweight(to_tsvector('english', NEW.TITLE), 'A') ||
+        setweight(to_tsvector('english', NEW.DESCRIPTION), 'B');
+    RETURN NEW;
+END
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER TODOS_SEARCH_VECTOR BEFORE INSERT OR UPDATE OF TITLE, DESCRIPTION ON TODOS
+    FOR EACH ROW EXECUTE FUNCTION TODOS_SEARCH_VECTOR_UPDATE();
+
+UPDATE TODOS SET SEARCH_VECTOR = setweight(to_tsvector('english', TITLE), 'A') ||
+    setweight(to_tsvector('english', DESCRIPTION), 'B');
+
+CREATE INDEX IDX_TODOS_SEARCH_VECTOR ON TODOS USING GIN (SEARCH_VECTOR);
@@ -0,0 +1,2 @@
+ALTER TABLE CATEGORIES DROP COLUMN IF EXISTS VERSION;
+ALTER TABLE TODOS DROP COLUMN IF EXISTS VERSION;
@@ -0,0 +1,5 @@
+-- Versions start at 1 and are incremented by every change, so clients can tell whether the row they read is still
+-- the current one.
+ALTER TABLE TODOS ADD COLUMN VERSION BIGINT NOT NULL DEFAULT 1;
+
+ALTER TABLE CATEGORIES ADD COLUMN VERSION BIGINT NOT NULL DEFAULT 1;
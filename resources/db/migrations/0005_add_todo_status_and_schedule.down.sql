@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS IDX_TODOS_UNFINISHED_DUE_AT;
+ALTER TABLE TODOS DROP COLUMN IF EXISTS COMPLETED_AT,
+    DROP COLUMN IF EXISTS DUE_AT,
+    DROP COLUMN IF EXISTS PRIORITY,
+    DROP COLUMN IF EXISTS STATUS;
@@ -0,0 +1,10 @@
+ALTER TABLE TODOS ADD COLUMN STATUS TEXT NOT NULL DEFAULT 'open',
+    ADD COLUMN PRIORITY TEXT NOT NULL DEFAULT 'medium',
+    ADD COLUMN DUE_AT TIMESTAMP,
+    ADD COLUMN COMPLETED_AT TIMESTAMP,
+    ADD CONSTRAINT CHECK_TODO_STATUS CHECK ( STATUS IN ('open', 'in-progress', 'done', 'cancelled') ),
+    ADD CONSTRAINT CHECK_TODO_PRIORITY CHECK ( PRIORITY IN ('low', 'medium', 'high') ),
+    ADD CONSTRAINT CHECK_TODO_COMPLETED_AT CHECK ( (STATUS = 'done') = (COMPLETED_AT IS NOT NULL) );
+
+-- Overdue and due soon filters only look at unfinished ToDos
+CREATE INDEX IDX_TODOS_UNFINISHED_DUE_AT ON TODOS (DUE_AT) WHERE STATUS IN ('open', 'in-progress');
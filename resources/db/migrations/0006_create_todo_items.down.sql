@@ -0,0 +1 @@
+DROP TABLE IF EXISTS TODO_ITEMS;
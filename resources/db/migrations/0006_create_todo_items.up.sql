@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS TODO_ITEMS (
+    ID UUID PRIMARY KEY,
+    TODO_ID UUID NOT NULL,
+    TITLE TEXT NOT NULL,
+    DONE BOOLEAN NOT NULL DEFAULT FALSE,
+    POSITION INTEGER NOT NULL,
+    CREATED_AT TIMESTAMP NOT NULL,
+    UPDATED_AT TIMESTAMP,
+    CONSTRAINT FK_TODO_ITEMS_TODO FOREIGN KEY (TODO_ID) REFERENCES TODOS (ID) ON DELETE CASCADE,
+    CONSTRAINT CHECK_TODO_ITEM_POSITION CHECK ( POSITION >= 0 )
+);
+
+-- Positions are not unique since shifting items would make them collide in the middle of an update
+CREATE INDEX IDX_TODO_ITEMS_TODO_ID_POSITION ON TODO_ITEMS (TODO_ID, POSITION);
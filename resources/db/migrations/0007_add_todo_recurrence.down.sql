@@ -0,0 +1,3 @@
+ALTER TABLE TODOS DROP COLUMN IF EXISTS OCCURRENCE,
+    DROP COLUMN IF EXISTS SERIES_ID,
+    DROP COLUMN IF EXISTS RECURRENCE;
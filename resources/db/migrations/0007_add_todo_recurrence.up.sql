@@ -0,0 +1,5 @@
+-- Occurrences of a recurring ToDo share the ID of the first one as series ID, which has none itself
+ALTER TABLE TODOS ADD COLUMN RECURRENCE TEXT,
+    ADD COLUMN SERIES_ID UUID,
+    ADD COLUMN OCCURRENCE INTEGER NOT NULL DEFAULT 0,
+    ADD CONSTRAINT CHECK_TODO_OCCURRENCE CHECK ( OCCURRENCE >= 0 );
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS TODO_REMINDERS (
+    ID UUID PRIMARY KEY,
+    TODO_ID UUID NOT NULL,
+    BEFORE_DUE_SECONDS BIGINT NOT NULL,
+    CREATED_AT TIMESTAMP NOT NULL,
+    -- The due date the reminder was last sent for, so it is sent once per due date even across restarts, and again
+    -- once the ToDo is rescheduled
+    SENT_DUE_AT TIMESTAMP,
+    SENT_AT TIMESTAMP,
+    CONSTRAINT FK_TODO_REMINDERS_TODO FOREIGN KEY (TODO_ID) REFERENCES TODOS (ID) ON DELETE CASCADE,
+    CONSTRAINT UNIQUE_TODO_REMINDER_BEFORE_DUE UNIQUE (TODO_ID, BEFORE_DUE_SECONDS),
+    CONSTRAINT CHECK_TODO_REMINDER_BEFORE_DUE CHECK ( BEFORE_DUE_SECONDS >= 0 )
+);
@@ -1,9 +1,68 @@
 -- name: GetToDoById :one
-SELECT * FROM todos WHERE id = $1;
--- name: GetToDoList :many
-SELECT * FROM todos;
+SELECT id, title, description, created_at, updated_at, version, status, priority, due_at, completed_at, recurrence, series_id, occurrence FROM todos WHERE id = $1;
+-- name: SearchToDoList :many
+SELECT id, title, description, created_at, updated_at, version, status, priority, due_at, completed_at, recurrence, series_id, occurrence,
+    sort_key::text FROM (
+    SELECT t.id, t.title, t.description, t.created_at, t.updated_at, t.version, t.status, t.priority, t.due_at, t.completed_at,
+        t.recurrence, t.series_id, t.occurrence,
+        CASE @sort_field::text
+            WHEN 'title' THEN t.title
+            WHEN 'updated_at' THEN to_char(COALESCE(t.updated_at, t.created_at), 'YYYY-MM-DD HH24:MI:SS.US')
+            ELSE to_char(t.created_at, 'YYYY-MM-DD HH24:MI:SS.US')
+        END AS sort_key
+    FROM todos t
+    WHERE (sqlc.narg(category_id)::uuid IS NULL OR EXISTS (
+            SELECT 1 FROM todo_category tc WHERE tc.todo_id = t.id AND tc.category_id = sqlc.narg(category_id)))
+        AND (sqlc.narg(created_from)::timestamp IS NULL OR t.created_at >= sqlc.narg(created_from))
+        AND (sqlc.narg(created_to)::timestamp IS NULL OR t.created_at < sqlc.narg(created_to))
+        AND (sqlc.narg(updated_from)::timestamp IS NULL OR COALESCE(t.updated_at, t.created_at) >= sqlc.narg(updated_from))
+        AND (sqlc.narg(updated_to)::timestamp IS NULL OR COALESCE(t.updated_at, t.created_at) < sqlc.narg(updated_to))
+        AND (sqlc.narg(title)::text IS NULL OR t.title ILIKE '%' || sqlc.narg(title) || '%' ESCAPE '\')
+        AND (sqlc.narg(status)::text IS NULL OR t.status = sqlc.narg(status))
+        AND (sqlc.narg(priority)::text IS NULL OR t.priority = sqlc.narg(priority))
+        AND (sqlc.narg(due_from)::timestamp IS NULL OR t.due_at >= sqlc.narg(due_from))
+        AND (sqlc.narg(due_to)::timestamp IS NULL OR t.due_at < sqlc.narg(due_to))
+        AND (NOT @unfinished::boolean OR t.status IN ('open', 'in-progress'))
+) AS filtered
+WHERE sqlc.narg(cursor_id)::uuid IS NULL
+    OR (@descending::boolean AND (sort_key, id) < (sqlc.narg(cursor_key)::text, sqlc.narg(cursor_id)))
+    OR (NOT @descending::boolean AND (sort_key, id) > (sqlc.narg(cursor_key)::text, sqlc.narg(cursor_id)))
+ORDER BY
+    CASE WHEN @descending::boolean THEN sort_key END DESC,
+    CASE WHEN @descending::boolean THEN id END DESC,
+    CASE WHEN NOT @descending::boolean THEN sort_key END ASC,
+    CASE WHEN NOT @descending::boolean THEN id END ASC
+LIMIT @page_limit OFFSET @page_offset;
 -- name: GetToDoCategories :many
-SELECT c.id, c.name FROM todo_category tc INNER JOIN categories c on c.id = tc.category_id WHERE todo_id = $1;
+SELECT c.id, c.name, c.version FROM todo_category tc INNER JOIN categories c on c.id = tc.category_id WHERE todo_id = $1;
+-- name: SearchToDoText :many
+SELECT t.id, t.title, t.description, t.created_at, t.updated_at, t.version, t.status, t.priority, t.due_at, t.completed_at,
+    t.recurrence, t.series_id, t.occurrence,
+    ts_rank_cd(t.search_vector, query)::real AS rank,
+    ts_headline(t.title, query, 'StartSel=<mark>, StopSel=</mark>, HighlightAll=true')::text AS title_highlight,
+    ts_headline(t.description, query, 'StartSel=<mark>, StopSel=</mark>, MaxFragments=2, MaxWords=20, MinWords=5')::text AS snippet
+FROM todos t, websearch_to_tsquery('english', @query::text) query
+WHERE t.search_vector @@ query
+ORDER BY rank DESC, t.id
+LIMIT @page_limit;
+-- name: GetToDoListCategories :many
+SELECT tc.todo_id, c.id, c.name FROM todo_category tc INNER JOIN categories c on c.id = tc.category_id WHERE tc.todo_id = ANY(@todo_ids::uuid[]);
+-- name: GetToDoItems :many
+SELECT * FROM todo_items WHERE todo_id = $1 ORDER BY position, id;
+-- name: GetToDoListItems :many
+SELECT * FROM todo_items WHERE todo_id = ANY(@todo_ids::uuid[]) ORDER BY todo_id, position, id;
+-- name: CountToDoItems :one
+SELECT COUNT(*) FROM todo_items WHERE todo_id = $1;
+-- name: GetToDoReminders :many
+SELECT * FROM todo_reminders WHERE todo_id = $1 ORDER BY before_due_seconds DESC;
+-- name: GetUpcomingToDoReminders :many
+SELECT r.id, r.todo_id, r.before_due_seconds, t.title, t.due_at::timestamp AS due_at,
+    (t.due_at - r.before_due_seconds * INTERVAL '1 second')::timestamp AS remind_at
+FROM todo_reminders r JOIN todos t ON t.id = r.todo_id
+WHERE t.status IN ('open', 'in-progress') AND t.due_at IS NOT NULL AND r.sent_due_at IS DISTINCT FROM t.due_at
+    AND t.due_at - r.before_due_seconds * INTERVAL '1 second' < @remind_before::timestamp
+ORDER BY remind_at, r.id
+LIMIT @page_limit;
 -- name: GetCategoryById :one
 SELECT * FROM categories WHERE id = $1;
 -- name: GetCategoriesList :many
@@ -13,23 +72,70 @@ SELECT * FROM users WHERE id = $1;
 -- name: GetUserByUsername :one
 SELECT * FROM users WHERE username = $1;
 -- name: CreateToDo :exec
-INSERT INTO todos (id, title, description, created_at) VALUES ($1, $2, $3, $4);
--- name: UpdateToDoInformation :exec
-UPDATE todos SET title = $2, description = $3, updated_at = $4 WHERE id = $1;
+INSERT INTO todos (id, title, description, created_at, priority, due_at, recurrence, series_id, occurrence)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9);
+-- name: UpdateToDoInformation :execrows
+UPDATE todos SET title = COALESCE(sqlc.narg(title), title), description = COALESCE(sqlc.narg(description), description),
+    priority = COALESCE(sqlc.narg(priority), priority),
+    recurrence = CASE WHEN @set_recurrence::boolean THEN sqlc.narg(recurrence) ELSE recurrence END,
+    updated_at = @updated_at, version = version + 1
+WHERE id = @id AND (sqlc.narg(expected_version)::bigint IS NULL OR version = sqlc.narg(expected_version));
+-- name: ChangeToDoStatus :execrows
+UPDATE todos SET status = @status, completed_at = sqlc.narg(completed_at), updated_at = @updated_at, version = version + 1
+WHERE id = @id AND status = ANY(@from_statuses::text[])
+    AND (sqlc.narg(expected_version)::bigint IS NULL OR version = sqlc.narg(expected_version));
+-- name: RescheduleToDo :execrows
+UPDATE todos SET due_at = sqlc.narg(due_at), updated_at = @updated_at, version = version + 1
+WHERE id = @id AND status = ANY(@from_statuses::text[])
+    AND (sqlc.narg(expected_version)::bigint IS NULL OR version = sqlc.narg(expected_version));
+-- name: BumpToDoVersion :execrows
+UPDATE todos SET version = version + 1
+WHERE id = $1 AND (sqlc.narg(expected_version)::bigint IS NULL OR version = sqlc.narg(expected_version));
 -- name: RemoveToDoFromCategory :exec
 DELETE FROM todo_category WHERE todo_id = $1 AND category_id = $2;
 -- name: RemoveAllCategoriesFromToDo :exec
 DELETE FROM todo_category WHERE todo_id = $1;
 -- name: AddToDoCategory :exec
 INSERT INTO todo_category (todo_id, category_id) VALUES ($1, $2);
--- name: DeleteToDo :exec
-DELETE FROM todos WHERE id = $1;
+-- name: CreateToDoItem :exec
+INSERT INTO todo_items (id, todo_id, title, position, created_at) VALUES ($1, $2, $3, $4, $5);
+-- name: UpdateToDoItem :execrows
+UPDATE todo_items SET title = COALESCE(sqlc.narg(title), title), done = COALESCE(sqlc.narg(done), done), updated_at = @updated_at
+WHERE id = @id AND todo_id = @todo_id;
+-- name: DeleteToDoItem :one
+DELETE FROM todo_items WHERE id = $1 AND todo_id = $2 RETURNING position;
+-- name: ShiftToDoItems :exec
+UPDATE todo_items SET position = position + @shift::integer WHERE todo_id = @todo_id AND position >= @from_position::integer;
+-- name: ReorderToDoItems :exec
+UPDATE todo_items SET position = array_position(@item_ids::uuid[], id) - 1 WHERE todo_id = @todo_id;
+-- name: CreateToDoReminder :exec
+INSERT INTO todo_reminders (id, todo_id, before_due_seconds, created_at) VALUES ($1, $2, $3, $4);
+-- name: DeleteToDoReminder :execrows
+DELETE FROM todo_reminders WHERE id = $1 AND todo_id = $2;
+-- name: MarkToDoReminderSent :execrows
+UPDATE todo_reminders SET sent_due_at = @due_at::timestamp, sent_at = @sent_at::timestamp
+WHERE id = @id AND sent_due_at IS DISTINCT FROM @due_at::timestamp
+    AND EXISTS (SELECT 1 FROM todos t WHERE t.id = todo_reminders.todo_id AND t.due_at = @due_at::timestamp
+        AND t.status IN ('open', 'in-progress'));
+-- name: DeleteToDo :execrows
+DELETE FROM todos WHERE id = $1 AND (sqlc.narg(expected_version)::bigint IS NULL OR version = sqlc.narg(expected_version));
 -- name: CreateCategory :exec
 INSERT INTO categories (id, name) VALUES ($1, $2);
--- name: UpdateCategoryName :exec
-UPDATE categories SET name = $2 WHERE id = $1;
--- name: DeleteCategory :exec
-DELETE FROM categories WHERE id = $1;
+-- name: UpdateCategoryName :execrows
+UPDATE categories SET name = $2, version = version + 1
+WHERE id = $1 AND (sqlc.narg(expected_version)::bigint IS NULL OR version = sqlc.narg(expected_version));
+-- name: DeleteCategory :execrows
+DELETE FROM categories WHERE id = $1 AND (sqlc.narg(expected_version)::bigint IS NULL OR version = sqlc.narg(expected_version));
+-- name: CountCategoryToDos :one
+SELECT COUNT(*) FROM todo_category WHERE category_id = $1;
+-- name: BumpCategoryToDoVersions :exec
+UPDATE todos SET version = version + 1 WHERE id IN (SELECT todo_id FROM todo_category WHERE category_id = $1);
+-- name: RemoveCategoryFromAllToDos :exec
+DELETE FROM todo_category WHERE category_id = $1;
+-- name: ReassignCategoryToDos :exec
+INSERT INTO todo_category (todo_id, category_id)
+SELECT tc.todo_id, @target_category_id::uuid FROM todo_category tc WHERE tc.category_id = @category_id
+ON CONFLICT DO NOTHING;
 -- name: CreateUser :exec
 INSERT INTO users (id, username, password, role) VALUES ($1, $2, $3, $4);
 -- name: UpdateUserPassword :exec
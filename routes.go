@@ -1,14 +1,33 @@
 package main
 
 import (
+	"event-bus-demo/application/middleware"
+	"event-bus-demo/infrastructure/configuration"
 	"event-bus-demo/infrastructure/util"
 	"github.com/gin-gonic/gin"
 	"net/http"
 )
 
-func initializeRoutes(profiles []string, controllers RequiredControllers) *gin.Engine {
-	router := gin.Default()
-	router.Use(gin.Recovery())
+func initializeRoutes(profiles []string, config configuration.ApplicationConfiguration, deps RequiredDependencies) *gin.Engine {
+	controllers := deps.RequiredControllers
+	router := gin.New()
+	router.Use(middleware.NewRequestIDMiddleware(deps.Logger))
+	router.Use(middleware.NewAccessLogMiddleware(*config.Gin.AccessLog, deps.Logger))
+	if *config.Metrics.Enabled {
+		router.Use(middleware.NewMetricsMiddleware(deps.HTTPMetrics))
+	}
+	if *config.Tracing.Enabled {
+		router.Use(middleware.NewTracingMiddleware(*config.Tracing.ServiceName))
+	}
+	router.Use(middleware.NewRecoveryMiddleware(deps.Logger))
+	if *config.Metrics.Enabled {
+		router.GET(*config.Metrics.Path, gin.WrapH(deps.MetricsHandler))
+	}
+	healthGroup := router.Group("/health")
+	{
+		healthGroup.GET("/live", controllers.HealthController.GetLiveness)
+		healthGroup.GET("/ready", controllers.HealthController.GetReadiness)
+	}
 	router.NoRoute(func(ctx *gin.Context) {
 		ctx.JSON(http.StatusNotFound, gin.H{
 			"message": "endpoint not found",
@@ -20,11 +39,27 @@ func initializeRoutes(profiles []string, controllers RequiredControllers) *gin.E
 		{
 			toDoGroup.GET("", controllers.ToDoController.GetToDoList)
 			toDoGroup.POST("", controllers.ToDoController.SaveToDo)
+			toDoGroup.GET("/search", controllers.ToDoController.SearchToDo)
 			toDoGroup.GET("/:id", controllers.ToDoController.GetToDoById)
 			toDoGroup.PUT("/:id", controllers.ToDoController.UpdateToDo)
+			toDoGroup.PATCH("/:id", controllers.ToDoController.PatchToDo)
 			toDoGroup.DELETE("/:id", controllers.ToDoController.DeleteToDo)
 			toDoGroup.PATCH("/:id/categories", controllers.ToDoController.AddCategoriesIntoToDo)
 			toDoGroup.DELETE("/:id/categories", controllers.ToDoController.RemoveCategoriesFromToDo)
+			toDoGroup.POST("/:id/complete", controllers.ToDoController.CompleteToDo)
+			toDoGroup.POST("/:id/reopen", controllers.ToDoController.ReopenToDo)
+			toDoGroup.POST("/:id/start", controllers.ToDoController.StartToDo)
+			toDoGroup.POST("/:id/cancel", controllers.ToDoController.CancelToDo)
+			toDoGroup.POST("/:id/reschedule", controllers.ToDoController.RescheduleToDo)
+			toDoGroup.GET("/:id/occurrences", controllers.ToDoController.GetToDoOccurrences)
+			toDoGroup.GET("/:id/items", controllers.ToDoController.GetToDoItems)
+			toDoGroup.POST("/:id/items", controllers.ToDoController.AddToDoItem)
+			toDoGroup.PUT("/:id/items/order", controllers.ToDoController.ReorderToDoItems)
+			toDoGroup.PATCH("/:id/items/:itemId", controllers.ToDoController.UpdateToDoItem)
+			toDoGroup.DELETE("/:id/items/:itemId", controllers.ToDoController.DeleteToDoItem)
+			toDoGroup.GET("/:id/reminders", controllers.ToDoController.GetToDoReminders)
+			toDoGroup.POST("/:id/reminders", controllers.ToDoController.AddToDoReminder)
+			toDoGroup.DELETE("/:id/reminders/:reminderId", controllers.ToDoController.DeleteToDoReminder)
 		}
 		categoryGroup := v1Group.Group("/category")
 		{
@@ -42,6 +77,16 @@ func initializeRoutes(profiles []string, controllers RequiredControllers) *gin.E
 				userGroup.PATCH("/:id", controllers.UserController.UpdateUserPassword)
 				userGroup.DELETE("/:id", controllers.UserController.DeleteUser)
 			}
+			// The admin API authenticates against the users, it is only available along with them
+			adminGroup := v1Group.Group("/admin", deps.AdminMiddleware)
+			{
+				adminGroup.GET("/bus", controllers.AdminController.GetEventBus)
+				adminGroup.POST("/bus/topics/:topic/pause", controllers.AdminController.PauseTopic)
+				adminGroup.POST("/bus/topics/:topic/resume", controllers.AdminController.ResumeTopic)
+				adminGroup.POST("/bus/topics/:topic/drain", controllers.AdminController.DrainTopic)
+				adminGroup.PUT("/bus/workers", controllers.AdminController.UpdateWorkers)
+				adminGroup.POST("/bus/events", controllers.AdminController.PublishEvent)
+			}
 		}
 	}
 	return router